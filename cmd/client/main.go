@@ -8,9 +8,14 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
+	"google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/genproto/googleapis/type/money"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
@@ -43,6 +48,7 @@ func main() {
 	defer conn.Close()
 
 	client := order_service.NewOrderServiceClient(conn)
+	cacheAdmin := order_service.NewCacheAdminServiceClient(conn)
 
 	scanner := bufio.NewScanner(os.Stdin)
 	fmt.Println("Enter the command (or 'exit' to exit):")
@@ -77,10 +83,18 @@ func main() {
 			err = Deliver(client, cmdArgs)
 		case "orders":
 			err = Orders(client, cmdArgs)
+		case "search":
+			err = Search(client, cmdArgs)
 		case "return":
 			err = Return(client, cmdArgs)
 		case "returns":
 			err = Returns(client, cmdArgs)
+		case "cache-stats":
+			err = CacheStats(cacheAdmin, cmdArgs)
+		case "cache-inspect":
+			err = CacheInspect(cacheAdmin, cmdArgs)
+		case "cache-flush":
+			err = CacheFlush(cacheAdmin, cmdArgs)
 		default:
 			fmt.Printf("Unknown command: %s\n", command)
 			continue
@@ -156,14 +170,19 @@ func Deliver(client order_service.OrderServiceClient, args []string) error {
 }
 
 func Orders(client order_service.OrderServiceClient, args []string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("usage: orders [recipientID]")
+	if len(args) < 1 || len(args) > 3 {
+		return fmt.Errorf("usage: orders [recipientID] [pageSize] [pageToken]")
 	}
 
-	recipientID := args[0]
+	pageSize, pageToken, err := parsePage(args[1:])
+	if err != nil {
+		return err
+	}
 
 	req := &order_service.GetOrdersRequest{
-		RecipientId: recipientID,
+		RecipientId: args[0],
+		PageSize:    pageSize,
+		PageToken:   pageToken,
 	}
 
 	res, err := client.GetOrders(context.Background(), req)
@@ -176,21 +195,142 @@ func Orders(client order_service.OrderServiceClient, args []string) error {
 	}
 
 	for _, order := range res.Orders {
-		fmt.Printf("Order ID: %s, Status: %s, Delivery Date: %s\n", order.OrderId, order.Status, order.DeliveryDate)
+		printOrder(order)
+	}
+	printNextPageToken(res.NextPageToken)
+
+	return nil
+}
+
+func Search(client order_service.OrderServiceClient, args []string) error {
+	const usage = "usage: search [recipient=ID] [status=new,delivered,returned] [packaging=bag,box,film] " +
+		"[expiry_from=YYYY-MM-DD] [expiry_to=YYYY-MM-DD] [prefix=orderIDPrefix] [in_storage] [page_size=N] [page_token=TOKEN]"
+
+	filter := &order_service.OrderFilter{}
+	req := &order_service.ListOrdersRequest{Filter: filter}
+
+	for _, arg := range args {
+		key, value, _ := strings.Cut(arg, "=")
+		switch key {
+		case "recipient":
+			filter.RecipientId = value
+		case "status":
+			for _, name := range strings.Split(value, ",") {
+				orderStatus, ok := order_service.OrderStatus_value["ORDER_STATUS_"+strings.ToUpper(name)]
+				if !ok {
+					return fmt.Errorf("invalid status %q\n%s", name, usage)
+				}
+				filter.Statuses = append(filter.Statuses, order_service.OrderStatus(orderStatus))
+			}
+		case "packaging":
+			for _, name := range strings.Split(value, ",") {
+				packagingType, ok := order_service.PackagingType_value["PACKAGING_TYPE_"+strings.ToUpper(name)]
+				if !ok {
+					return fmt.Errorf("invalid packaging %q\n%s", name, usage)
+				}
+				filter.PackagingTypes = append(filter.PackagingTypes, order_service.PackagingType(packagingType))
+			}
+		case "expiry_from", "expiry_to":
+			d, err := parseDate(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %v", key, err)
+			}
+			if key == "expiry_from" {
+				filter.ExpiryFrom = d
+			} else {
+				filter.ExpiryTo = d
+			}
+		case "prefix":
+			filter.OrderIdPrefix = value
+		case "in_storage":
+			filter.InStorage = true
+		case "page_size":
+			size, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid page size: %v", err)
+			}
+			req.PageSize = int32(size)
+		case "page_token":
+			req.PageToken = value
+		default:
+			return fmt.Errorf("%s", usage)
+		}
+	}
+
+	res, err := client.ListOrders(context.Background(), req)
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			return fmt.Errorf("ListOrders failed: %v", st.Message())
+		}
+		return err
+	}
+
+	for _, order := range res.Orders {
+		printOrder(order)
 	}
+	printNextPageToken(res.NextPageToken)
 
 	return nil
 }
 
+func parseDate(value string) (*date.Date, error) {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &date.Date{Year: int32(t.Year()), Month: int32(t.Month()), Day: int32(t.Day())}, nil
+}
+
+func printOrder(order *order_service.Order) {
+	fmt.Printf("Order ID: %s, Recipient ID: %s, Status: %s, Packaging: %s, Weight: %.2f kg, Cost: %s, Expiry Date: %s, Delivered At: %s, Returned At: %s\n",
+		order.OrderId,
+		order.RecipientId,
+		enumName(order.Status.String(), "ORDER_STATUS_"),
+		enumName(order.PackagingType.String(), "PACKAGING_TYPE_"),
+		order.Weight,
+		formatMoney(order.Cost),
+		formatDate(order.ExpiryDate),
+		formatTimestamp(order.DeliveredAt),
+		formatTimestamp(order.ReturnedAt),
+	)
+}
+
+func enumName(value, prefix string) string {
+	return strings.ToLower(strings.TrimPrefix(value, prefix))
+}
+
+func formatMoney(m *money.Money) string {
+	if m == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d.%02d %s", m.Units, m.Nanos/10_000_000, m.CurrencyCode)
+}
+
+func formatDate(d *date.Date) string {
+	if d == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+func formatTimestamp(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return "-"
+	}
+	return ts.AsTime().Local().Format("2006-01-02 15:04:05")
+}
+
 func Remove(client order_service.OrderServiceClient, args []string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("usage: remove [orderID]")
+	if len(args) < 1 {
+		return fmt.Errorf("usage: remove [orderID] [reason...]")
 	}
 
 	orderID := args[0]
 
 	req := &order_service.RemoveOrderRequest{
 		OrderId: orderID,
+		Reason:  strings.Join(args[1:], " "),
 	}
 
 	_, err := client.RemoveOrder(context.Background(), req)
@@ -233,17 +373,18 @@ func Return(client order_service.OrderServiceClient, args []string) error {
 }
 
 func Returns(client order_service.OrderServiceClient, args []string) error {
-	page := int32(1)
-	if len(args) >= 1 {
-		p, err := strconv.Atoi(args[0])
-		if err != nil {
-			return fmt.Errorf("invalid page number: %v", err)
-		}
-		page = int32(p)
+	if len(args) > 2 {
+		return fmt.Errorf("usage: returns [pageSize] [pageToken]")
+	}
+
+	pageSize, pageToken, err := parsePage(args)
+	if err != nil {
+		return err
 	}
 
 	req := &order_service.GetReturnsRequest{
-		Page: page,
+		PageSize:  pageSize,
+		PageToken: pageToken,
 	}
 
 	res, err := client.GetReturns(context.Background(), req)
@@ -258,6 +399,91 @@ func Returns(client order_service.OrderServiceClient, args []string) error {
 	for _, ret := range res.Returns {
 		fmt.Printf("Order ID: %s, Return Date: %s\n", ret.OrderId, ret.ReturnDate)
 	}
+	printNextPageToken(res.NextPageToken)
 
 	return nil
 }
+
+func CacheStats(client order_service.CacheAdminServiceClient, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: cache-stats")
+	}
+
+	stats, err := client.GetCacheStats(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			return fmt.Errorf("GetCacheStats failed: %v", st.Message())
+		}
+		return err
+	}
+
+	fmt.Printf("Size: %d, Cost: %d, Hits: %d, Misses: %d, Hit Rate: %.1f%%\n", stats.Size, stats.Cost, stats.Hits, stats.Misses, 100*stats.HitRate)
+	fmt.Printf("Evictions: capacity %d, ttl %d\n", stats.CapacityEvictions, stats.ExpiredEvictions)
+	fmt.Printf("Loads: %d, Average Load Time: %s\n", stats.Loads, stats.AverageLoadTime.AsDuration())
+	return nil
+}
+
+func CacheInspect(client order_service.CacheAdminServiceClient, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: cache-inspect [orderID]")
+	}
+
+	res, err := client.InspectCacheKey(context.Background(), &order_service.InspectCacheKeyRequest{OrderId: args[0]})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			return fmt.Errorf("InspectCacheKey failed: %v", st.Message())
+		}
+		return err
+	}
+
+	if !res.Found {
+		fmt.Println("Order is not cached")
+		return nil
+	}
+	printOrder(res.Order)
+	fmt.Printf("Cached until: %s\n", formatTimestamp(res.ExpiresAt))
+	return nil
+}
+
+func CacheFlush(client order_service.CacheAdminServiceClient, args []string) error {
+	_, err := client.FlushCache(context.Background(), &order_service.FlushCacheRequest{OrderIds: args})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			return fmt.Errorf("FlushCache failed: %v", st.Message())
+		}
+		return err
+	}
+
+	if len(args) == 0 {
+		fmt.Println("Cache flushed")
+	} else {
+		fmt.Printf("Removed %d key(s) from cache\n", len(args))
+	}
+	return nil
+}
+
+func parsePage(args []string) (int32, string, error) {
+	var pageSize int32
+	if len(args) >= 1 {
+		size, err := strconv.Atoi(args[0])
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid page size: %v", err)
+		}
+		pageSize = int32(size)
+	}
+
+	var pageToken string
+	if len(args) >= 2 {
+		pageToken = args[1]
+	}
+	return pageSize, pageToken, nil
+}
+
+func printNextPageToken(token string) {
+	if token != "" {
+		fmt.Printf("Next page token: %s\n", token)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/database"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/legacy"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/metrics"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/orders"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/postgres"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/returns"
+
+	"github.com/spf13/viper"
+)
+
+func initConfig() {
+	viper.SetConfigName("config")
+	viper.AddConfigPath("./config")
+	viper.AutomaticEnv()
+	if err := viper.ReadInConfig(); err != nil {
+		log.Fatalf("Error reading config file: %v", err)
+	}
+}
+
+// migrate-legacy переносит заказы и возвраты из файлов старого хранилища в PostgreSQL
+func main() {
+	ordersPath := flag.String("orders", "orders.json", "legacy orders file")
+	returnsPath := flag.String("returns", "returns.json", "legacy returns file")
+	dryRun := flag.Bool("dry-run", false, "validate records and report conflicts without writing anything")
+	batchSize := flag.Int("batch-size", 500, "records per transaction")
+	flag.Parse()
+
+	initConfig()
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	legacyOrders, err := orders.ReadOrders(*ordersPath)
+	if err != nil {
+		log.Fatalf("Failed to read legacy orders: %v", err)
+	}
+	legacyReturns, err := returns.ReadReturns(*returnsPath)
+	if err != nil {
+		log.Fatalf("Failed to read legacy returns: %v", err)
+	}
+
+	pool, err := database.ConnectPrimary(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer pool.Close()
+	db := pool.DB()
+	schema, err := database.NewMigrator(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := schema.CheckVersion(ctx); err != nil {
+		log.Fatalf("%v, run `server migrate up` before importing legacy data", err)
+	}
+
+	// Пачки только вставляют записи, поэтому уровня изоляции по умолчанию достаточно
+	txManager := postgres.NewTxManager(db, postgres.TxConfig{
+		MaxRetries:     viper.GetInt("database.tx.max_retries"),
+		RetryBaseDelay: viper.GetDuration("database.tx.retry_base_delay"),
+		RetryMaxDelay:  viper.GetDuration("database.tx.retry_max_delay"),
+	}, metrics.GetMetrics())
+	importer := legacy.NewImporter(postgres.NewLegacyImportRepository(db), txManager, *batchSize)
+	// Большая пачка пишется одним COPY, поэтому таймаут запросов из database.pool к импорту не применяется
+	report, err := importer.Import(postgres.WithoutQueryTimeout(ctx), legacyOrders, legacyReturns, *dryRun)
+	printReport(report)
+	if err != nil {
+		log.Fatalf("Migration stopped: %v", err)
+	}
+}
+
+func printReport(report *legacy.Report) {
+	for _, problem := range report.Problems {
+		fmt.Printf("%-8s %-6s %s: %s\n", problem.Kind, problem.Entity, problem.ID, problem.Reason)
+	}
+
+	verb := "imported"
+	if report.DryRun {
+		verb = "would import"
+	}
+	fmt.Printf("Orders: read %d, %s %d\n", report.OrdersRead, verb, report.OrdersImported)
+	fmt.Printf("Returns: read %d, %s %d\n", report.ReturnsRead, verb, report.ReturnsImported)
+	fmt.Printf("Problems: %d\n", len(report.Problems))
+}
@@ -2,21 +2,28 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/cache"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/controller"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/kafka"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/metrics"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/embedded"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/memory"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/postgres"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/server"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/tracer"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/usecase"
 
-	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/viper"
 )
 
@@ -29,60 +36,228 @@ func initConfig() {
 	}
 }
 
+// defaultReplicaID отличает реплики на разных хостах и несколько процессов на одном
+func defaultReplicaID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
 func main() {
+	storageFlag := flag.String("storage", "", "where orders are stored: postgres, memory or embedded, overrides storage.type from the config")
+	flag.Parse()
+
 	initConfig()
-	ctx, cancel := context.WithCancel(context.Background())
+	storage := viper.GetString("storage.type")
+	if *storageFlag != "" {
+		storage = *storageFlag
+	}
+	// Контекст отменяется по сигналу остановки, чтобы сервер успел сохранить снимок кэша и закрыть ресурсы
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	if flag.Arg(0) == "migrate" {
+		runMigrate(ctx, flag.Args()[1:])
+		return
+	}
+
 	tracer.SetupTracer(ctx, "order_service")
 
 	metricsInstance := metrics.GetMetrics()
 
-	connectionString := viper.GetString("database.dsn")
-	if connectionString == "" {
-		log.Fatal("Database connection string is not set in the config")
-	}
+	var (
+		orderRepo       interfaces.OrderRepository
+		returnRepo      interfaces.ReturnRepository
+		txManager       interfaces.TxManager
+		idempotencyRepo interfaces.IdempotencyRepository
+		archiveRepo     interfaces.ArchiveRepository
+		// Кэш заказов и Kafka нужны только с postgres. В остальных режимах кэша нет, а события о заказах отбрасываются
+		orderCache   interfaces.Cache[string, *domain.Order]
+		producer     kafka.Producer = kafka.NopProducer{}
+		snapshotPath string
+	)
+	switch storage {
+	case "postgres":
+		orderCache = newOrderCache()
+		defer orderCache.Close()
+		prometheus.MustRegister(metrics.NewCacheCollector("orders", orderCache.Stats))
 
-	db, err := sqlx.Connect("postgres", connectionString)
-	if err != nil {
-		log.Fatalf("Failed to connect to DB: %v", err)
-	}
+		kafkaProducer, err := kafka.NewProducer(viper.GetStringSlice("kafka.brokers"))
+		if err != nil {
+			log.Fatalf("Failed to create Kafka producer: %v", err)
+		}
+		defer kafkaProducer.Close()
+		producer = kafkaProducer
 
-	cacheConfig := cache.CacheConfig{
-		Strategy:        cache.LRUStrategy,
-		MaxEntries:      1000,
-		DefaultTTL:      5 * time.Minute,
-		CleanupInterval: 1 * time.Minute,
-	}
-	orderCache, err := cache.NewCache[string, *domain.Order](cacheConfig)
-	if err != nil {
-		log.Fatalf("Failed to create cache: %v", err)
-	}
-	defer cache.CloseCache(orderCache)
+		replicaID := viper.GetString("cache.invalidation.replica_id")
+		if replicaID == "" {
+			replicaID = defaultReplicaID()
+		}
+		invalidationTopic := viper.GetString("cache.invalidation.topic")
+		cacheInvalidator := kafka.NewCacheInvalidator(producer, invalidationTopic, replicaID, metricsInstance)
+		// Кэш только в Redis общий для всех реплик, и локальных копий, которые нужно сбрасывать, у них нет
+		if backend, _ := cache.ParseBackend(viper.GetString("cache.backend")); backend != cache.RemoteBackend {
+			if err := kafka.RunCacheInvalidationConsumer(ctx, viper.GetStringSlice("kafka.brokers"), invalidationTopic, replicaID, orderCache, metricsInstance); err != nil {
+				log.Fatalf("Failed to subscribe to cache invalidations: %v", err)
+			}
+		}
 
-	orderRepo := postgres.NewOrderRepository(db, orderCache)
-	returnRepo := postgres.NewReturnRepository(db)
+		primary := connectDB(ctx)
+		defer primary.Close()
+		db := primary.DB()
+		schema := newMigrator(db)
+		if viper.GetBool("database.auto_migrate") {
+			applied, err := schema.Up(postgres.WithoutQueryTimeout(ctx))
+			if err != nil {
+				log.Fatalf("Failed to migrate database: %v", err)
+			}
+			for _, migration := range applied {
+				log.Printf("Applied migration %s", migration.Name)
+			}
+		}
+		if err := schema.CheckVersion(ctx); err != nil {
+			log.Fatalf("%v, run `server migrate up` or enable database.auto_migrate", err)
+		}
+
+		cluster, replicas := newCluster(ctx, primary)
+		for _, replica := range replicas {
+			defer replica.Close()
+		}
+		go cluster.Run(ctx)
 
-	txManager := postgres.NewTxManager(db)
+		postgresOrderRepo := postgres.NewOrderRepository(cluster, orderCache, cacheInvalidator)
+		orderRepo = postgresOrderRepo
+		returnRepo = postgres.NewReturnRepository(cluster)
+		txManager = newTxManager(db)
+		idempotencyRepo = postgres.NewIdempotencyRepository(db, viper.GetDuration("idempotency.ttl"), viper.GetDuration("idempotency.lease"))
+		archiveRepo = postgres.NewArchiveRepository(cluster, orderCache, cacheInvalidator)
 
-	producer, err := kafka.NewProducer(viper.GetStringSlice("kafka.brokers"))
-	if err != nil {
-		log.Fatalf("Failed to create Kafka producer: %v", err)
+		snapshotPath = viper.GetString("cache.snapshot.path")
+		if snapshotPath != "" {
+			loaded, err := cache.LoadSnapshot(ctx, orderCache, snapshotPath, nil)
+			if err != nil {
+				log.Printf("Failed to load cache snapshot: %v", err)
+			} else {
+				log.Printf("Loaded %d orders from cache snapshot", loaded)
+			}
+		}
+		if viper.GetBool("cache.warm_up.enabled") {
+			loaded, err := postgresOrderRepo.WarmUpCache(ctx, viper.GetInt("cache.warm_up.limit"))
+			if err != nil {
+				log.Printf("Failed to warm up cache: %v", err)
+			} else {
+				log.Printf("Warmed up cache with %d orders in storage", loaded)
+			}
+		}
+	case "memory":
+		// Данные живут только в памяти процесса и теряются при остановке, кэш заказов не используется
+		log.Println("Using in-memory storage, all data will be lost on shutdown")
+		store := memory.NewStore()
+		orderRepo = memory.NewOrderRepository(store)
+		returnRepo = memory.NewReturnRepository(store)
+		txManager = memory.NewTxManager(store)
+		idempotencyRepo = memory.NewIdempotencyRepository(store, viper.GetDuration("idempotency.ttl"), viper.GetDuration("idempotency.lease"))
+		archiveRepo = memory.NewArchiveRepository(store)
+	case "embedded":
+		// Данные хранятся в одном файле рядом с сервером, кэш заказов не используется: чтение идёт из памяти отображённого файла
+		store, err := embedded.Open(viper.GetString("storage.embedded.path"), viper.GetDuration("storage.embedded.lock_timeout"))
+		if err != nil {
+			log.Fatalf("Failed to open embedded storage: %v", err)
+		}
+		defer store.Close()
+		orderRepo = embedded.NewOrderRepository(store)
+		returnRepo = embedded.NewReturnRepository(store)
+		txManager = embedded.NewTxManager(store)
+		idempotencyRepo = embedded.NewIdempotencyRepository(store, viper.GetDuration("idempotency.ttl"), viper.GetDuration("idempotency.lease"))
+		archiveRepo = embedded.NewArchiveRepository(store)
+	default:
+		log.Fatalf("Unknown storage %q, expected postgres, memory or embedded", storage)
 	}
-	defer producer.Close()
 
 	orderUseCase := usecase.NewOrderUseCase(orderRepo, returnRepo, txManager, metricsInstance)
 
+	archiveUseCase, err := usecase.NewArchiveUseCase(archiveRepo, viper.GetDuration("archive.retention"), viper.GetInt("archive.batch_size"))
+	if err != nil {
+		log.Fatalf("Invalid archive settings: %v", err)
+	}
+	if viper.GetBool("archive.enabled") {
+		go archiveUseCase.Run(ctx, viper.GetDuration("archive.interval"))
+	}
+
 	orderController := controller.NewOrderController(orderUseCase, producer, viper.GetString("kafka.topic"))
 
 	grpcServer := server.NewOrderServiceServer(orderController)
+	var cacheAdminServer *server.CacheAdminServer
+	if orderCache != nil {
+		cacheAdminServer = server.NewCacheAdminServer(orderCache)
+	}
+	archiveAdminServer := server.NewArchiveAdminServer(archiveUseCase)
 
 	go func() {
 		grpcAddress := viper.GetString("server.grpc_port")
-		if err := server.RunGRPCServer(ctx, grpcAddress, grpcServer, metricsInstance); err != nil {
+		if err := server.RunGRPCServer(ctx, grpcAddress, grpcServer, cacheAdminServer, archiveAdminServer, metricsInstance, idempotencyRepo,
+			viper.GetDuration("database.replicas.sticky_window")); err != nil {
 			log.Fatalf("Failed to run gRPC server: %v", err)
 		}
 	}()
 
+	go func() {
+		httpAddress := viper.GetString("server.http_port")
+		grpcAddress := viper.GetString("server.grpc_address")
+		if err := server.RunHTTPGateway(ctx, httpAddress, grpcAddress); err != nil {
+			log.Fatalf("Failed to run HTTP gateway: %v", err)
+		}
+	}()
+
 	<-ctx.Done()
+
+	if snapshotPath != "" {
+		saved, err := cache.SaveSnapshot(context.Background(), orderCache, snapshotPath, viper.GetInt("cache.snapshot.limit"), nil)
+		if err != nil {
+			log.Printf("Failed to save cache snapshot: %v", err)
+		} else {
+			log.Printf("Saved %d orders to cache snapshot", saved)
+		}
+	}
+}
+
+// newOrderCache создает кэш заказов по настройкам cache.*
+func newOrderCache() interfaces.Cache[string, *domain.Order] {
+	cacheStrategy, err := cache.ParseStrategy(viper.GetString("cache.strategy"))
+	if err != nil {
+		log.Fatalf("Invalid cache config: %v", err)
+	}
+	cacheBackend, err := cache.ParseBackend(viper.GetString("cache.backend"))
+	if err != nil {
+		log.Fatalf("Invalid cache config: %v", err)
+	}
+	cacheConfig := cache.CacheConfig{
+		Strategy:        cacheStrategy,
+		MaxEntries:      viper.GetInt("cache.max_entries"),
+		MaxCost:         int64(viper.GetSizeInBytes("cache.max_cost")),
+		DefaultTTL:      5 * time.Minute,
+		CleanupInterval: 1 * time.Minute,
+		Shards:          viper.GetInt("cache.shards"),
+		Backend:         cacheBackend,
+		Redis: cache.RedisConfig{
+			Addr:      viper.GetString("cache.redis.addr"),
+			Password:  viper.GetString("cache.redis.password"),
+			DB:        viper.GetInt("cache.redis.db"),
+			KeyPrefix: viper.GetString("cache.redis.key_prefix"),
+			TTL:       viper.GetDuration("cache.redis.ttl"),
+		},
+	}
+	orderCache, err := cache.NewCache[string, *domain.Order](cacheConfig,
+		cache.WithCloner((*domain.Order).Clone),
+		cache.WithNegativeCaching[*domain.Order](viper.GetDuration("cache.negative_ttl")),
+		cache.WithRefreshAhead[*domain.Order](viper.GetDuration("cache.refresh_ahead")),
+		cache.WithCost((*domain.Order).Size),
+		cache.WithTTLJitter[*domain.Order](viper.GetFloat64("cache.ttl_jitter")),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create cache: %v", err)
+	}
+	return orderCache
 }
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/database"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/metrics"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/migrator"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/postgres"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/viper"
+)
+
+const migrateUsage = "usage: server migrate up|down|status"
+
+// runMigrate выполняет подкоманду migrate: up применяет все новые миграции,
+// down откатывает последнюю, status показывает состояние каждой
+func runMigrate(ctx context.Context, args []string) {
+	if len(args) != 1 {
+		log.Fatal(migrateUsage)
+	}
+
+	pool := connectDB(ctx)
+	defer pool.Close()
+	// Миграция может долго перестраивать таблицу, поэтому таймаут запросов из database.pool к ней не применяется
+	ctx = postgres.WithoutQueryTimeout(ctx)
+	m := newMigrator(pool.DB())
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, migration := range applied {
+			log.Printf("Applied migration %s", migration.Name)
+		}
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		log.Printf("Schema is at version %d", m.Latest())
+	case "down":
+		rolledBack, err := m.Down(ctx)
+		if err != nil {
+			log.Fatalf("Rollback failed: %v", err)
+		}
+		if rolledBack == nil {
+			log.Println("No applied migrations to roll back")
+			return
+		}
+		log.Printf("Rolled back migration %s", rolledBack.Name)
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get migration status: %v", err)
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-20s %s\n", appliedAt, status.Name)
+		}
+	default:
+		log.Fatal(migrateUsage)
+	}
+}
+
+// connectDB открывает пул подключений к основной базе и проверяет, что она доступна
+func connectDB(ctx context.Context) *postgres.Pool {
+	pool, err := database.ConnectPrimary(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return pool
+}
+
+// newCluster направляет чтения на реплики из database.replicas и возвращает их пулы, чтобы закрыть их
+// при остановке. Недоступная реплика не мешает старту и не получает чтений, пока не пройдёт проверку
+func newCluster(ctx context.Context, primary *postgres.Pool) (*postgres.Cluster, []*postgres.Pool) {
+	var (
+		pools    []*postgres.Pool
+		replicas []*sqlx.DB
+	)
+	for i, dsn := range viper.GetStringSlice("database.replicas.dsns") {
+		pool, err := database.OpenPool(ctx, fmt.Sprintf("replica-%d", i+1), dsn)
+		if err != nil {
+			log.Fatal(err)
+		}
+		pools = append(pools, pool)
+		replicas = append(replicas, pool.DB())
+	}
+	cluster := postgres.NewCluster(primary.DB(), replicas, postgres.ClusterConfig{
+		StickyWindow:  viper.GetDuration("database.replicas.sticky_window"),
+		CheckInterval: viper.GetDuration("database.replicas.check_interval"),
+		MaxLag:        viper.GetDuration("database.replicas.max_lag"),
+	})
+	return cluster, pools
+}
+
+// newTxManager создает менеджер транзакций с настройками из database.tx
+func newTxManager(db *sqlx.DB) *postgres.TxManager {
+	isolation, err := postgres.ParseIsolationLevel(viper.GetString("database.tx.isolation"))
+	if err != nil {
+		log.Fatalf("Invalid database.tx.isolation: %v", err)
+	}
+	return postgres.NewTxManager(db, postgres.TxConfig{
+		Isolation:      isolation,
+		MaxRetries:     viper.GetInt("database.tx.max_retries"),
+		RetryBaseDelay: viper.GetDuration("database.tx.retry_base_delay"),
+		RetryMaxDelay:  viper.GetDuration("database.tx.retry_max_delay"),
+	}, metrics.GetMetrics())
+}
+
+func newMigrator(db *sqlx.DB) *migrator.Migrator {
+	m, err := database.NewMigrator(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return m
+}
@@ -4,18 +4,38 @@ Package cache предоставляет универсальный in-memory к
 Поддерживаемые стратегии:
 - LRU (Least Recently Used): Удаляет наименее недавно использованные элементы при достижении максимального размера.
 - LFU (Least Frequently Used): Удаляет наименее часто используемые элементы при достижении максимального размера.
+- W-TinyLFU: Адаптивная стратегия: новые элементы попадают в окно LRU и допускаются в основную часть, только если к ним обращаются чаще, чем к вытесняемым. Частоты периодически уменьшаются вдвое, поэтому устаревшие популярные ключи не занимают кэш навсегда.
 
 Конфигурация кэша:
-- Strategy: Выбор стратегии кэширования (LRUStrategy, LFUStrategy).
+- Strategy: Выбор стратегии кэширования (LRUStrategy, LFUStrategy, TinyLFUStrategy).
 - MaxEntries: Максимальное количество элементов в кэше.
+- MaxCost: Максимальная суммарная стоимость элементов, стоимость задаётся опцией WithCost.
 - DefaultTTL: Дефолтное время жизни элемента в кэше.
 - CleanupInterval: Интервал очистки просроченных элементов.
+- Shards: Количество независимо блокируемых сегментов (при значении больше 1 MaxEntries делится между ними).
+- Backend: Где хранятся значения: LocalBackend (в памяти процесса), RemoteBackend (Redis, общий для реплик) или TieredBackend (локальный кэш перед Redis).
+- Redis: Параметры подключения к удалённому кэшу для RemoteBackend и TieredBackend.
+
+Дополнительные опции:
+- WithCloner: Копирование значений при записи и чтении, чтобы вызывающие не делили один указатель.
+- WithNegativeCaching: Кэширование отсутствующих в источнике ключей для GetOrLoad.
+- WithRefreshAhead: Фоновое обновление элементов GetOrLoad незадолго до истечения TTL.
+- WithCodec: Формат значений в удалённом кэше (JSONCodec, ProtoCodec).
+- WithCost: Стоимость элемента для MaxCost, например, примерный размер в байтах.
+- WithTTLJitter: Случайное сокращение TTL, чтобы одновременно записанные элементы не истекали разом.
+
+Любой кэш закрывается через Close, поддерживает пакетные GetMany, SetMany, DeleteMany и подписку
+на удаление элементов через OnEvict.
+
+SaveSnapshot сохраняет самые востребованные элементы в файл, а LoadSnapshot восстанавливает их
+после перезапуска с оставшимся TTL.
 */
 package cache
 
 import (
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
@@ -27,25 +47,76 @@ type CacheStrategy int
 const (
 	LRUStrategy CacheStrategy = iota
 	LFUStrategy
+	TinyLFUStrategy
+)
+
+// CacheBackend определяет, где хранятся значения кэша
+type CacheBackend int
+
+const (
+	LocalBackend CacheBackend = iota
+	RemoteBackend
+	TieredBackend
 )
 
 // CacheConfig содержит конфигурационные параметры для кэша
 type CacheConfig struct {
-	Strategy        CacheStrategy
-	MaxEntries      int
+	Strategy   CacheStrategy
+	MaxEntries int
+	// MaxCost ограничивает суммарную стоимость элементов локального кэша, 0 — без ограничения
+	MaxCost         int64
 	DefaultTTL      time.Duration
 	CleanupInterval time.Duration
+	Shards          int
+	Backend         CacheBackend
+	Redis           RedisConfig
 }
 
 // NewCache создает новый экземпляр кэша в соответствии с заданной конфигурацией
 //
 // Возвращает интерфейс Cache и ошибку, если стратегия не поддерживается
-func NewCache[K comparable, V any](config CacheConfig) (interfaces.Cache[K, V], error) {
+func NewCache[K comparable, V any](config CacheConfig, opts ...Option[V]) (interfaces.Cache[K, V], error) {
+	switch config.Backend {
+	case LocalBackend:
+		return newLocalCache[K, V](config, opts...)
+	case RemoteBackend:
+		return newRemoteCacheFromConfig[K, V](config.Redis, config.DefaultTTL, opts...), nil
+	case TieredBackend:
+		local, err := newLocalCache[K, V](config, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &tieredCache[K, V]{
+			local:  local,
+			remote: newRemoteCacheFromConfig[K, V](config.Redis, config.DefaultTTL, opts...),
+		}, nil
+	default:
+		err := fmt.Errorf("unsupported cache backend: %v", config.Backend)
+		log.Println(err)
+		return nil, err
+	}
+}
+
+func newLocalCache[K comparable, V any](config CacheConfig, opts ...Option[V]) (interfaces.Cache[K, V], error) {
+	if config.MaxCost > 0 {
+		opts = append(opts[:len(opts):len(opts)], withMaxCost[V](config.MaxCost))
+	}
+
+	if config.Shards > 1 {
+		c, err := NewShardedCache[K, V](config.Strategy, config.Shards, config.MaxEntries, config.DefaultTTL, config.CleanupInterval, opts...)
+		if err != nil {
+			log.Println(err)
+		}
+		return c, err
+	}
+
 	switch config.Strategy {
 	case LRUStrategy:
-		return NewLRUCache[K, V](config.MaxEntries, config.DefaultTTL, config.CleanupInterval), nil
+		return NewLRUCache[K, V](config.MaxEntries, config.DefaultTTL, config.CleanupInterval, opts...), nil
 	case LFUStrategy:
-		return NewLFUCache[K, V](config.MaxEntries, config.DefaultTTL, config.CleanupInterval), nil
+		return NewLFUCache[K, V](config.MaxEntries, config.DefaultTTL, config.CleanupInterval, opts...), nil
+	case TinyLFUStrategy:
+		return NewTinyLFUCache[K, V](config.MaxEntries, config.DefaultTTL, config.CleanupInterval, opts...), nil
 	default:
 		err := fmt.Errorf("unsupported cache strategy: %v", config.Strategy)
 		log.Println(err)
@@ -53,16 +124,30 @@ func NewCache[K comparable, V any](config CacheConfig) (interfaces.Cache[K, V],
 	}
 }
 
-// CloseCache закрывает кэш и освобождает связанные с ним ресурсы
-//
-// Если кэш имеет метод Close, он будет вызван. Если тип кэша неизвестен, функция выведет предупреждение
-func CloseCache[K comparable, V any](c interfaces.Cache[K, V]) {
-	switch cacheInstance := c.(type) {
-	case *lruCache[K, V]:
-		cacheInstance.Close()
-	case *lfuCache[K, V]:
-		cacheInstance.Close()
+// ParseStrategy возвращает стратегию по её названию из конфигурации
+func ParseStrategy(name string) (CacheStrategy, error) {
+	switch strings.ToLower(name) {
+	case "lru", "":
+		return LRUStrategy, nil
+	case "lfu":
+		return LFUStrategy, nil
+	case "tinylfu", "w-tinylfu":
+		return TinyLFUStrategy, nil
+	default:
+		return 0, fmt.Errorf("unknown cache strategy: %q", name)
+	}
+}
+
+// ParseBackend возвращает бэкенд по его названию из конфигурации
+func ParseBackend(name string) (CacheBackend, error) {
+	switch strings.ToLower(name) {
+	case "local", "":
+		return LocalBackend, nil
+	case "redis", "remote":
+		return RemoteBackend, nil
+	case "tiered":
+		return TieredBackend, nil
 	default:
-		log.Printf("Warning: Cache instance of unknown type %T cannot be closed", cacheInstance)
+		return 0, fmt.Errorf("unknown cache backend: %q", name)
 	}
 }
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+)
+
+const benchKeys = 10000
+
+func benchmarkCaches(b *testing.B) map[string]interfaces.Cache[string, int] {
+	b.Helper()
+
+	caches := map[string]interfaces.Cache[string, int]{
+		"LRU": NewLRUCache[string, int](benchKeys, time.Minute, time.Minute),
+		"LFU": NewLFUCache[string, int](benchKeys, time.Minute, time.Minute),
+	}
+	for name, strategy := range map[string]CacheStrategy{"ShardedLRU": LRUStrategy, "ShardedLFU": LFUStrategy} {
+		c, err := NewShardedCache[string, int](strategy, 16, benchKeys, time.Minute, time.Minute)
+		if err != nil {
+			b.Fatal(err)
+		}
+		caches[name] = c
+	}
+	return caches
+}
+
+func benchmarkKeys() []string {
+	keys := make([]string, benchKeys)
+	for i := range keys {
+		keys[i] = "order-" + strconv.Itoa(i)
+	}
+	return keys
+}
+
+func BenchmarkCache_ParallelGet(b *testing.B) {
+	keys := benchmarkKeys()
+	ctx := context.Background()
+
+	for name, c := range benchmarkCaches(b) {
+		for i, key := range keys {
+			c.Set(ctx, key, i)
+		}
+
+		b.Run(name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					c.Get(ctx, keys[i%benchKeys])
+					i++
+				}
+			})
+		})
+		c.Close()
+	}
+}
+
+func BenchmarkCache_ParallelMixed(b *testing.B) {
+	keys := benchmarkKeys()
+	ctx := context.Background()
+
+	for name, c := range benchmarkCaches(b) {
+		b.Run(name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					key := keys[i%benchKeys]
+					if i%10 == 0 {
+						c.Set(ctx, key, i)
+					} else {
+						c.Get(ctx, key)
+					}
+					i++
+				}
+			})
+		})
+		c.Close()
+	}
+}
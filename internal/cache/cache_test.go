@@ -2,12 +2,14 @@ package cache
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log"
 	"sync"
 	"testing"
 	"time"
 
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
 
 	"github.com/stretchr/testify/assert"
@@ -20,20 +22,6 @@ func TestMain(m *testing.M) {
 	m.Run()
 }
 
-// Определяем mockCache, который реализует интерфейс Cache без метода Close
-type mockCache[K comparable, V any] struct{}
-
-func (m *mockCache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Duration) {}
-
-func (m *mockCache[K, V]) Get(ctx context.Context, key K) (V, bool) {
-	var zero V
-	return zero, false
-}
-
-func (m *mockCache[K, V]) Delete(ctx context.Context, key K) {}
-
-func (m *mockCache[K, V]) Flush(ctx context.Context) {}
-
 // функция для создания нового кэша LRU для тестирования
 func newTestLRUCache[K comparable, V any](maxEntries int, defaultTTL, cleanupInterval time.Duration) *lruCache[K, V] {
 	return NewLRUCache[K, V](maxEntries, defaultTTL, cleanupInterval).(*lruCache[K, V])
@@ -361,8 +349,264 @@ func TestCache_NewCacheInvalidStrategy(t *testing.T) {
 	assert.Error(t, err, "should return error for invalid cache strategy")
 }
 
-func TestCache_CloseCacheUnknownType(t *testing.T) {
-	var c interfaces.Cache[string, string] = &mockCache[string, string]{}
+// Регрессия: DeliverOrders меняет статус у заказа, полученного из кэша, и если UpdateOrder
+// падает, остальные читатели не должны увидеть "delivered"
+func TestCache_ClonerIsolatesReaders(t *testing.T) {
+	caches := map[string]interfaces.Cache[string, *domain.Order]{
+		"LRU": NewLRUCache[string, *domain.Order](10, 5*time.Second, 1*time.Second, WithCloner((*domain.Order).Clone)),
+		"LFU": NewLFUCache[string, *domain.Order](10, 5*time.Second, 1*time.Second, WithCloner((*domain.Order).Clone)),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			defer c.Close()
+
+			ctx := context.Background()
+
+			order := &domain.Order{OrderID: "order1", Status: domain.OrderStatusNew}
+			c.Set(ctx, order.OrderID, order)
+			order.Status = domain.OrderStatusReturned
+
+			got, found := c.Get(ctx, "order1")
+			require.True(t, found)
+			assert.Equal(t, domain.OrderStatusNew, got.Status, "mutation after Set must not leak into the cache")
+
+			got.Status = domain.OrderStatusDelivered
+
+			again, found := c.Get(ctx, "order1")
+			require.True(t, found)
+			assert.Equal(t, domain.OrderStatusNew, again.Status, "mutation after Get must not leak into the cache")
+			assert.NotSame(t, got, again)
+		})
+	}
+}
+
+func TestCache_Stats(t *testing.T) {
+	strategies := map[string]CacheStrategy{
+		"LRU":     LRUStrategy,
+		"LFU":     LFUStrategy,
+		"TinyLFU": TinyLFUStrategy,
+	}
+
+	for name, strategy := range strategies {
+		for _, shards := range []int{1, 4} {
+			t.Run(fmt.Sprintf("%s/shards=%d", name, shards), func(t *testing.T) {
+				c, err := NewCache[int, int](CacheConfig{
+					Strategy:        strategy,
+					MaxEntries:      200,
+					DefaultTTL:      time.Hour,
+					CleanupInterval: time.Hour,
+					Shards:          shards,
+				})
+				require.NoError(t, err)
+				defer c.Close()
+
+				ctx := context.Background()
+
+				for i := 0; i < 1000; i++ {
+					c.Set(ctx, i, i)
+				}
+				c.Set(ctx, -1, -1, 10*time.Millisecond)
+				time.Sleep(20 * time.Millisecond)
+
+				_, found := c.Get(ctx, -1)
+				assert.False(t, found)
+				c.Get(ctx, 999)
+				c.Get(ctx, 5000)
+				c.RecordLoad(30 * time.Millisecond)
+				c.RecordLoad(10 * time.Millisecond)
+
+				stats := c.Stats()
+				assert.Equal(t, uint64(1001-stats.CapacityEvictions-stats.ExpiredEvictions), uint64(stats.Size))
+				assert.NotZero(t, stats.CapacityEvictions)
+				assert.Equal(t, uint64(1), stats.ExpiredEvictions)
+				assert.Equal(t, stats.Hits+stats.Misses, uint64(3))
+				assert.GreaterOrEqual(t, stats.Misses, uint64(2))
+				assert.Equal(t, uint64(2), stats.Loads)
+				assert.Equal(t, 20*time.Millisecond, stats.AverageLoadTime())
+			})
+		}
+	}
+}
+
+func TestCache_InspectDoesNotTouchEntry(t *testing.T) {
+	c := newTestLRUCache[string, string](2, time.Hour, time.Hour)
+	defer c.Close()
+
+	ctx := context.Background()
+
+	c.Set(ctx, "key1", "value1")
+	c.Set(ctx, "key2", "value2")
+
+	entry, found := c.Inspect(ctx, "key1")
+	require.True(t, found)
+	assert.Equal(t, "value1", entry.Value)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), entry.ExpiresAt, time.Second)
+
+	// Inspect не поднимает key1 в начало списка, поэтому вытесняется именно он
+	c.Set(ctx, "key3", "value3")
+	_, found = c.Inspect(ctx, "key1")
+	assert.False(t, found)
+
+	stats := c.Stats()
+	assert.Zero(t, stats.Hits+stats.Misses)
+}
+
+func TestCache_OnEvict(t *testing.T) {
+	type evicted struct {
+		key    string
+		reason domain.EvictionReason
+	}
+
+	strategies := map[string]CacheStrategy{"LRU": LRUStrategy, "LFU": LFUStrategy, "TinyLFU": TinyLFUStrategy}
+	for name, strategy := range strategies {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewCache[string, int](CacheConfig{
+				Strategy:        strategy,
+				MaxEntries:      2,
+				DefaultTTL:      time.Minute,
+				CleanupInterval: time.Minute,
+			})
+			require.NoError(t, err)
+			defer c.Close()
+
+			var got []evicted
+			c.OnEvict(func(key string, value int, reason domain.EvictionReason) {
+				// Обработчик вызывается вне блокировки и может обращаться к кэшу
+				c.Inspect(context.Background(), key)
+				got = append(got, evicted{key: key, reason: reason})
+			})
+
+			ctx := context.Background()
+			for _, key := range []string{"a", "b", "c"} {
+				c.Set(ctx, key, 1)
+			}
+			require.Len(t, got, 1)
+			assert.Equal(t, domain.EvictionCapacity, got[0].reason)
+
+			survivor := "c"
+			if got[0].key == "c" {
+				survivor = "a"
+			}
+			c.Delete(ctx, survivor)
+			require.Len(t, got, 2)
+			assert.Equal(t, evicted{key: survivor, reason: domain.EvictionDeleted}, got[1])
+
+			c.Set(ctx, "d", 1, time.Millisecond)
+			time.Sleep(5 * time.Millisecond)
+			_, found := c.Get(ctx, "d")
+			assert.False(t, found)
+			require.Len(t, got, 3)
+			assert.Equal(t, evicted{key: "d", reason: domain.EvictionExpired}, got[2])
+		})
+	}
+}
+
+func TestCache_BatchOperations(t *testing.T) {
+	configs := map[string]CacheConfig{
+		"LRU":     {Strategy: LRUStrategy},
+		"LFU":     {Strategy: LFUStrategy},
+		"TinyLFU": {Strategy: TinyLFUStrategy},
+		"Sharded": {Strategy: LRUStrategy, Shards: 4},
+	}
+
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			config.MaxEntries = 100
+			config.DefaultTTL = time.Minute
+			config.CleanupInterval = time.Minute
+			c, err := NewCache[string, int](config)
+			require.NoError(t, err)
+			defer c.Close()
+
+			ctx := context.Background()
+			values := make(map[string]int)
+			for i := 0; i < 10; i++ {
+				values[fmt.Sprintf("key%d", i)] = i
+			}
+			c.SetMany(ctx, values)
+
+			assert.Equal(t, values, c.GetMany(ctx, []string{"key0", "key1", "key2", "key3", "key4", "key5", "key6", "key7", "key8", "key9", "missing"}))
+
+			c.DeleteMany(ctx, []string{"key0", "key5", "missing"})
+			got := c.GetMany(ctx, []string{"key0", "key1", "key5"})
+			assert.Equal(t, map[string]int{"key1": 1}, got)
+			assert.Equal(t, 8, c.Stats().Size)
+		})
+	}
+}
+
+func TestCache_MaxCost(t *testing.T) {
+	configs := map[string]CacheConfig{
+		"LRU":     {Strategy: LRUStrategy},
+		"LFU":     {Strategy: LFUStrategy},
+		"TinyLFU": {Strategy: TinyLFUStrategy},
+	}
+
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			config.MaxEntries = 100
+			config.MaxCost = 10
+			config.DefaultTTL = time.Minute
+			config.CleanupInterval = time.Minute
+			c, err := NewCache[string, string](config, WithCost(func(v string) int64 { return int64(len(v)) }))
+			require.NoError(t, err)
+			defer c.Close()
+
+			ctx := context.Background()
+			for i := 0; i < 10; i++ {
+				c.Set(ctx, fmt.Sprintf("key%d", i), "abcd")
+			}
+			stats := c.Stats()
+			assert.LessOrEqual(t, stats.Cost, int64(10))
+			assert.Equal(t, int64(stats.Size*4), stats.Cost)
+			assert.NotZero(t, stats.CapacityEvictions)
+
+			// Элемент дороже бюджета не сохраняется и удаляет прежнее значение ключа
+			c.Set(ctx, "small", "a")
+			c.Set(ctx, "small", "abcdefghijk")
+			_, found := c.Get(ctx, "small")
+			assert.False(t, found)
+			assert.LessOrEqual(t, c.Stats().Cost, int64(10))
+		})
+	}
+}
+
+func TestCache_MaxCostSharded(t *testing.T) {
+	c, err := NewCache[int, int](CacheConfig{
+		Strategy:        LRUStrategy,
+		MaxEntries:      1000,
+		MaxCost:         40,
+		Shards:          4,
+		DefaultTTL:      time.Minute,
+		CleanupInterval: time.Minute,
+	})
+	require.NoError(t, err)
+	defer c.Close()
 
-	CloseCache(c)
+	ctx := context.Background()
+	for i := 0; i < 1000; i++ {
+		c.Set(ctx, i, i)
+	}
+	// Без функции стоимости каждый элемент стоит 1, а бюджет делится между сегментами
+	stats := c.Stats()
+	assert.LessOrEqual(t, stats.Cost, int64(40))
+	assert.Equal(t, int64(stats.Size), stats.Cost)
+}
+
+func TestCache_TTLJitter(t *testing.T) {
+	c := NewLRUCache[int, int](1000, time.Hour, time.Minute, WithTTLJitter[int](0.5))
+	defer c.Close()
+
+	ctx := context.Background()
+	expiries := make(map[time.Time]struct{})
+	for i := 0; i < 100; i++ {
+		c.Set(ctx, i, i)
+		entry, found := c.Inspect(ctx, i)
+		require.True(t, found)
+		remaining := time.Until(entry.ExpiresAt)
+		assert.True(t, remaining > 30*time.Minute-time.Second && remaining <= time.Hour, "remaining TTL %v", remaining)
+		expiries[entry.ExpiresAt] = struct{}{}
+	}
+	assert.Greater(t, len(expiries), 1)
 }
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec переводит значения в байты для хранения в удалённом кэше
+type Codec[V any] interface {
+	Marshal(value V) ([]byte, error)
+	Unmarshal(data []byte) (V, error)
+}
+
+type jsonCodec[V any] struct{}
+
+// JSONCodec кодирует значения через encoding/json
+func JSONCodec[V any]() Codec[V] {
+	return jsonCodec[V]{}
+}
+
+func (jsonCodec[V]) Marshal(value V) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec[V]) Unmarshal(data []byte) (V, error) {
+	var value V
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+type protoCodec[T any, P interface {
+	*T
+	proto.Message
+}] struct{}
+
+// ProtoCodec кодирует protobuf-сообщения: ProtoCodec[order_service.Order]() работает с *order_service.Order
+func ProtoCodec[T any, P interface {
+	*T
+	proto.Message
+}]() Codec[P] {
+	return protoCodec[T, P]{}
+}
+
+func (protoCodec[T, P]) Marshal(value P) ([]byte, error) {
+	return proto.Marshal(value)
+}
+
+func (protoCodec[T, P]) Unmarshal(data []byte) (P, error) {
+	value := P(new(T))
+	err := proto.Unmarshal(data, value)
+	return value, err
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+)
+
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason domain.EvictionReason
+}
+
+// evictionQueue копит вытесненные элементы, пока кэш заблокирован, и передаёт их подписчикам
+// уже после снятия блокировки, чтобы обработчик мог обращаться к кэшу
+type evictionQueue[K comparable, V any] struct {
+	mu        sync.Mutex
+	listeners atomic.Pointer[[]func(K, V, domain.EvictionReason)]
+	// pending защищён блокировкой кэша, а не mu
+	pending []eviction[K, V]
+}
+
+// subscribe добавляет обработчик. Список копируется, поэтому notify читает его без блокировки
+func (q *evictionQueue[K, V]) subscribe(fn func(K, V, domain.EvictionReason)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var listeners []func(K, V, domain.EvictionReason)
+	if current := q.listeners.Load(); current != nil {
+		listeners = append(listeners, *current...)
+	}
+	listeners = append(listeners, fn)
+	q.listeners.Store(&listeners)
+}
+
+// add запоминает элемент, если есть подписчики. Вызывается под блокировкой кэша
+func (q *evictionQueue[K, V]) add(key K, value V, reason domain.EvictionReason) {
+	if q.listeners.Load() == nil {
+		return
+	}
+	q.pending = append(q.pending, eviction[K, V]{key: key, value: value, reason: reason})
+}
+
+// take забирает накопленные элементы. Вызывается под блокировкой кэша
+func (q *evictionQueue[K, V]) take() []eviction[K, V] {
+	pending := q.pending
+	q.pending = nil
+	return pending
+}
+
+// notify вызывает обработчики для элементов, полученных из take
+func (q *evictionQueue[K, V]) notify(evicted []eviction[K, V]) {
+	if len(evicted) == 0 {
+		return
+	}
+	listeners := q.listeners.Load()
+	for _, e := range evicted {
+		for _, fn := range *listeners {
+			fn(e.key, e.value, e.reason)
+		}
+	}
+}
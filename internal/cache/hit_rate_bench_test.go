@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"math/rand"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Трасса обращений к заказам. Записанную трассу (по одному order_id в строке) можно передать
+// через переменную окружения CACHE_TRACE, иначе используется синтетическая с похожим профилем
+const (
+	traceOrders    = 50000
+	traceLength    = 200000
+	traceCacheSize = 1000
+)
+
+func loadOrderTrace(b *testing.B) []string {
+	b.Helper()
+
+	if path := os.Getenv("CACHE_TRACE"); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			b.Fatalf("failed to open trace: %v", err)
+		}
+		defer file.Close()
+
+		var trace []string
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			trace = append(trace, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			b.Fatalf("failed to read trace: %v", err)
+		}
+		return trace
+	}
+
+	return syntheticOrderTrace()
+}
+
+// syntheticOrderTrace моделирует день ПВЗ: чаще всего запрашивают недавно принятые заказы,
+// их популярность убывает по Зипфу с возрастом заказа; часть обращений приходится на заказы
+// постоянных получателей, популярность которых не меняется; выгрузки списков заказов дают
+// длинные однократные проходы по редким ключам
+func syntheticOrderTrace() []string {
+	rnd := rand.New(rand.NewSource(256))
+	recent := rand.NewZipf(rnd, 1.1, 1, traceOrders-1)
+	regular := rand.NewZipf(rnd, 1.2, 1, traceOrders-1)
+
+	trace := make([]string, 0, traceLength)
+	for len(trace) < traceLength {
+		newest := len(trace) / 100
+		// Выгрузки занимают около 10% обращений, постоянные получатели — около четверти
+		switch p := rnd.Intn(10000); {
+		case p < 5:
+			start := rnd.Intn(traceOrders)
+			for i := 0; i < 200 && len(trace) < traceLength; i++ {
+				trace = append(trace, "order-"+strconv.Itoa((start+i)%traceOrders))
+			}
+		case p < 2800:
+			trace = append(trace, "regular-"+strconv.FormatUint(regular.Uint64(), 10))
+		default:
+			age := int(recent.Uint64())
+			trace = append(trace, "order-"+strconv.Itoa((newest-age+traceOrders)%traceOrders))
+		}
+	}
+	return trace
+}
+
+func BenchmarkCache_HitRate(b *testing.B) {
+	trace := loadOrderTrace(b)
+	ctx := context.Background()
+
+	strategies := map[string]CacheStrategy{
+		"LRU":     LRUStrategy,
+		"LFU":     LFUStrategy,
+		"TinyLFU": TinyLFUStrategy,
+	}
+
+	for name, strategy := range strategies {
+		b.Run(name, func(b *testing.B) {
+			var hits, total int
+			for n := 0; n < b.N; n++ {
+				c, err := NewCache[string, struct{}](CacheConfig{
+					Strategy:        strategy,
+					MaxEntries:      traceCacheSize,
+					DefaultTTL:      time.Hour,
+					CleanupInterval: time.Hour,
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				for _, key := range trace {
+					if _, found := c.Get(ctx, key); found {
+						hits++
+					} else {
+						c.Set(ctx, key, struct{}{})
+					}
+					total++
+				}
+				c.Close()
+			}
+			b.ReportMetric(100*float64(hits)/float64(total), "hit%")
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package cache
+
+import "time"
+
+const (
+	// sweepBatch — сколько элементов просматривается за одно удержание блокировки
+	sweepBatch = 64
+	// sweepRepeatRatio — если в пачке просрочено больше этой доли, очистка продолжается сразу
+	sweepRepeatRatio = 0.25
+)
+
+// janitor периодически вызывает очистку просроченных элементов в отдельной горутине
+type janitor struct {
+	ticker *time.Ticker
+	stop   chan struct{}
+}
+
+func startJanitor(interval time.Duration, sweep func()) *janitor {
+	j := &janitor{
+		ticker: time.NewTicker(interval),
+		stop:   make(chan struct{}),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-j.ticker.C:
+				sweep()
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+
+	return j
+}
+
+func (j *janitor) Stop() {
+	j.ticker.Stop()
+	close(j.stop)
+}
+
+// sweepIncrementally удаляет просроченные элементы небольшими пачками, отпуская блокировку
+// между ними, чтобы очистка не останавливала Get и Set на время обхода всего кэша.
+// sweep должен просмотреть не больше limit элементов и вернуть число просмотренных и удалённых
+func sweepIncrementally(sweep func(limit int) (scanned, removed int)) {
+	for {
+		scanned, removed := sweep(sweepBatch)
+		if scanned < sweepBatch || float64(removed) < float64(scanned)*sweepRepeatRatio {
+			return
+		}
+	}
+}
@@ -1,31 +1,39 @@
 package cache
 
 import (
+	"cmp"
 	"container/heap"
 	"context"
 	"log"
+	"slices"
 	"sync"
 	"time"
 
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
 )
 
 // lfuCache представляет LFU кэш
 type lfuCache[K comparable, V any] struct {
-	mu              sync.RWMutex
-	maxEntries      int
-	defaultTTL      time.Duration
-	cleanupInterval time.Duration
-	cache           map[K]*lfuEntry[K, V]
-	heap            *lfuHeap[K, V]
-	cleanupTicker   *time.Ticker
-	stopCleanup     chan struct{}
+	mu         sync.RWMutex
+	maxEntries int
+	defaultTTL time.Duration
+	// cost — суммарная стоимость элементов
+	cost      int64
+	cache     map[K]*lfuEntry[K, V]
+	heap      *lfuHeap[K, V]
+	janitor   *janitor
+	options   options[V]
+	loader    *loader[K, V]
+	stats     statsCounter
+	evictions evictionQueue[K, V]
 }
 
 // lfuEntry представляет элемент в LFU кэше
 type lfuEntry[K comparable, V any] struct {
 	key       K
 	value     V
+	cost      int64
 	frequency int
 	expiry    time.Time
 	index     int
@@ -62,97 +70,268 @@ func (h *lfuHeap[K, V]) Pop() any {
 }
 
 // NewLFUCache создает новый LFU кэш
-func NewLFUCache[K comparable, V any](maxEntries int, defaultTTL, cleanupInterval time.Duration) interfaces.Cache[K, V] {
+func NewLFUCache[K comparable, V any](maxEntries int, defaultTTL, cleanupInterval time.Duration, opts ...Option[V]) interfaces.Cache[K, V] {
+	c := newLFUSegment[K, V](maxEntries, defaultTTL, opts...)
+	c.janitor = startJanitor(cleanupInterval, c.cleanupExpired)
+	return c
+}
+
+// newLFUSegment создает LFU кэш без фоновой очистки, очисткой управляет владелец
+func newLFUSegment[K comparable, V any](maxEntries int, defaultTTL time.Duration, opts ...Option[V]) *lfuCache[K, V] {
 	h := &lfuHeap[K, V]{}
 	heap.Init(h)
 
-	c := &lfuCache[K, V]{
-		maxEntries:      maxEntries,
-		defaultTTL:      defaultTTL,
-		cleanupInterval: cleanupInterval,
-		cache:           make(map[K]*lfuEntry[K, V]),
-		heap:            h,
-		cleanupTicker:   time.NewTicker(cleanupInterval),
-		stopCleanup:     make(chan struct{}),
+	o := newOptions(opts)
+	return &lfuCache[K, V]{
+		maxEntries: maxEntries,
+		defaultTTL: defaultTTL,
+		cache:      make(map[K]*lfuEntry[K, V]),
+		heap:       h,
+		options:    o,
+		loader:     newLoader[K, V](maxEntries, o),
 	}
-
-	go c.cleanupExpired()
-
-	return c
 }
 
 // Set добавляет или обновляет элемент в кэше
 func (c *lfuCache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Duration) {
-	var itemTTL time.Duration
-	if len(ttl) > 0 {
-		itemTTL = ttl[0]
-	} else {
-		itemTTL = c.defaultTTL
-	}
+	value = c.options.clone(value)
+	c.loader.forget(ctx, key)
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	if elem, exists := c.cache[key]; exists {
-		elem.value = value
-		elem.expiry = time.Now().Add(itemTTL)
-		elem.frequency++
-		heap.Fix(c.heap, elem.index)
-		return
-	}
+	c.set(key, value, resolveTTL(ttl, c.defaultTTL))
+}
 
-	newEntry := &lfuEntry[K, V]{
-		key:       key,
-		value:     value,
-		frequency: 1,
-		expiry:    time.Now().Add(itemTTL),
+// SetMany добавляет или обновляет несколько элементов под одной блокировкой
+func (c *lfuCache[K, V]) SetMany(ctx context.Context, values map[K]V, ttl ...time.Duration) {
+	itemTTL := resolveTTL(ttl, c.defaultTTL)
+
+	cloned := make(map[K]V, len(values))
+	for key, value := range values {
+		cloned[key] = c.options.clone(value)
+		c.loader.forget(ctx, key)
 	}
-	heap.Push(c.heap, newEntry)
-	c.cache[key] = newEntry
 
-	if len(c.cache) > c.maxEntries {
-		c.evict()
+	c.mu.Lock()
+	defer c.unlock()
+
+	for key, value := range cloned {
+		c.set(key, value, itemTTL)
 	}
 }
 
 // Get возвращает значение элемента по ключу
 func (c *lfuCache[K, V]) Get(ctx context.Context, key K) (V, bool) {
-	var zero V
-
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	if elem, exists := c.cache[key]; exists {
-		if time.Now().After(elem.expiry) {
-			c.removeElement(elem)
-			return zero, false
+	value, found := c.get(key)
+	if !found {
+		return value, false
+	}
+	return c.options.clone(value), true
+}
+
+// GetMany возвращает найденные элементы под одной блокировкой
+func (c *lfuCache[K, V]) GetMany(ctx context.Context, keys []K) map[K]V {
+	found := make(map[K]V, len(keys))
+
+	c.mu.Lock()
+	for _, key := range keys {
+		if value, ok := c.get(key); ok {
+			found[key] = value
 		}
-		elem.frequency++
-		heap.Fix(c.heap, elem.index)
-		return elem.value, true
 	}
+	c.unlock()
+
+	for key, value := range found {
+		found[key] = c.options.clone(value)
+	}
+	return found
+}
 
-	return zero, false
+// GetOrLoad возвращает значение из кэша, а при промахе загружает его через load
+func (c *lfuCache[K, V]) GetOrLoad(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error) {
+	return c.loader.getOrLoad(ctx, c, key, load)
 }
 
 // Delete удаляет элемент из кэша по ключу
 func (c *lfuCache[K, V]) Delete(ctx context.Context, key K) {
+	c.loader.forget(ctx, key)
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	if elem, exists := c.cache[key]; exists {
-		c.removeElement(elem)
+	c.delete(key)
+}
+
+// DeleteMany удаляет несколько элементов под одной блокировкой
+func (c *lfuCache[K, V]) DeleteMany(ctx context.Context, keys []K) {
+	for _, key := range keys {
+		c.loader.forget(ctx, key)
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	for _, key := range keys {
+		c.delete(key)
 	}
 }
 
 // Flush очищает весь кэш
 func (c *lfuCache[K, V]) Flush(ctx context.Context) {
+	c.loader.forgetAll(ctx)
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
+	for _, elem := range *c.heap {
+		c.evictions.add(elem.key, elem.value, domain.EvictionDeleted)
+	}
 	c.cache = make(map[K]*lfuEntry[K, V])
 	c.heap = &lfuHeap[K, V]{}
 	heap.Init(c.heap)
+	c.cost = 0
+}
+
+// Inspect возвращает элемент без увеличения его частоты
+func (c *lfuCache[K, V]) Inspect(ctx context.Context, key K) (domain.CacheEntry[V], bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	elem, exists := c.cache[key]
+	if !exists {
+		return domain.CacheEntry[V]{}, false
+	}
+	return domain.CacheEntry[V]{Value: c.options.clone(elem.value), ExpiresAt: elem.expiry}, true
+}
+
+// Hottest возвращает живые элементы от часто используемых к редко используемым
+func (c *lfuCache[K, V]) Hottest(ctx context.Context, limit int) []domain.CacheItem[K, V] {
+	c.mu.RLock()
+	now := time.Now()
+	entries := make([]*lfuEntry[K, V], 0, len(c.cache))
+	for _, elem := range c.cache {
+		if now.Before(elem.expiry) {
+			entries = append(entries, elem)
+		}
+	}
+	slices.SortFunc(entries, func(a, b *lfuEntry[K, V]) int {
+		return cmp.Compare(b.frequency, a.frequency)
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	items := make([]domain.CacheItem[K, V], len(entries))
+	for i, elem := range entries {
+		items[i] = domain.CacheItem[K, V]{Key: elem.key, Value: elem.value, ExpiresAt: elem.expiry}
+	}
+	c.mu.RUnlock()
+
+	return cloneItems(items, c.options)
+}
+
+// OnEvict подписывает fn на удаление элементов из кэша. fn вызывается после снятия блокировки
+func (c *lfuCache[K, V]) OnEvict(fn func(key K, value V, reason domain.EvictionReason)) {
+	c.evictions.subscribe(fn)
+}
+
+// Stats возвращает статистику кэша
+func (c *lfuCache[K, V]) Stats() domain.CacheStats {
+	c.mu.RLock()
+	size, cost := len(c.cache), c.cost
+	c.mu.RUnlock()
+
+	return c.stats.snapshot(size, cost)
+}
+
+// RecordLoad учитывает загрузку значения из источника
+func (c *lfuCache[K, V]) RecordLoad(d time.Duration) {
+	c.stats.recordLoad(d)
+}
+
+// set добавляет или обновляет элемент, вызывается под блокировкой
+func (c *lfuCache[K, V]) set(key K, value V, ttl time.Duration) {
+	cost := c.options.costOf(value)
+	if c.options.overBudget(cost) {
+		c.reject(key, value)
+		return
+	}
+	expiry := time.Now().Add(c.options.jitter(ttl))
+
+	if elem, exists := c.cache[key]; exists {
+		c.cost += cost - elem.cost
+		elem.value = value
+		elem.cost = cost
+		elem.expiry = expiry
+		elem.frequency++
+		heap.Fix(c.heap, elem.index)
+	} else {
+		newEntry := &lfuEntry[K, V]{
+			key:       key,
+			value:     value,
+			cost:      cost,
+			frequency: 1,
+			expiry:    expiry,
+		}
+		heap.Push(c.heap, newEntry)
+		c.cache[key] = newEntry
+		c.cost += cost
+	}
+
+	for len(c.cache) > c.maxEntries || c.options.overBudget(c.cost) {
+		c.evict()
+	}
+}
+
+// reject отказывает в записи элементу, который дороже всего бюджета. Прежнее значение ключа
+// удаляется, чтобы Get не вернул устаревшие данные
+func (c *lfuCache[K, V]) reject(key K, value V) {
+	if elem, exists := c.cache[key]; exists {
+		value = elem.value
+		c.removeElement(elem)
+	}
+	c.stats.evictedByCapacity()
+	c.evictions.add(key, value, domain.EvictionCapacity)
+}
+
+// get возвращает значение без копирования, вызывается под блокировкой
+func (c *lfuCache[K, V]) get(key K) (V, bool) {
+	var zero V
+
+	elem, exists := c.cache[key]
+	if !exists {
+		c.stats.miss()
+		return zero, false
+	}
+	if time.Now().After(elem.expiry) {
+		c.removeElement(elem)
+		c.stats.evictedByTTL()
+		c.evictions.add(elem.key, elem.value, domain.EvictionExpired)
+		c.stats.miss()
+		return zero, false
+	}
+	elem.frequency++
+	heap.Fix(c.heap, elem.index)
+	c.stats.hit()
+	return elem.value, true
+}
+
+// delete удаляет элемент, вызывается под блокировкой
+func (c *lfuCache[K, V]) delete(key K) {
+	if elem, exists := c.cache[key]; exists {
+		c.removeElement(elem)
+		c.evictions.add(elem.key, elem.value, domain.EvictionDeleted)
+	}
+}
+
+// unlock снимает блокировку и передаёт подписчикам элементы, удалённые под ней
+func (c *lfuCache[K, V]) unlock() {
+	evicted := c.evictions.take()
+	c.mu.Unlock()
+	c.evictions.notify(evicted)
 }
 
 func (c *lfuCache[K, V]) evict() {
@@ -161,35 +340,48 @@ func (c *lfuCache[K, V]) evict() {
 	}
 	elem := heap.Pop(c.heap).(*lfuEntry[K, V])
 	delete(c.cache, elem.key)
+	c.cost -= elem.cost
+	c.stats.evictedByCapacity()
+	c.evictions.add(elem.key, elem.value, domain.EvictionCapacity)
 	log.Printf("Evicting key: %v due to cache size limit", elem.key)
 }
 
 func (c *lfuCache[K, V]) removeElement(elem *lfuEntry[K, V]) {
 	heap.Remove(c.heap, elem.index)
 	delete(c.cache, elem.key)
+	c.cost -= elem.cost
 	log.Printf("Removing key: %v due to expiration or deletion", elem.key)
 }
 
 func (c *lfuCache[K, V]) cleanupExpired() {
-	for {
-		select {
-		case <-c.cleanupTicker.C:
-			c.mu.Lock()
-			now := time.Now()
-			for _, elem := range c.cache {
-				if now.After(elem.expiry) {
-					c.removeElement(elem)
-				}
-			}
-			c.mu.Unlock()
-		case <-c.stopCleanup:
-			return
+	sweepIncrementally(c.sweepExpired)
+}
+
+// sweepExpired просматривает до limit элементов и удаляет просроченные
+func (c *lfuCache[K, V]) sweepExpired(limit int) (scanned, removed int) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := time.Now()
+	for _, elem := range c.cache {
+		if scanned == limit {
+			break
+		}
+		scanned++
+		if now.After(elem.expiry) {
+			c.removeElement(elem)
+			c.stats.evictedByTTL()
+			c.evictions.add(elem.key, elem.value, domain.EvictionExpired)
+			removed++
 		}
 	}
+	return scanned, removed
 }
 
 // Close останавливает процесс очистки и освобождает ресурсы
-func (c *lfuCache[K, V]) Close() {
-	c.cleanupTicker.Stop()
-	close(c.stopCleanup)
+func (c *lfuCache[K, V]) Close() error {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+	return nil
 }
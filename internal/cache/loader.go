@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+)
+
+// loadCall — загрузка ключа, которую ждут все конкурентные вызовы GetOrLoad
+type loadCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+	// forgotten считает, сколько раз ключ меняли или удаляли во время загрузки. Если ключ изменился
+	// до сохранения, результат отдаётся ожидающим, но не сохраняется в кэш
+	forgotten int
+}
+
+// defaultLoadTimeout ограничивает загрузку, если WithLoadTimeout не задан, чтобы зависший запрос
+// к источнику не держал ключ вечно
+const defaultLoadTimeout = 30 * time.Second
+
+// loader реализует GetOrLoad поверх любого кэша: объединяет конкурентные загрузки одного ключа,
+// запоминает отсутствующие ключи на negativeTTL и заранее обновляет элементы, которым осталось
+// жить меньше refreshAhead
+type loader[K comparable, V any] struct {
+	mu       sync.Mutex
+	calls    map[K]*loadCall[V]
+	negative *lruCache[K, struct{}]
+	options  options[V]
+}
+
+func newLoader[K comparable, V any](maxEntries int, o options[V]) *loader[K, V] {
+	l := &loader[K, V]{
+		calls:   make(map[K]*loadCall[V]),
+		options: o,
+	}
+	if o.negativeTTL > 0 {
+		l.negative = newLRUSegment[K, struct{}](maxEntries, o.negativeTTL)
+	}
+	return l
+}
+
+func (l *loader[K, V]) getOrLoad(ctx context.Context, c interfaces.Cache[K, V], key K, load func(ctx context.Context) (V, error)) (V, error) {
+	if value, found := c.Get(ctx, key); found {
+		if l.options.refreshAhead > 0 {
+			if entry, ok := c.Inspect(ctx, key); ok && time.Until(entry.ExpiresAt) < l.options.refreshAhead {
+				l.start(ctx, c, key, load)
+			}
+		}
+		return value, nil
+	}
+
+	var zero V
+	if l.negative != nil {
+		if _, found := l.negative.Get(ctx, key); found {
+			return zero, domain.ErrCacheMiss
+		}
+	}
+
+	call := l.start(ctx, c, key, load)
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return zero, call.err
+		}
+		return l.options.clone(call.value), nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// start запускает загрузку ключа или возвращает уже идущую. Загрузка не привязана к отмене
+// контекста вызывающего, чтобы его таймаут не оборвал её для остальных ожидающих, и ограничена
+// собственным таймаутом
+func (l *loader[K, V]) start(ctx context.Context, c interfaces.Cache[K, V], key K, load func(ctx context.Context) (V, error)) *loadCall[V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if call, exists := l.calls[key]; exists {
+		return call
+	}
+
+	call := &loadCall[V]{done: make(chan struct{})}
+	l.calls[key] = call
+
+	go l.run(context.WithoutCancel(ctx), c, key, load, call)
+
+	return call
+}
+
+func (l *loader[K, V]) run(ctx context.Context, c interfaces.Cache[K, V], key K, load func(ctx context.Context) (V, error), call *loadCall[V]) {
+	loadCtx, cancel := context.WithTimeout(ctx, l.options.loadTimeoutOrDefault())
+	start := time.Now()
+	value, err := load(loadCtx)
+	cancel()
+	c.RecordLoad(time.Since(start))
+
+	// Загрузка остаётся зарегистрированной, пока результат не сохранён, чтобы изменение ключа
+	// между проверкой и сохранением не потерялось
+	l.mu.Lock()
+	stale := call.forgotten > 0
+	l.mu.Unlock()
+
+	switch {
+	case err == nil && !stale:
+		// Set сам вызывает forget для ключа один раз
+		c.Set(ctx, key, value)
+		l.finish(ctx, c, key, call, 1)
+	case errors.Is(err, domain.ErrCacheMiss) && !stale:
+		// Ключа больше нет в источнике: убираем устаревшее значение, если это было обновление заранее
+		c.Delete(ctx, key)
+		if l.negative != nil {
+			l.negative.Set(ctx, key, struct{}{})
+		}
+		l.finish(ctx, c, key, call, 1)
+	default:
+		if err != nil && !errors.Is(err, domain.ErrCacheMiss) {
+			log.Printf("Failed to load key %v into cache: %v", key, err)
+		}
+		// Ничего не сохранено, поэтому изменения ключа во время загрузки ничему не мешают
+		l.mu.Lock()
+		delete(l.calls, key)
+		l.mu.Unlock()
+	}
+
+	call.value, call.err = value, err
+	close(call.done)
+}
+
+// finish снимает загрузку с учёта. Если ключ изменили или удалили чаще, чем ожидалось от самой загрузки,
+// сохранённый результат мог перезаписать более свежее значение, поэтому ключ убирается из кэша,
+// а вместе с ним и из кэша отсутствующих
+func (l *loader[K, V]) finish(ctx context.Context, c interfaces.Cache[K, V], key K, call *loadCall[V], expected int) {
+	l.mu.Lock()
+	delete(l.calls, key)
+	raced := call.forgotten > expected
+	l.mu.Unlock()
+
+	if raced {
+		c.Delete(ctx, key)
+	}
+}
+
+// forget убирает ключ из кэша отсутствующих и не даёт идущей загрузке сохранить
+// результат, прочитанный до изменения ключа
+func (l *loader[K, V]) forget(ctx context.Context, key K) {
+	l.mu.Lock()
+	if call, exists := l.calls[key]; exists {
+		call.forgotten++
+	}
+	l.mu.Unlock()
+
+	if l.negative != nil {
+		l.negative.Delete(ctx, key)
+	}
+}
+
+// forgetAll сбрасывает кэш отсутствующих ключей и результаты всех идущих загрузок
+func (l *loader[K, V]) forgetAll(ctx context.Context) {
+	l.mu.Lock()
+	for _, call := range l.calls {
+		call.forgotten++
+	}
+	l.mu.Unlock()
+
+	if l.negative != nil {
+		l.negative.Flush(ctx)
+	}
+}
@@ -0,0 +1,257 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrLoad_CoalescesConcurrentLoads(t *testing.T) {
+	strategies := map[string]CacheStrategy{
+		"LRU":     LRUStrategy,
+		"LFU":     LFUStrategy,
+		"TinyLFU": TinyLFUStrategy,
+	}
+
+	for name, strategy := range strategies {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewCache[string, int](CacheConfig{
+				Strategy:        strategy,
+				MaxEntries:      100,
+				DefaultTTL:      time.Minute,
+				CleanupInterval: time.Minute,
+				Shards:          4,
+			})
+			require.NoError(t, err)
+			defer c.Close()
+
+			ctx := context.Background()
+
+			var loads atomic.Int32
+			release := make(chan struct{})
+			load := func(ctx context.Context) (int, error) {
+				loads.Add(1)
+				<-release
+				return 42, nil
+			}
+
+			var wg sync.WaitGroup
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					value, err := c.GetOrLoad(ctx, "order1", load)
+					assert.NoError(t, err)
+					assert.Equal(t, 42, value)
+				}()
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			assert.Equal(t, int32(1), loads.Load())
+
+			value, found := c.Get(ctx, "order1")
+			require.True(t, found)
+			assert.Equal(t, 42, value)
+			assert.Equal(t, uint64(1), c.Stats().Loads)
+		})
+	}
+}
+
+func TestGetOrLoad_ErrorIsNotCached(t *testing.T) {
+	cache := newTestLRUCache[string, int](10, time.Minute, time.Minute)
+	defer cache.Close()
+
+	ctx := context.Background()
+	errDB := errors.New("connection refused")
+
+	_, err := cache.GetOrLoad(ctx, "order1", func(ctx context.Context) (int, error) {
+		return 0, errDB
+	})
+	assert.ErrorIs(t, err, errDB)
+
+	value, err := cache.GetOrLoad(ctx, "order1", func(ctx context.Context) (int, error) {
+		return 7, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 7, value)
+}
+
+func TestGetOrLoad_NegativeCaching(t *testing.T) {
+	cache := NewLRUCache[string, int](10, time.Minute, time.Minute, WithNegativeCaching[int](50*time.Millisecond)).(*lruCache[string, int])
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	var loads atomic.Int32
+	missing := func(ctx context.Context) (int, error) {
+		loads.Add(1)
+		return 0, domain.ErrCacheMiss
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := cache.GetOrLoad(ctx, "order1", missing)
+		assert.ErrorIs(t, err, domain.ErrCacheMiss)
+	}
+	assert.Equal(t, int32(1), loads.Load(), "not-found result should be cached")
+
+	time.Sleep(60 * time.Millisecond)
+	_, err := cache.GetOrLoad(ctx, "order1", missing)
+	assert.ErrorIs(t, err, domain.ErrCacheMiss)
+	assert.Equal(t, int32(2), loads.Load(), "not-found result should expire")
+
+	// Set после создания заказа должен сбросить запомненное отсутствие
+	cache.Set(ctx, "order1", 5)
+	value, err := cache.GetOrLoad(ctx, "order1", missing)
+	require.NoError(t, err)
+	assert.Equal(t, 5, value)
+}
+
+func TestGetOrLoad_RefreshAhead(t *testing.T) {
+	cache := NewLRUCache[string, int](10, 100*time.Millisecond, time.Minute, WithRefreshAhead[int](80*time.Millisecond)).(*lruCache[string, int])
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	var version atomic.Int32
+	load := func(ctx context.Context) (int, error) {
+		return int(version.Add(1)), nil
+	}
+
+	value, err := cache.GetOrLoad(ctx, "order1", load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// Элемент ещё жив, поэтому вызывающий сразу получает текущее значение, а обновление идёт в фоне
+	value, err = cache.GetOrLoad(ctx, "order1", load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	assert.Eventually(t, func() bool {
+		entry, found := cache.Inspect(ctx, "order1")
+		return found && entry.Value == 2
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestGetOrLoad_CallerCancellationDoesNotAbortLoad(t *testing.T) {
+	cache := newTestLRUCache[string, int](10, time.Minute, time.Minute)
+	defer cache.Close()
+
+	release := make(chan struct{})
+	load := func(ctx context.Context) (int, error) {
+		<-release
+		return 42, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err := cache.GetOrLoad(ctx, "order1", load)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	value, err := cache.GetOrLoad(context.Background(), "order1", load)
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+}
+
+func TestGetOrLoad_DeleteDuringLoadDiscardsResult(t *testing.T) {
+	cache := newTestLRUCache[string, int](10, time.Minute, time.Minute)
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		value, err := cache.GetOrLoad(ctx, "order1", func(ctx context.Context) (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, value)
+	}()
+
+	<-started
+	cache.Delete(ctx, "order1")
+	close(release)
+	<-done
+
+	_, found := cache.Get(ctx, "order1")
+	assert.False(t, found, "value read before Delete must not be cached")
+}
+
+// invalidatingCache вызывает beforeSet перед первой записью, чтобы изменение ключа попало
+// между проверкой загрузки и сохранением её результата
+type invalidatingCache struct {
+	interfaces.Cache[string, int]
+	loader    *loader[string, int]
+	beforeSet func()
+}
+
+func (c *invalidatingCache) Set(ctx context.Context, key string, value int, ttl ...time.Duration) {
+	if hook := c.beforeSet; hook != nil {
+		c.beforeSet = nil
+		hook()
+	}
+	c.loader.forget(ctx, key)
+	c.Cache.Set(ctx, key, value, ttl...)
+}
+
+func (c *invalidatingCache) Delete(ctx context.Context, key string) {
+	c.loader.forget(ctx, key)
+	c.Cache.Delete(ctx, key)
+}
+
+func TestGetOrLoad_DeleteBeforeSetDiscardsResult(t *testing.T) {
+	inner := NewLRUCache[string, int](10, time.Minute, time.Minute)
+	defer inner.Close()
+	c := &invalidatingCache{Cache: inner, loader: newLoader[string, int](10, options[int]{})}
+	c.beforeSet = func() { c.Delete(context.Background(), "order1") }
+
+	ctx := context.Background()
+	value, err := c.loader.getOrLoad(ctx, c, "order1", func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	_, found := c.Get(ctx, "order1")
+	assert.False(t, found, "a Delete that lands right before the Set must not be lost")
+}
+
+func TestGetOrLoad_LoadTimeout(t *testing.T) {
+	cache := NewLRUCache[string, int](10, time.Minute, time.Minute, WithLoadTimeout[int](20*time.Millisecond))
+	defer cache.Close()
+
+	ctx := context.Background()
+	_, err := cache.GetOrLoad(ctx, "order1", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	value, err := cache.GetOrLoad(ctx, "order1", func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, value, "a timed out load must not pin the key")
+}
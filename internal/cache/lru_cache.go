@@ -6,150 +6,335 @@ import (
 	"sync"
 	"time"
 
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
 )
 
 // lruCache представляет кэш со стратегией LRU
 type lruCache[K comparable, V any] struct {
-	mu              sync.RWMutex
-	maxEntries      int
-	defaultTTL      time.Duration
-	cleanupInterval time.Duration
-	cache           map[K]*list.Element
-	lruList         *list.List
-	cleanupTicker   *time.Ticker
-	stopCleanup     chan struct{}
+	mu         sync.RWMutex
+	maxEntries int
+	defaultTTL time.Duration
+	// cost — суммарная стоимость элементов
+	cost      int64
+	cache     map[K]*list.Element
+	lruList   *list.List
+	janitor   *janitor
+	options   options[V]
+	loader    *loader[K, V]
+	stats     statsCounter
+	evictions evictionQueue[K, V]
 }
 
 // entry представляет элемент в LRU кэше
 type entry[K comparable, V any] struct {
 	key    K
 	value  V
+	cost   int64
 	expiry time.Time
 }
 
 // NewLRUCache создает новый LRU кэш
-func NewLRUCache[K comparable, V any](maxEntries int, defaultTTL, cleanupInterval time.Duration) interfaces.Cache[K, V] {
-	c := &lruCache[K, V]{
-		maxEntries:      maxEntries,
-		defaultTTL:      defaultTTL,
-		cleanupInterval: cleanupInterval,
-		cache:           make(map[K]*list.Element),
-		lruList:         list.New(),
-		cleanupTicker:   time.NewTicker(cleanupInterval),
-		stopCleanup:     make(chan struct{}),
-	}
-
-	go c.cleanupExpired()
-
+func NewLRUCache[K comparable, V any](maxEntries int, defaultTTL, cleanupInterval time.Duration, opts ...Option[V]) interfaces.Cache[K, V] {
+	c := newLRUSegment[K, V](maxEntries, defaultTTL, opts...)
+	c.janitor = startJanitor(cleanupInterval, c.cleanupExpired)
 	return c
 }
 
+// newLRUSegment создает LRU кэш без фоновой очистки, очисткой управляет владелец
+func newLRUSegment[K comparable, V any](maxEntries int, defaultTTL time.Duration, opts ...Option[V]) *lruCache[K, V] {
+	o := newOptions(opts)
+	return &lruCache[K, V]{
+		maxEntries: maxEntries,
+		defaultTTL: defaultTTL,
+		cache:      make(map[K]*list.Element),
+		lruList:    list.New(),
+		options:    o,
+		loader:     newLoader[K, V](maxEntries, o),
+	}
+}
+
 // Set добавляет или обновляет элемент в кэше
 func (c *lruCache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Duration) {
-	var itemTTL time.Duration
-	if len(ttl) > 0 {
-		itemTTL = ttl[0]
-	} else {
-		itemTTL = c.defaultTTL
-	}
+	value = c.options.clone(value)
+	c.loader.forget(ctx, key)
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	if elem, exists := c.cache[key]; exists {
-		c.lruList.MoveToFront(elem)
-		elem.Value.(*entry[K, V]).value = value
-		elem.Value.(*entry[K, V]).expiry = time.Now().Add(itemTTL)
-		return
-	}
+	c.set(key, value, resolveTTL(ttl, c.defaultTTL))
+}
+
+// SetMany добавляет или обновляет несколько элементов под одной блокировкой
+func (c *lruCache[K, V]) SetMany(ctx context.Context, values map[K]V, ttl ...time.Duration) {
+	itemTTL := resolveTTL(ttl, c.defaultTTL)
 
-	newEntry := &entry[K, V]{
-		key:    key,
-		value:  value,
-		expiry: time.Now().Add(itemTTL),
+	cloned := make(map[K]V, len(values))
+	for key, value := range values {
+		cloned[key] = c.options.clone(value)
+		c.loader.forget(ctx, key)
 	}
-	elem := c.lruList.PushFront(newEntry)
-	c.cache[key] = elem
 
-	if c.lruList.Len() > c.maxEntries {
-		c.evict()
+	c.mu.Lock()
+	defer c.unlock()
+
+	for key, value := range cloned {
+		c.set(key, value, itemTTL)
 	}
 }
 
 // Get возвращает значение элемента по ключу и обновляет его позицию в списке
 func (c *lruCache[K, V]) Get(ctx context.Context, key K) (V, bool) {
-	var zero V
-
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	if elem, exists := c.cache[key]; exists {
-		if time.Now().After(elem.Value.(*entry[K, V]).expiry) {
-			c.removeElement(elem)
-			return zero, false
+	value, found := c.get(key)
+	if !found {
+		//Возвращает zero value и false, если элемент не найден или истек по TTL
+		return value, false
+	}
+	return c.options.clone(value), true
+}
+
+// GetMany возвращает найденные элементы под одной блокировкой
+func (c *lruCache[K, V]) GetMany(ctx context.Context, keys []K) map[K]V {
+	found := make(map[K]V, len(keys))
+
+	c.mu.Lock()
+	for _, key := range keys {
+		if value, ok := c.get(key); ok {
+			found[key] = value
 		}
-		c.lruList.MoveToFront(elem)
-		return elem.Value.(*entry[K, V]).value, true
 	}
-	//Возвращает zero value и false, если элемент не найден или истек по TTL
-	return zero, false
+	c.unlock()
+
+	for key, value := range found {
+		found[key] = c.options.clone(value)
+	}
+	return found
+}
+
+// GetOrLoad возвращает значение из кэша, а при промахе загружает его через load
+func (c *lruCache[K, V]) GetOrLoad(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error) {
+	return c.loader.getOrLoad(ctx, c, key, load)
 }
 
 // Delete удаляет элемент из кэша по ключу
 func (c *lruCache[K, V]) Delete(ctx context.Context, key K) {
+	c.loader.forget(ctx, key)
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	if elem, exists := c.cache[key]; exists {
-		c.removeElement(elem)
+	c.delete(key)
+}
+
+// DeleteMany удаляет несколько элементов под одной блокировкой
+func (c *lruCache[K, V]) DeleteMany(ctx context.Context, keys []K) {
+	for _, key := range keys {
+		c.loader.forget(ctx, key)
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	for _, key := range keys {
+		c.delete(key)
 	}
 }
 
 // Flush очищает весь кэш
 func (c *lruCache[K, V]) Flush(ctx context.Context) {
+	c.loader.forgetAll(ctx)
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
+	for elem := c.lruList.Front(); elem != nil; elem = elem.Next() {
+		e := elem.Value.(*entry[K, V])
+		c.evictions.add(e.key, e.value, domain.EvictionDeleted)
+	}
 	c.lruList.Init()
 	c.cache = make(map[K]*list.Element)
+	c.cost = 0
+}
+
+// Inspect возвращает элемент без обновления его позиции в списке
+func (c *lruCache[K, V]) Inspect(ctx context.Context, key K) (domain.CacheEntry[V], bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	elem, exists := c.cache[key]
+	if !exists {
+		return domain.CacheEntry[V]{}, false
+	}
+	e := elem.Value.(*entry[K, V])
+	return domain.CacheEntry[V]{Value: c.options.clone(e.value), ExpiresAt: e.expiry}, true
+}
+
+// Hottest возвращает живые элементы от недавно использованных к давно использованным
+func (c *lruCache[K, V]) Hottest(ctx context.Context, limit int) []domain.CacheItem[K, V] {
+	var items []domain.CacheItem[K, V]
+
+	c.mu.RLock()
+	now := time.Now()
+	for elem := c.lruList.Front(); elem != nil && (limit <= 0 || len(items) < limit); elem = elem.Next() {
+		e := elem.Value.(*entry[K, V])
+		if now.Before(e.expiry) {
+			items = append(items, domain.CacheItem[K, V]{Key: e.key, Value: e.value, ExpiresAt: e.expiry})
+		}
+	}
+	c.mu.RUnlock()
+
+	return cloneItems(items, c.options)
+}
+
+// OnEvict подписывает fn на удаление элементов из кэша. fn вызывается после снятия блокировки
+func (c *lruCache[K, V]) OnEvict(fn func(key K, value V, reason domain.EvictionReason)) {
+	c.evictions.subscribe(fn)
+}
+
+// Stats возвращает статистику кэша
+func (c *lruCache[K, V]) Stats() domain.CacheStats {
+	c.mu.RLock()
+	size, cost := len(c.cache), c.cost
+	c.mu.RUnlock()
+
+	return c.stats.snapshot(size, cost)
+}
+
+// RecordLoad учитывает загрузку значения из источника
+func (c *lruCache[K, V]) RecordLoad(d time.Duration) {
+	c.stats.recordLoad(d)
+}
+
+// set добавляет или обновляет элемент, вызывается под блокировкой
+func (c *lruCache[K, V]) set(key K, value V, ttl time.Duration) {
+	cost := c.options.costOf(value)
+	if c.options.overBudget(cost) {
+		c.reject(key, value)
+		return
+	}
+	expiry := time.Now().Add(c.options.jitter(ttl))
+
+	if elem, exists := c.cache[key]; exists {
+		c.lruList.MoveToFront(elem)
+		e := elem.Value.(*entry[K, V])
+		c.cost += cost - e.cost
+		e.value = value
+		e.cost = cost
+		e.expiry = expiry
+	} else {
+		c.cache[key] = c.lruList.PushFront(&entry[K, V]{
+			key:    key,
+			value:  value,
+			cost:   cost,
+			expiry: expiry,
+		})
+		c.cost += cost
+	}
+
+	// Новый элемент стоит в начале списка и укладывается в бюджет сам, поэтому вытесняются только другие
+	for c.lruList.Len() > c.maxEntries || c.options.overBudget(c.cost) {
+		c.evict()
+	}
+}
+
+// reject отказывает в записи элементу, который дороже всего бюджета. Прежнее значение ключа
+// удаляется, чтобы Get не вернул устаревшие данные
+func (c *lruCache[K, V]) reject(key K, value V) {
+	if elem, exists := c.cache[key]; exists {
+		c.removeElement(elem, domain.EvictionCapacity)
+		return
+	}
+	c.stats.evictedByCapacity()
+	c.evictions.add(key, value, domain.EvictionCapacity)
+}
+
+// get возвращает значение без копирования, вызывается под блокировкой
+func (c *lruCache[K, V]) get(key K) (V, bool) {
+	var zero V
+
+	elem, exists := c.cache[key]
+	if !exists {
+		c.stats.miss()
+		return zero, false
+	}
+	if time.Now().After(elem.Value.(*entry[K, V]).expiry) {
+		c.removeElement(elem, domain.EvictionExpired)
+		c.stats.miss()
+		return zero, false
+	}
+	c.lruList.MoveToFront(elem)
+	c.stats.hit()
+	return elem.Value.(*entry[K, V]).value, true
+}
+
+// delete удаляет элемент, вызывается под блокировкой
+func (c *lruCache[K, V]) delete(key K) {
+	if elem, exists := c.cache[key]; exists {
+		c.removeElement(elem, domain.EvictionDeleted)
+	}
+}
+
+// unlock снимает блокировку и передаёт подписчикам элементы, удалённые под ней
+func (c *lruCache[K, V]) unlock() {
+	evicted := c.evictions.take()
+	c.mu.Unlock()
+	c.evictions.notify(evicted)
 }
 
 // evict удаляет наименее недавно использованный элемент из кэша
 func (c *lruCache[K, V]) evict() {
 	elem := c.lruList.Back()
 	if elem != nil {
-		c.removeElement(elem)
+		c.removeElement(elem, domain.EvictionCapacity)
 	}
 }
 
-// removeElement удаляет элемент из списка и мапы
-func (c *lruCache[K, V]) removeElement(elem *list.Element) {
+// removeElement удаляет элемент из списка и мапы и учитывает причину удаления
+func (c *lruCache[K, V]) removeElement(elem *list.Element, reason domain.EvictionReason) {
 	c.lruList.Remove(elem)
 	entry := elem.Value.(*entry[K, V])
 	delete(c.cache, entry.key)
+	c.cost -= entry.cost
+
+	switch reason {
+	case domain.EvictionCapacity:
+		c.stats.evictedByCapacity()
+	case domain.EvictionExpired:
+		c.stats.evictedByTTL()
+	}
+	c.evictions.add(entry.key, entry.value, reason)
 }
 
 func (c *lruCache[K, V]) cleanupExpired() {
-	for {
-		select {
-		case <-c.cleanupTicker.C:
-			c.mu.Lock()
-			now := time.Now()
-			for _, elem := range c.cache {
-				if now.After(elem.Value.(*entry[K, V]).expiry) {
-					c.removeElement(elem)
-				}
-			}
-			c.mu.Unlock()
-		case <-c.stopCleanup:
-			return
+	sweepIncrementally(c.sweepExpired)
+}
+
+// sweepExpired просматривает до limit элементов и удаляет просроченные
+func (c *lruCache[K, V]) sweepExpired(limit int) (scanned, removed int) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := time.Now()
+	for _, elem := range c.cache {
+		if scanned == limit {
+			break
+		}
+		scanned++
+		if now.After(elem.Value.(*entry[K, V]).expiry) {
+			c.removeElement(elem, domain.EvictionExpired)
+			removed++
 		}
 	}
+	return scanned, removed
 }
 
 // Close останавливает процесс очистки и освобождает ресурсы.
-func (c *lruCache[K, V]) Close() {
-	c.cleanupTicker.Stop()
-	close(c.stopCleanup)
+func (c *lruCache[K, V]) Close() error {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+	return nil
 }
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+)
+
+// Option задаёт дополнительные параметры кэша
+type Option[V any] func(*options[V])
+
+type options[V any] struct {
+	cloner       func(V) V
+	negativeTTL  time.Duration
+	refreshAhead time.Duration
+	codec        Codec[V]
+	cost         func(V) int64
+	maxCost      int64
+	ttlJitter    float64
+	loadTimeout  time.Duration
+}
+
+// WithCloner включает копирование значений: кэш сохраняет копию при Set и отдаёт копию при Get.
+// Нужно для значений-указателей, чтобы изменения у одного вызывающего не попадали к остальным
+func WithCloner[V any](cloner func(V) V) Option[V] {
+	return func(o *options[V]) {
+		o.cloner = cloner
+	}
+}
+
+// WithNegativeCaching включает кэширование отсутствующих ключей: если загрузчик GetOrLoad вернул
+// domain.ErrCacheMiss, повторные запросы ключа в течение ttl не доходят до источника
+func WithNegativeCaching[V any](ttl time.Duration) Option[V] {
+	return func(o *options[V]) {
+		o.negativeTTL = ttl
+	}
+}
+
+// WithRefreshAhead включает фоновое обновление: если GetOrLoad попал в элемент, которому осталось
+// жить меньше window, значение перезагружается заранее, а вызывающий сразу получает текущее
+func WithRefreshAhead[V any](window time.Duration) Option[V] {
+	return func(o *options[V]) {
+		o.refreshAhead = window
+	}
+}
+
+// WithLoadTimeout ограничивает одну загрузку GetOrLoad, по умолчанию 30 секунд. Загрузка идёт независимо
+// от отмены контекста вызывающего, поэтому без таймаута зависший запрос к источнику держал бы ключ вечно
+func WithLoadTimeout[V any](timeout time.Duration) Option[V] {
+	return func(o *options[V]) {
+		o.loadTimeout = timeout
+	}
+}
+
+// WithCodec задаёт формат значений в удалённом кэше, по умолчанию JSON
+func WithCodec[V any](codec Codec[V]) Option[V] {
+	return func(o *options[V]) {
+		o.codec = codec
+	}
+}
+
+// WithCost задаёт стоимость элемента, например, примерный размер значения в байтах.
+// Вместе с CacheConfig.MaxCost ограничивает суммарную стоимость кэша, без функции каждый элемент стоит 1
+func WithCost[V any](cost func(V) int64) Option[V] {
+	return func(o *options[V]) {
+		o.cost = cost
+	}
+}
+
+// WithTTLJitter сокращает TTL каждого элемента на случайную долю до fraction, чтобы элементы,
+// записанные одновременно, не истекали тоже одновременно. fraction ограничивается отрезком [0, 1]
+func WithTTLJitter[V any](fraction float64) Option[V] {
+	return func(o *options[V]) {
+		o.ttlJitter = min(max(fraction, 0), 1)
+	}
+}
+
+// withMaxCost задаёт бюджет стоимости, вызывается из NewCache и NewShardedCache
+func withMaxCost[V any](maxCost int64) Option[V] {
+	return func(o *options[V]) {
+		o.maxCost = maxCost
+	}
+}
+
+func newOptions[V any](opts []Option[V]) options[V] {
+	var o options[V]
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o options[V]) clone(value V) V {
+	if o.cloner == nil {
+		return value
+	}
+	return o.cloner(value)
+}
+
+// cloneItems копирует значения элементов, собранных под блокировкой кэша
+func cloneItems[K comparable, V any](items []domain.CacheItem[K, V], o options[V]) []domain.CacheItem[K, V] {
+	for i := range items {
+		items[i].Value = o.clone(items[i].Value)
+	}
+	return items
+}
+
+func (o options[V]) loadTimeoutOrDefault() time.Duration {
+	if o.loadTimeout <= 0 {
+		return defaultLoadTimeout
+	}
+	return o.loadTimeout
+}
+
+// costOf возвращает стоимость значения
+func (o options[V]) costOf(value V) int64 {
+	if o.cost == nil {
+		return 1
+	}
+	return o.cost(value)
+}
+
+// overBudget сообщает, превышает ли стоимость cost бюджет кэша
+func (o options[V]) overBudget(cost int64) bool {
+	return o.maxCost > 0 && cost > o.maxCost
+}
+
+// jitter применяет к ttl случайное сокращение из WithTTLJitter
+func (o options[V]) jitter(ttl time.Duration) time.Duration {
+	if o.ttlJitter == 0 || ttl <= 0 {
+		return ttl
+	}
+	return ttl - time.Duration(rand.Float64()*o.ttlJitter*float64(ttl))
+}
+
+// resolveTTL возвращает TTL из необязательного аргумента Set или значение по умолчанию
+func resolveTTL(ttl []time.Duration, defaultTTL time.Duration) time.Duration {
+	if len(ttl) > 0 {
+		return ttl[0]
+	}
+	return defaultTTL
+}
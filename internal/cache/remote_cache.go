@@ -0,0 +1,344 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+)
+
+const (
+	// flushBatch — сколько ключей Flush удаляет за одну команду
+	flushBatch = 500
+	// remoteNegativeEntries ограничивает локальный список отсутствующих ключей: размера
+	// удалённого кэша реплика не знает
+	remoteNegativeEntries = 10000
+)
+
+// RedisConfig содержит параметры подключения к удалённому кэшу
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	// KeyPrefix отделяет ключи этого кэша от остальных данных в Redis
+	KeyPrefix string
+	// TTL — время жизни в удалённом кэше, по умолчанию DefaultTTL
+	TTL time.Duration
+}
+
+// remoteCache хранит значения в Redis или совместимом сервере, общем для всех реплик.
+// Вытеснение и истечение TTL выполняет сервер, поэтому в статистике учитываются только
+// попадания, промахи и загрузки, а размер не известен
+type remoteCache[K comparable, V any] struct {
+	client     redis.UniversalClient
+	ownsClient bool
+	prefix     string
+	key        func(K) string
+	codec      Codec[V]
+	options    options[V]
+	defaultTTL time.Duration
+	loader     *loader[K, V]
+	stats      statsCounter
+}
+
+// NewRemoteCache создает кэш поверх готового клиента Redis. Клиент остаётся за вызывающим,
+// Close его не закрывает
+func NewRemoteCache[K comparable, V any](client redis.UniversalClient, keyPrefix string, defaultTTL time.Duration, opts ...Option[V]) interfaces.Cache[K, V] {
+	return newRemoteCache[K, V](client, keyPrefix, defaultTTL, opts...)
+}
+
+func newRemoteCache[K comparable, V any](client redis.UniversalClient, keyPrefix string, defaultTTL time.Duration, opts ...Option[V]) *remoteCache[K, V] {
+	o := newOptions(opts)
+	if o.codec == nil {
+		o.codec = JSONCodec[V]()
+	}
+
+	return &remoteCache[K, V]{
+		client:     client,
+		prefix:     keyPrefix,
+		key:        newKeyEncoder[K](keyPrefix),
+		codec:      o.codec,
+		options:    o,
+		defaultTTL: defaultTTL,
+		loader:     newLoader[K, V](remoteNegativeEntries, o),
+	}
+}
+
+// newRemoteCacheFromConfig подключается к серверу из config, клиент закрывается вместе с кэшем
+func newRemoteCacheFromConfig[K comparable, V any](config RedisConfig, defaultTTL time.Duration, opts ...Option[V]) *remoteCache[K, V] {
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.Addr,
+		Password: config.Password,
+		DB:       config.DB,
+	})
+
+	ttl := config.TTL
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+
+	c := newRemoteCache[K, V](client, config.KeyPrefix, ttl, opts...)
+	c.ownsClient = true
+	return c
+}
+
+// Set записывает значение с TTL. Redis хранит время жизни с точностью до миллисекунды,
+// поэтому TTL меньше миллисекунды округляется вверх, а неположительный не сохраняется вовсе
+func (c *remoteCache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Duration) {
+	itemTTL := resolveTTL(ttl, c.defaultTTL)
+
+	c.loader.forget(ctx, key)
+
+	if itemTTL <= 0 {
+		c.Delete(ctx, key)
+		return
+	}
+
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		log.Printf("Failed to encode cache value for key %v: %v", key, err)
+		return
+	}
+
+	if err := c.client.Set(ctx, c.key(key), data, redisTTL(c.options.jitter(itemTTL))).Err(); err != nil {
+		log.Printf("Failed to write key %v to remote cache: %v", key, err)
+	}
+}
+
+// Get читает значение. Ошибки сервера и повреждённые значения считаются промахом,
+// чтобы недоступность кэша не ломала чтение из источника
+func (c *remoteCache[K, V]) Get(ctx context.Context, key K) (V, bool) {
+	var zero V
+
+	data, err := c.client.Get(ctx, c.key(key)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("Failed to read key %v from remote cache: %v", key, err)
+		}
+		c.stats.miss()
+		return zero, false
+	}
+
+	value, ok := c.decode(ctx, key, data)
+	if !ok {
+		c.stats.miss()
+		return zero, false
+	}
+	c.stats.hit()
+	return value, true
+}
+
+// GetOrLoad возвращает значение из кэша, а при промахе загружает его через load.
+// Загрузки объединяются в пределах реплики
+func (c *remoteCache[K, V]) GetOrLoad(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error) {
+	return c.loader.getOrLoad(ctx, c, key, load)
+}
+
+// Delete удаляет элемент из кэша по ключу
+func (c *remoteCache[K, V]) Delete(ctx context.Context, key K) {
+	c.loader.forget(ctx, key)
+
+	if err := c.client.Del(ctx, c.key(key)).Err(); err != nil {
+		log.Printf("Failed to delete key %v from remote cache: %v", key, err)
+	}
+}
+
+// Flush удаляет все ключи с префиксом этого кэша. Остальные данные на сервере не затрагиваются.
+// Ключи удаляются после полного прохода SCAN: не все совместимые серверы выдерживают удаление
+// во время обхода без пропусков
+func (c *remoteCache[K, V]) Flush(ctx context.Context) {
+	c.loader.forgetAll(ctx)
+
+	var keys []string
+	iter := c.client.Scan(ctx, 0, escapePattern(c.prefix)+"*", flushBatch).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		log.Printf("Failed to scan remote cache: %v", err)
+	}
+
+	for start := 0; start < len(keys); start += flushBatch {
+		end := min(start+flushBatch, len(keys))
+		if err := c.client.Unlink(ctx, keys[start:end]...).Err(); err != nil {
+			log.Printf("Failed to flush remote cache: %v", err)
+		}
+	}
+}
+
+// Inspect возвращает значение и срок жизни одним конвейером, не учитывая обращение в статистике
+func (c *remoteCache[K, V]) Inspect(ctx context.Context, key K) (domain.CacheEntry[V], bool) {
+	pipe := c.client.Pipeline()
+	get := pipe.Get(ctx, c.key(key))
+	pttl := pipe.PTTL(ctx, c.key(key))
+	if _, err := pipe.Exec(ctx); err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("Failed to inspect key %v in remote cache: %v", key, err)
+		}
+		return domain.CacheEntry[V]{}, false
+	}
+
+	value, ok := c.decode(ctx, key, []byte(get.Val()))
+	if !ok {
+		return domain.CacheEntry[V]{}, false
+	}
+
+	entry := domain.CacheEntry[V]{Value: value}
+	// Отрицательный PTTL означает ключ без срока жизни
+	if ttl := pttl.Val(); ttl > 0 {
+		entry.ExpiresAt = time.Now().Add(ttl)
+	}
+	return entry, true
+}
+
+// GetMany читает ключи одним конвейером и возвращает найденные
+func (c *remoteCache[K, V]) GetMany(ctx context.Context, keys []K) map[K]V {
+	found := make(map[K]V, len(keys))
+	if len(keys) == 0 {
+		return found
+	}
+
+	pipe := c.client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Get(ctx, c.key(key))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("Failed to read keys from remote cache: %v", err)
+	}
+
+	for i, cmd := range cmds {
+		data, err := cmd.Bytes()
+		if err != nil {
+			c.stats.miss()
+			continue
+		}
+		value, ok := c.decode(ctx, keys[i], data)
+		if !ok {
+			c.stats.miss()
+			continue
+		}
+		c.stats.hit()
+		found[keys[i]] = value
+	}
+	return found
+}
+
+// SetMany записывает значения одним конвейером с общим TTL
+func (c *remoteCache[K, V]) SetMany(ctx context.Context, values map[K]V, ttl ...time.Duration) {
+	itemTTL := resolveTTL(ttl, c.defaultTTL)
+
+	keys := make([]K, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	if itemTTL <= 0 {
+		c.DeleteMany(ctx, keys)
+		return
+	}
+
+	pipe := c.client.Pipeline()
+	for _, key := range keys {
+		c.loader.forget(ctx, key)
+
+		data, err := c.codec.Marshal(values[key])
+		if err != nil {
+			log.Printf("Failed to encode cache value for key %v: %v", key, err)
+			continue
+		}
+		pipe.Set(ctx, c.key(key), data, redisTTL(c.options.jitter(itemTTL)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Failed to write keys to remote cache: %v", err)
+	}
+}
+
+// DeleteMany удаляет ключи одной командой
+func (c *remoteCache[K, V]) DeleteMany(ctx context.Context, keys []K) {
+	if len(keys) == 0 {
+		return
+	}
+
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		c.loader.forget(ctx, key)
+		redisKeys[i] = c.key(key)
+	}
+	if err := c.client.Del(ctx, redisKeys...).Err(); err != nil {
+		log.Printf("Failed to delete keys from remote cache: %v", err)
+	}
+}
+
+// Hottest ничего не возвращает: частота обращений к ключам известна только серверу,
+// а удалённый кэш и так переживает перезапуск реплики
+func (c *remoteCache[K, V]) Hottest(ctx context.Context, limit int) []domain.CacheItem[K, V] {
+	return nil
+}
+
+// OnEvict ничего не делает: вытеснение и истечение TTL происходят на сервере, и реплика о них не узнаёт
+func (c *remoteCache[K, V]) OnEvict(fn func(key K, value V, reason domain.EvictionReason)) {}
+
+// Stats возвращает статистику обращений этой реплики к удалённому кэшу
+func (c *remoteCache[K, V]) Stats() domain.CacheStats {
+	return c.stats.snapshot(0, 0)
+}
+
+// RecordLoad учитывает загрузку значения из источника
+func (c *remoteCache[K, V]) RecordLoad(d time.Duration) {
+	c.stats.recordLoad(d)
+}
+
+// Close закрывает подключение, если кэш создавал его сам
+func (c *remoteCache[K, V]) Close() error {
+	if !c.ownsClient {
+		return nil
+	}
+	if err := c.client.Close(); err != nil {
+		return fmt.Errorf("failed to close remote cache client: %w", err)
+	}
+	return nil
+}
+
+// decode разбирает значение. Значение, которое не удалось разобрать (например, после смены формата),
+// удаляется, чтобы следующая загрузка записала его заново
+func (c *remoteCache[K, V]) decode(ctx context.Context, key K, data []byte) (V, bool) {
+	value, err := c.codec.Unmarshal(data)
+	if err != nil {
+		log.Printf("Failed to decode cache value for key %v, dropping it: %v", key, err)
+		c.client.Del(ctx, c.key(key))
+		return value, false
+	}
+	return value, true
+}
+
+// redisTTL округляет TTL вверх до миллисекунды: Redis не принимает меньшие значения
+func redisTTL(ttl time.Duration) time.Duration {
+	if rem := ttl % time.Millisecond; rem != 0 {
+		ttl += time.Millisecond - rem
+	}
+	return ttl
+}
+
+// newKeyEncoder строит ключ Redis из префикса и ключа кэша
+func newKeyEncoder[K comparable](prefix string) func(K) string {
+	var zero K
+	if _, ok := any(zero).(string); ok {
+		return func(key K) string {
+			return prefix + any(key).(string)
+		}
+	}
+	return func(key K) string {
+		return prefix + fmt.Sprint(key)
+	}
+}
+
+// escapePattern экранирует спецсимволы glob-шаблона SCAN в префиксе
+func escapePattern(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`).Replace(prefix)
+}
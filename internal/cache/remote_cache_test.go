@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRemoteCache поднимает in-process сервер с протоколом Redis
+func newTestRemoteCache[V any](t *testing.T, defaultTTL time.Duration, opts ...Option[V]) (*remoteCache[string, V], *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return newRemoteCache[string, V](client, "orders:", defaultTTL, opts...), server
+}
+
+func TestRemoteCache_SetGet(t *testing.T) {
+	cache, server := newTestRemoteCache[*domain.Order](t, time.Minute)
+	ctx := context.Background()
+
+	order := &domain.Order{
+		OrderID:     "order1",
+		RecipientID: "recipient1",
+		Status:      domain.OrderStatusNew,
+		ExpiryDate:  time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		Weight:      1.5,
+	}
+	cache.Set(ctx, order.OrderID, order)
+
+	assert.True(t, server.Exists("orders:order1"))
+	assert.Equal(t, time.Minute, server.TTL("orders:order1"))
+
+	got, found := cache.Get(ctx, "order1")
+	require.True(t, found)
+	assert.Equal(t, order, got)
+	assert.NotSame(t, order, got)
+
+	_, found = cache.Get(ctx, "order2")
+	assert.False(t, found)
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+}
+
+func TestRemoteCache_TTL(t *testing.T) {
+	cache, server := newTestRemoteCache[string](t, time.Minute)
+	ctx := context.Background()
+
+	cache.Set(ctx, "short", "value", 1500*time.Microsecond)
+	assert.Equal(t, 2*time.Millisecond, server.TTL("orders:short"), "TTL must be rounded up to milliseconds")
+
+	cache.Set(ctx, "key", "value", 10*time.Second)
+	entry, found := cache.Inspect(ctx, "key")
+	require.True(t, found)
+	assert.Equal(t, "value", entry.Value)
+	assert.WithinDuration(t, time.Now().Add(10*time.Second), entry.ExpiresAt, time.Second)
+
+	server.FastForward(11 * time.Second)
+	_, found = cache.Get(ctx, "key")
+	assert.False(t, found)
+
+	cache.Set(ctx, "expired", "value", 0)
+	assert.False(t, server.Exists("orders:expired"))
+}
+
+func TestRemoteCache_ProtoCodec(t *testing.T) {
+	cache, server := newTestRemoteCache(t, time.Minute, WithCodec(ProtoCodec[wrapperspb.StringValue]()))
+	ctx := context.Background()
+
+	cache.Set(ctx, "key", wrapperspb.String("value"))
+
+	raw, err := server.Get("orders:key")
+	require.NoError(t, err)
+	assert.NotContains(t, raw, "{", "value must be stored in protobuf wire format")
+
+	got, found := cache.Get(ctx, "key")
+	require.True(t, found)
+	assert.Equal(t, "value", got.GetValue())
+}
+
+func TestRemoteCache_CorruptedValueIsDropped(t *testing.T) {
+	cache, server := newTestRemoteCache[*domain.Order](t, time.Minute)
+	ctx := context.Background()
+
+	require.NoError(t, server.Set("orders:order1", "not json"))
+
+	_, found := cache.Get(ctx, "order1")
+	assert.False(t, found)
+	assert.False(t, server.Exists("orders:order1"))
+}
+
+func TestRemoteCache_FlushKeepsForeignKeys(t *testing.T) {
+	cache, server := newTestRemoteCache[int](t, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 1200; i++ {
+		cache.Set(ctx, strconv.Itoa(i), i)
+	}
+	require.NoError(t, server.Set("sessions:1", "keep"))
+
+	cache.Flush(ctx)
+
+	assert.Equal(t, []string{"sessions:1"}, server.Keys())
+}
+
+func TestRemoteCache_BatchOperations(t *testing.T) {
+	cache, server := newTestRemoteCache[int](t, time.Minute)
+	ctx := context.Background()
+
+	cache.SetMany(ctx, map[string]int{"a": 1, "b": 2, "c": 3}, 30*time.Second)
+	assert.Equal(t, 30*time.Second, server.TTL("orders:b"))
+
+	found := cache.GetMany(ctx, []string{"a", "b", "missing"})
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, found)
+
+	cache.DeleteMany(ctx, []string{"a", "c"})
+	assert.Equal(t, []string{"orders:b"}, server.Keys())
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+}
+
+func TestRemoteCache_ServerDownIsAMiss(t *testing.T) {
+	cache, server := newTestRemoteCache[string](t, time.Minute)
+	ctx := context.Background()
+
+	cache.Set(ctx, "key", "value")
+	server.Close()
+
+	_, found := cache.Get(ctx, "key")
+	assert.False(t, found)
+
+	value, err := cache.GetOrLoad(ctx, "key", func(ctx context.Context) (string, error) {
+		return "from source", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "from source", value)
+}
+
+func TestTieredCache(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	c, err := NewCache[string, string](CacheConfig{
+		Strategy:        LRUStrategy,
+		MaxEntries:      10,
+		DefaultTTL:      time.Minute,
+		CleanupInterval: time.Minute,
+		Backend:         TieredBackend,
+		Redis: RedisConfig{
+			Addr:      server.Addr(),
+			KeyPrefix: "orders:",
+			TTL:       time.Hour,
+		},
+	})
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx := context.Background()
+	tiered := c.(*tieredCache[string, string])
+
+	c.Set(ctx, "order1", "value")
+	assert.Equal(t, time.Hour, server.TTL("orders:order1"), "remote TTL comes from the Redis config")
+
+	// Значение, записанное другой репликой, читается из удалённого кэша и оседает в локальном
+	require.NoError(t, server.Set("orders:order2", `"from replica b"`))
+	value, found := c.Get(ctx, "order2")
+	require.True(t, found)
+	assert.Equal(t, "from replica b", value)
+	_, found = tiered.local.Inspect(ctx, "order2")
+	assert.True(t, found)
+
+	loads := 0
+	for i := 0; i < 3; i++ {
+		value, err := c.GetOrLoad(ctx, "order3", func(ctx context.Context) (string, error) {
+			loads++
+			return "from source", nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "from source", value)
+	}
+	assert.Equal(t, 1, loads)
+	assert.True(t, server.Exists("orders:order3"))
+
+	tiered.InvalidateLocal(ctx, []string{"order2"})
+	_, found = tiered.local.Inspect(ctx, "order2")
+	assert.False(t, found)
+	assert.True(t, server.Exists("orders:order2"), "local invalidation must keep the shared entry")
+
+	c.Delete(ctx, "order1")
+	assert.False(t, server.Exists("orders:order1"))
+	_, found = tiered.local.Inspect(ctx, "order1")
+	assert.False(t, found)
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(1), stats.Loads)
+	assert.Equal(t, uint64(3), stats.Hits, "order2 from remote, order3 twice from local")
+}
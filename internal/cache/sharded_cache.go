@@ -0,0 +1,259 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"hash/maphash"
+	"reflect"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+)
+
+// segment — часть шардированного кэша со своей блокировкой и инкрементальной очисткой
+type segment[K comparable, V any] interface {
+	interfaces.Cache[K, V]
+	sweepExpired(limit int) (scanned, removed int)
+}
+
+// shardedCache распределяет ключи по независимо блокируемым сегментам,
+// чтобы параллельные Get по разным ключам не ждали друг друга
+type shardedCache[K comparable, V any] struct {
+	shards  []segment[K, V]
+	hash    func(K) uint64
+	janitor *janitor
+}
+
+// NewShardedCache создает кэш из shards сегментов стратегии strategy.
+// Лимит maxEntries и бюджет стоимости делятся между сегментами поровну
+func NewShardedCache[K comparable, V any](strategy CacheStrategy, shards, maxEntries int, defaultTTL, cleanupInterval time.Duration, opts ...Option[V]) (interfaces.Cache[K, V], error) {
+	if shards < 1 {
+		return nil, fmt.Errorf("invalid number of cache shards: %d", shards)
+	}
+
+	perShard := (maxEntries + shards - 1) / shards
+	if maxCost := newOptions(opts).maxCost; maxCost > 0 {
+		opts = append(opts[:len(opts):len(opts)], withMaxCost[V]((maxCost+int64(shards)-1)/int64(shards)))
+	}
+
+	c := &shardedCache[K, V]{
+		shards: make([]segment[K, V], shards),
+		hash:   newKeyHasher[K](maphash.MakeSeed()),
+	}
+	for i := range c.shards {
+		switch strategy {
+		case LRUStrategy:
+			c.shards[i] = newLRUSegment[K, V](perShard, defaultTTL, opts...)
+		case LFUStrategy:
+			c.shards[i] = newLFUSegment[K, V](perShard, defaultTTL, opts...)
+		case TinyLFUStrategy:
+			c.shards[i] = newTinyLFUSegment[K, V](perShard, defaultTTL, opts...)
+		default:
+			return nil, fmt.Errorf("unsupported cache strategy: %v", strategy)
+		}
+	}
+	c.janitor = startJanitor(cleanupInterval, c.cleanupExpired)
+
+	return c, nil
+}
+
+// Set добавляет или обновляет элемент в сегменте ключа
+func (c *shardedCache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Duration) {
+	c.shardFor(key).Set(ctx, key, value, ttl...)
+}
+
+// Get возвращает значение элемента по ключу
+func (c *shardedCache[K, V]) Get(ctx context.Context, key K) (V, bool) {
+	return c.shardFor(key).Get(ctx, key)
+}
+
+// GetOrLoad возвращает значение из сегмента ключа, загрузки объединяются внутри сегмента
+func (c *shardedCache[K, V]) GetOrLoad(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error) {
+	return c.shardFor(key).GetOrLoad(ctx, key, load)
+}
+
+// Delete удаляет элемент из кэша по ключу
+func (c *shardedCache[K, V]) Delete(ctx context.Context, key K) {
+	c.shardFor(key).Delete(ctx, key)
+}
+
+// Flush очищает все сегменты по очереди
+func (c *shardedCache[K, V]) Flush(ctx context.Context) {
+	for _, shard := range c.shards {
+		shard.Flush(ctx)
+	}
+}
+
+// GetMany группирует ключи по сегментам, чтобы каждый сегмент блокировался один раз
+func (c *shardedCache[K, V]) GetMany(ctx context.Context, keys []K) map[K]V {
+	found := make(map[K]V, len(keys))
+	for shard, shardKeys := range c.groupKeys(keys) {
+		for key, value := range shard.GetMany(ctx, shardKeys) {
+			found[key] = value
+		}
+	}
+	return found
+}
+
+// SetMany группирует значения по сегментам и записывает каждую группу под одной блокировкой
+func (c *shardedCache[K, V]) SetMany(ctx context.Context, values map[K]V, ttl ...time.Duration) {
+	groups := make(map[segment[K, V]]map[K]V)
+	for key, value := range values {
+		shard := c.shardFor(key)
+		if groups[shard] == nil {
+			groups[shard] = make(map[K]V)
+		}
+		groups[shard][key] = value
+	}
+	for shard, group := range groups {
+		shard.SetMany(ctx, group, ttl...)
+	}
+}
+
+// DeleteMany группирует ключи по сегментам и удаляет каждую группу под одной блокировкой
+func (c *shardedCache[K, V]) DeleteMany(ctx context.Context, keys []K) {
+	for shard, shardKeys := range c.groupKeys(keys) {
+		shard.DeleteMany(ctx, shardKeys)
+	}
+}
+
+// Inspect возвращает элемент из сегмента ключа
+func (c *shardedCache[K, V]) Inspect(ctx context.Context, key K) (domain.CacheEntry[V], bool) {
+	return c.shardFor(key).Inspect(ctx, key)
+}
+
+// Hottest чередует самые востребованные элементы сегментов: сравнивать позиции из разных сегментов
+// нельзя, поэтому первыми идут лидеры всех сегментов, затем вторые и так далее
+func (c *shardedCache[K, V]) Hottest(ctx context.Context, limit int) []domain.CacheItem[K, V] {
+	perShard := make([][]domain.CacheItem[K, V], len(c.shards))
+	total := 0
+	for i, shard := range c.shards {
+		perShard[i] = shard.Hottest(ctx, limit)
+		total += len(perShard[i])
+	}
+	if limit > 0 {
+		total = min(total, limit)
+	}
+
+	items := make([]domain.CacheItem[K, V], 0, total)
+	for rank := 0; len(items) < total; rank++ {
+		for _, shardItems := range perShard {
+			if rank < len(shardItems) && len(items) < total {
+				items = append(items, shardItems[rank])
+			}
+		}
+	}
+	return items
+}
+
+// OnEvict подписывает fn на удаления во всех сегментах
+func (c *shardedCache[K, V]) OnEvict(fn func(key K, value V, reason domain.EvictionReason)) {
+	for _, shard := range c.shards {
+		shard.OnEvict(fn)
+	}
+}
+
+// Stats суммирует статистику всех сегментов, включая загрузки: их учитывает сегмент, выполнивший GetOrLoad
+func (c *shardedCache[K, V]) Stats() domain.CacheStats {
+	var stats domain.CacheStats
+	for _, shard := range c.shards {
+		stats = stats.Add(shard.Stats())
+	}
+	return stats
+}
+
+// RecordLoad учитывает загрузку значения не через GetOrLoad. Ключ загрузки неизвестен, поэтому она
+// записывается в первый сегмент, а в Stats всё равно суммируется со всеми
+func (c *shardedCache[K, V]) RecordLoad(d time.Duration) {
+	c.shards[0].RecordLoad(d)
+}
+
+// cleanupExpired очищает сегменты по одному, блокируя только очищаемый
+func (c *shardedCache[K, V]) cleanupExpired() {
+	for _, shard := range c.shards {
+		sweepIncrementally(shard.sweepExpired)
+	}
+}
+
+// Close останавливает процесс очистки и освобождает ресурсы
+func (c *shardedCache[K, V]) Close() error {
+	c.janitor.Stop()
+	return nil
+}
+
+func (c *shardedCache[K, V]) shardFor(key K) segment[K, V] {
+	return c.shards[c.hash(key)%uint64(len(c.shards))]
+}
+
+func (c *shardedCache[K, V]) groupKeys(keys []K) map[segment[K, V]][]K {
+	groups := make(map[segment[K, V]][]K)
+	for _, key := range keys {
+		shard := c.shardFor(key)
+		groups[shard] = append(groups[shard], key)
+	}
+	return groups
+}
+
+// newKeyHasher выбирает функцию хэширования один раз при создании кэша. Ключи встроенных типов
+// хэшируются без рефлексии, именованные типы поверх строк и целых — через reflect, остальные — по fmt.Sprint
+func newKeyHasher[K comparable](seed maphash.Seed) func(K) uint64 {
+	var zero K
+	switch any(zero).(type) {
+	case string:
+		return func(key K) uint64 {
+			return maphash.String(seed, any(key).(string))
+		}
+	case int:
+		return func(key K) uint64 {
+			return mix64(uint64(any(key).(int)))
+		}
+	case int64:
+		return func(key K) uint64 {
+			return mix64(uint64(any(key).(int64)))
+		}
+	case uint64:
+		return func(key K) uint64 {
+			return mix64(any(key).(uint64))
+		}
+	case int32:
+		return func(key K) uint64 {
+			return mix64(uint64(any(key).(int32)))
+		}
+	case uint32:
+		return func(key K) uint64 {
+			return mix64(uint64(any(key).(uint32)))
+		}
+	}
+
+	// У ключа-интерфейса нет своего типа, его значения хэшируются по fmt.Sprint
+	if keyType := reflect.TypeOf(zero); keyType != nil {
+		switch keyType.Kind() {
+		case reflect.String:
+			return func(key K) uint64 {
+				return maphash.String(seed, reflect.ValueOf(key).String())
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return func(key K) uint64 {
+				return mix64(uint64(reflect.ValueOf(key).Int()))
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return func(key K) uint64 {
+				return mix64(reflect.ValueOf(key).Uint())
+			}
+		}
+	}
+	return func(key K) uint64 {
+		return maphash.String(seed, fmt.Sprint(key))
+	}
+}
+
+// mix64 — финализатор splitmix64, чтобы последовательные числа расходились по сегментам
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
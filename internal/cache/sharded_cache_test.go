@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"context"
+	"hash/maphash"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestShardedCache[K comparable, V any](t *testing.T, strategy CacheStrategy, shards, maxEntries int, defaultTTL, cleanupInterval time.Duration) *shardedCache[K, V] {
+	c, err := NewShardedCache[K, V](strategy, shards, maxEntries, defaultTTL, cleanupInterval)
+	require.NoError(t, err)
+	return c.(*shardedCache[K, V])
+}
+
+func TestShardedCache_SetGet(t *testing.T) {
+	for name, strategy := range map[string]CacheStrategy{"LRU": LRUStrategy, "LFU": LFUStrategy} {
+		t.Run(name, func(t *testing.T) {
+			cache := newTestShardedCache[string, string](t, strategy, 4, 100, 5*time.Second, 1*time.Second)
+			defer cache.Close()
+
+			ctx := context.Background()
+
+			cache.Set(ctx, "key1", "value1")
+			cache.Set(ctx, "key2", "value2")
+
+			val, found := cache.Get(ctx, "key1")
+			require.True(t, found)
+			assert.Equal(t, "value1", val)
+
+			cache.Delete(ctx, "key1")
+			_, found = cache.Get(ctx, "key1")
+			assert.False(t, found, "key1 should have been deleted")
+
+			cache.Flush(ctx)
+			_, found = cache.Get(ctx, "key2")
+			assert.False(t, found, "key2 should have been flushed")
+		})
+	}
+}
+
+func TestShardedCache_KeysSpreadAcrossShards(t *testing.T) {
+	cache := newTestShardedCache[int, int](t, LRUStrategy, 8, 8000, 5*time.Second, 1*time.Second)
+	defer cache.Close()
+
+	counts := make(map[segment[int, int]]int)
+	for i := 0; i < 8000; i++ {
+		counts[cache.shardFor(i)]++
+	}
+
+	assert.Len(t, counts, 8)
+	for _, count := range counts {
+		assert.InDelta(t, 1000, count, 200)
+	}
+}
+
+func TestShardedCache_StatsCountLoadsOnce(t *testing.T) {
+	cache := newTestShardedCache[int, int](t, LRUStrategy, 4, 100, time.Minute, time.Minute)
+	defer cache.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 8; i++ {
+		_, err := cache.GetOrLoad(ctx, i, func(ctx context.Context) (int, error) { return i, nil })
+		require.NoError(t, err)
+	}
+	cache.RecordLoad(time.Millisecond)
+
+	assert.Equal(t, uint64(9), cache.Stats().Loads)
+}
+
+func TestShardedCache_TTLExpiration(t *testing.T) {
+	cache := newTestShardedCache[int, int](t, LRUStrategy, 4, 1000, 500*time.Millisecond, 200*time.Millisecond)
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	for i := 0; i < 500; i++ {
+		cache.Set(ctx, i, i)
+	}
+	time.Sleep(1 * time.Second)
+
+	for _, shard := range cache.shards {
+		lru := shard.(*lruCache[int, int])
+		lru.mu.RLock()
+		size := len(lru.cache)
+		lru.mu.RUnlock()
+		assert.Zero(t, size, "expired entries should have been swept in the background")
+	}
+}
+
+func TestShardedCache_InvalidShards(t *testing.T) {
+	_, err := NewShardedCache[string, string](LRUStrategy, 0, 100, 5*time.Second, 1*time.Second)
+	assert.Error(t, err)
+}
+
+func TestShardedCache_ConcurrentAccess(t *testing.T) {
+	cache := newTestShardedCache[int, int](t, LFUStrategy, 16, 10000, 5*time.Second, 1*time.Second)
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(id int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				cache.Set(ctx, id*1000+j, id*1000+j)
+			}
+		}(i)
+		go func(id int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				cache.Get(ctx, id*1000+j)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	val, found := cache.Get(ctx, 5000)
+	assert.True(t, found)
+	assert.Equal(t, 5000, val)
+}
+
+type orderID string
+
+type shardNumber uint16
+
+func TestNewKeyHasher(t *testing.T) {
+	seed := maphash.MakeSeed()
+
+	t.Run("Strings", func(t *testing.T) {
+		hash := newKeyHasher[string](seed)
+		assert.Equal(t, hash("order1"), hash("order1"))
+		assert.NotEqual(t, hash("order1"), hash("order2"))
+	})
+
+	t.Run("Named string matches its underlying type", func(t *testing.T) {
+		assert.Equal(t, newKeyHasher[string](seed)("order1"), newKeyHasher[orderID](seed)("order1"))
+	})
+
+	t.Run("Integers", func(t *testing.T) {
+		assert.Equal(t, mix64(42), newKeyHasher[int](seed)(42))
+		assert.Equal(t, mix64(42), newKeyHasher[int64](seed)(42))
+		assert.Equal(t, mix64(42), newKeyHasher[uint64](seed)(42))
+		assert.Equal(t, mix64(42), newKeyHasher[int32](seed)(42))
+		assert.Equal(t, mix64(42), newKeyHasher[uint32](seed)(42))
+		assert.Equal(t, mix64(42), newKeyHasher[shardNumber](seed)(42))
+		assert.Equal(t, newKeyHasher[int](seed)(-1), newKeyHasher[int64](seed)(-1))
+	})
+
+	t.Run("Other keys", func(t *testing.T) {
+		type pair struct{ a, b int }
+		hash := newKeyHasher[pair](seed)
+		assert.Equal(t, hash(pair{1, 2}), hash(pair{1, 2}))
+		assert.NotEqual(t, hash(pair{1, 2}), hash(pair{2, 1}))
+
+		anyHash := newKeyHasher[any](seed)
+		assert.Equal(t, anyHash("order1"), anyHash("order1"))
+	})
+}
+
+func TestNewKeyHasher_DoesNotAllocate(t *testing.T) {
+	seed := maphash.MakeSeed()
+	hashString := newKeyHasher[string](seed)
+	hashInt := newKeyHasher[int](seed)
+	key := "order1"
+
+	allocs := testing.AllocsPerRun(100, func() {
+		hashString(key)
+		hashInt(42)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,81 @@
+package cache
+
+// countMinSketch приблизительно считает частоту обращений к ключам в фиксированном объёме памяти.
+// Счётчики ограничены 15 и периодически делятся пополам, поэтому давно популярные ключи
+// со временем теряют вес и не занимают кэш навсегда
+type countMinSketch struct {
+	counters   []uint8
+	mask       uint64
+	additions  int
+	sampleSize int
+}
+
+const sketchDepth = 4
+
+// sketchSeeds разводят строки таблицы, чтобы коллизии в одной строке не повторялись в другой
+var sketchSeeds = [sketchDepth]uint64{0xc3a5c85c97cb3127, 0xb492b66fbe98f273, 0x9ae16a3b2f90404f, 0xcbf29ce484222325}
+
+// newCountMinSketch создает sketch для кэша на capacity элементов. Ширина строки берётся
+// с запасом относительно capacity, чтобы редкие ключи реже делили счётчики с популярными
+func newCountMinSketch(capacity int) *countMinSketch {
+	if capacity < 1 {
+		capacity = 1
+	}
+	width := 16
+	for width < 4*capacity {
+		width <<= 1
+	}
+	return &countMinSketch{
+		counters:   make([]uint8, width*sketchDepth),
+		mask:       uint64(width - 1),
+		sampleSize: 10 * capacity,
+	}
+}
+
+func (s *countMinSketch) index(hash uint64, row int) int {
+	h := mix64(hash ^ sketchSeeds[row])
+	return row*int(s.mask+1) + int(h&s.mask)
+}
+
+// Increment учитывает обращение к ключу и при накоплении sampleSize обращений старит счётчики
+func (s *countMinSketch) Increment(hash uint64) {
+	added := false
+	for row := 0; row < sketchDepth; row++ {
+		i := s.index(hash, row)
+		if s.counters[i] < 15 {
+			s.counters[i]++
+			added = true
+		}
+	}
+	if added {
+		s.additions++
+		if s.additions >= s.sampleSize {
+			s.reset()
+		}
+	}
+}
+
+// Estimate возвращает оценку частоты ключа сверху
+func (s *countMinSketch) Estimate(hash uint64) uint8 {
+	estimate := uint8(15)
+	for row := 0; row < sketchDepth; row++ {
+		if c := s.counters[s.index(hash, row)]; c < estimate {
+			estimate = c
+		}
+	}
+	return estimate
+}
+
+func (s *countMinSketch) reset() {
+	for i := range s.counters {
+		s.counters[i] >>= 1
+	}
+	s.additions /= 2
+}
+
+func (s *countMinSketch) Clear() {
+	for i := range s.counters {
+		s.counters[i] = 0
+	}
+	s.additions = 0
+}
@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+)
+
+// snapshotVersion меняется при несовместимом изменении формата снимка
+const snapshotVersion = 1
+
+// snapshotHeader — первая строка файла снимка
+type snapshotHeader struct {
+	Version int       `json:"version"`
+	SavedAt time.Time `json:"saved_at"`
+	Items   int       `json:"items"`
+}
+
+// snapshotItem — строка файла снимка с одним элементом, значение закодировано кодеком кэша
+type snapshotItem[K comparable] struct {
+	Key       K         `json:"key"`
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SaveSnapshot записывает до limit самых востребованных элементов кэша в файл path построчно в JSON.
+// Файл сначала пишется рядом под временным именем и затем переименовывается, поэтому
+// прерванная запись не портит предыдущий снимок. Если codec равен nil, значения кодируются в JSON
+func SaveSnapshot[K comparable, V any](ctx context.Context, c interfaces.Cache[K, V], path string, limit int, codec Codec[V]) (int, error) {
+	if codec == nil {
+		codec = JSONCodec[V]()
+	}
+	items := c.Hottest(ctx, limit)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	lines := make([]snapshotItem[K], 0, len(items))
+	for _, item := range items {
+		data, err := codec.Marshal(item.Value)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode cache value for key %v: %w", item.Key, err)
+		}
+		lines = append(lines, snapshotItem[K]{Key: item.Key, Value: data, ExpiresAt: item.ExpiresAt})
+	}
+
+	if err := enc.Encode(snapshotHeader{Version: snapshotVersion, SavedAt: time.Now(), Items: len(lines)}); err != nil {
+		return 0, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	for _, line := range lines {
+		if err := enc.Encode(line); err != nil {
+			return 0, fmt.Errorf("failed to write snapshot: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return 0, fmt.Errorf("failed to sync snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("failed to replace snapshot: %w", err)
+	}
+	return len(lines), nil
+}
+
+// LoadSnapshot загружает в кэш элементы из снимка path, сохранённого SaveSnapshot. Каждый элемент
+// получает оставшийся у него TTL, истёкшие пропускаются. Самые востребованные элементы записываются
+// последними, чтобы стратегия вытеснения считала их самыми свежими. Отсутствие файла не ошибка
+func LoadSnapshot[K comparable, V any](ctx context.Context, c interfaces.Cache[K, V], path string, codec Codec[V]) (int, error) {
+	if codec == nil {
+		codec = JSONCodec[V]()
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return 0, fmt.Errorf("failed to read snapshot header: %w", err)
+	}
+	if header.Version != snapshotVersion {
+		return 0, fmt.Errorf("unsupported snapshot version %d", header.Version)
+	}
+
+	var lines []snapshotItem[K]
+	for {
+		var line snapshotItem[K]
+		if err := dec.Decode(&line); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return 0, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		lines = append(lines, line)
+	}
+
+	loaded := 0
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := lines[i]
+		ttl := time.Until(line.ExpiresAt)
+		if ttl <= 0 {
+			continue
+		}
+		value, err := codec.Unmarshal(line.Value)
+		if err != nil {
+			return loaded, fmt.Errorf("failed to decode cache value for key %v: %w", line.Key, err)
+		}
+		c.Set(ctx, line.Key, value, ttl)
+		loaded++
+	}
+	return loaded, nil
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot_SaveLoad(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+
+	src := NewLRUCache[string, *domain.Order](10, time.Hour, time.Minute)
+	defer src.Close()
+	src.Set(ctx, "cold", &domain.Order{OrderID: "cold"})
+	src.Set(ctx, "warm", &domain.Order{OrderID: "warm"}, 30*time.Minute)
+	src.Set(ctx, "expired", &domain.Order{OrderID: "expired"}, time.Millisecond)
+	src.Set(ctx, "hot", &domain.Order{OrderID: "hot", Status: domain.OrderStatusNew})
+	time.Sleep(5 * time.Millisecond)
+
+	saved, err := SaveSnapshot(ctx, src, path, 2, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, saved, "limit keeps only the hottest live entries")
+
+	dst := NewLRUCache[string, *domain.Order](10, time.Hour, time.Minute)
+	defer dst.Close()
+	loaded, err := LoadSnapshot(ctx, dst, path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loaded)
+
+	hottest := dst.Hottest(ctx, 0)
+	require.Len(t, hottest, 2)
+	assert.Equal(t, "hot", hottest[0].Key, "the hottest entry is restored as the most recent")
+	assert.Equal(t, domain.OrderStatusNew, hottest[0].Value.Status)
+	assert.Equal(t, "warm", hottest[1].Key)
+
+	entry, found := dst.Inspect(ctx, "warm")
+	require.True(t, found)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), entry.ExpiresAt, time.Second, "the remaining TTL is preserved")
+}
+
+func TestSnapshot_SkipsExpiredEntries(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+
+	src := NewLRUCache[int, int](10, time.Hour, time.Minute)
+	defer src.Close()
+	src.Set(ctx, 1, 1, 20*time.Millisecond)
+	src.Set(ctx, 2, 2)
+
+	_, err := SaveSnapshot(ctx, src, path, 0, nil)
+	require.NoError(t, err)
+	time.Sleep(30 * time.Millisecond)
+
+	dst := NewLRUCache[int, int](10, time.Hour, time.Minute)
+	defer dst.Close()
+	loaded, err := LoadSnapshot(ctx, dst, path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loaded)
+
+	_, found := dst.Get(ctx, 1)
+	assert.False(t, found)
+}
+
+func TestSnapshot_MissingFile(t *testing.T) {
+	c := NewLRUCache[int, int](10, time.Hour, time.Minute)
+	defer c.Close()
+
+	loaded, err := LoadSnapshot(context.Background(), c, filepath.Join(t.TempDir(), "missing"), nil)
+	require.NoError(t, err)
+	assert.Zero(t, loaded)
+}
+
+func TestSnapshot_UnsupportedVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version":99}`+"\n"), 0o644))
+
+	c := NewLRUCache[int, int](10, time.Hour, time.Minute)
+	defer c.Close()
+
+	_, err := LoadSnapshot(context.Background(), c, path, nil)
+	assert.Error(t, err)
+}
+
+func TestSnapshot_ShardedHottest(t *testing.T) {
+	c, err := NewShardedCache[int, int](LFUStrategy, 4, 100, time.Hour, time.Minute)
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 20; i++ {
+		c.Set(ctx, i, i)
+	}
+	for i := 0; i < 5; i++ {
+		c.Get(ctx, 7)
+	}
+
+	hottest := c.Hottest(ctx, 10)
+	require.Len(t, hottest, 10)
+	// Лидеры четырёх сегментов идут первыми, среди них и самый востребованный ключ
+	leaders := make([]int, 0, 4)
+	for _, item := range hottest[:4] {
+		leaders = append(leaders, item.Key)
+	}
+	assert.Contains(t, leaders, 7)
+	assert.Len(t, c.Hottest(ctx, 0), 20)
+}
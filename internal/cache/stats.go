@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+)
+
+// statsCounter накапливает статистику кэша. Счётчики атомарные, поэтому Stats
+// не ждёт блокировку кэша ради чего-то, кроме размера
+type statsCounter struct {
+	hits              atomic.Uint64
+	misses            atomic.Uint64
+	capacityEvictions atomic.Uint64
+	expiredEvictions  atomic.Uint64
+	loads             atomic.Uint64
+	loadTime          atomic.Int64
+}
+
+func (s *statsCounter) hit() {
+	s.hits.Add(1)
+}
+
+func (s *statsCounter) miss() {
+	s.misses.Add(1)
+}
+
+func (s *statsCounter) evictedByCapacity() {
+	s.capacityEvictions.Add(1)
+}
+
+func (s *statsCounter) evictedByTTL() {
+	s.expiredEvictions.Add(1)
+}
+
+func (s *statsCounter) recordLoad(d time.Duration) {
+	s.loads.Add(1)
+	s.loadTime.Add(int64(d))
+}
+
+func (s *statsCounter) snapshot(size int, cost int64) domain.CacheStats {
+	return domain.CacheStats{
+		Hits:              s.hits.Load(),
+		Misses:            s.misses.Load(),
+		CapacityEvictions: s.capacityEvictions.Load(),
+		ExpiredEvictions:  s.expiredEvictions.Load(),
+		Size:              size,
+		Cost:              cost,
+		Loads:             s.loads.Load(),
+		LoadTime:          time.Duration(s.loadTime.Load()),
+	}
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+)
+
+// tieredCache ставит локальный кэш перед удалённым: локальный отвечает без сетевого запроса,
+// а удалённый общий для реплик и переживает их перезапуск
+type tieredCache[K comparable, V any] struct {
+	local  interfaces.Cache[K, V]
+	remote *remoteCache[K, V]
+}
+
+// Set записывает значение в оба уровня
+func (c *tieredCache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Duration) {
+	c.remote.Set(ctx, key, value, ttl...)
+	c.local.Set(ctx, key, value, ttl...)
+}
+
+// Get ищет значение локально, затем в удалённом кэше. Найденное в удалённом кэше
+// кладётся в локальный с его собственным TTL
+func (c *tieredCache[K, V]) Get(ctx context.Context, key K) (V, bool) {
+	if value, found := c.local.Get(ctx, key); found {
+		return value, true
+	}
+
+	value, found := c.remote.Get(ctx, key)
+	if found {
+		c.local.Set(ctx, key, value)
+	}
+	return value, found
+}
+
+// GetOrLoad объединяет загрузки на обоих уровнях: локальный кэш загружает значение из удалённого,
+// а тот при промахе — из источника через load
+func (c *tieredCache[K, V]) GetOrLoad(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error) {
+	return c.local.GetOrLoad(ctx, key, func(ctx context.Context) (V, error) {
+		return c.remote.GetOrLoad(ctx, key, load)
+	})
+}
+
+// Delete удаляет элемент с обоих уровней
+func (c *tieredCache[K, V]) Delete(ctx context.Context, key K) {
+	c.remote.Delete(ctx, key)
+	c.local.Delete(ctx, key)
+}
+
+// Flush очищает оба уровня
+func (c *tieredCache[K, V]) Flush(ctx context.Context) {
+	c.remote.Flush(ctx)
+	c.local.Flush(ctx)
+}
+
+// Inspect показывает локальную копию, а если её нет — удалённую
+func (c *tieredCache[K, V]) Inspect(ctx context.Context, key K) (domain.CacheEntry[V], bool) {
+	if entry, found := c.local.Inspect(ctx, key); found {
+		return entry, true
+	}
+	return c.remote.Inspect(ctx, key)
+}
+
+// GetMany отдаёт локальные копии, а недостающие ключи читает из удалённого кэша одним конвейером
+func (c *tieredCache[K, V]) GetMany(ctx context.Context, keys []K) map[K]V {
+	found := c.local.GetMany(ctx, keys)
+	var missing []K
+	for _, key := range keys {
+		if _, ok := found[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	fetched := c.remote.GetMany(ctx, missing)
+	c.local.SetMany(ctx, fetched)
+	for key, value := range fetched {
+		found[key] = value
+	}
+	return found
+}
+
+// SetMany записывает значения в оба уровня
+func (c *tieredCache[K, V]) SetMany(ctx context.Context, values map[K]V, ttl ...time.Duration) {
+	c.remote.SetMany(ctx, values, ttl...)
+	c.local.SetMany(ctx, values, ttl...)
+}
+
+// DeleteMany удаляет ключи с обоих уровней
+func (c *tieredCache[K, V]) DeleteMany(ctx context.Context, keys []K) {
+	c.remote.DeleteMany(ctx, keys)
+	c.local.DeleteMany(ctx, keys)
+}
+
+// InvalidateLocal удаляет ключи только с локального уровня, не трогая общий для реплик удалённый
+func (c *tieredCache[K, V]) InvalidateLocal(ctx context.Context, keys []K) {
+	c.local.DeleteMany(ctx, keys)
+}
+
+// Hottest возвращает элементы локального уровня
+func (c *tieredCache[K, V]) Hottest(ctx context.Context, limit int) []domain.CacheItem[K, V] {
+	return c.local.Hottest(ctx, limit)
+}
+
+// OnEvict подписывает fn на удаления из локального уровня: об удалениях на сервере реплика не узнаёт
+func (c *tieredCache[K, V]) OnEvict(fn func(key K, value V, reason domain.EvictionReason)) {
+	c.local.OnEvict(fn)
+}
+
+// Stats объединяет уровни: попадания считаются на обоих, промах — только если значения
+// не нашлось нигде, вытеснения, размер и стоимость относятся к локальному кэшу, а загрузки — к источнику
+func (c *tieredCache[K, V]) Stats() domain.CacheStats {
+	local := c.local.Stats()
+	remote := c.remote.Stats()
+
+	return domain.CacheStats{
+		Hits:              local.Hits + remote.Hits,
+		Misses:            remote.Misses,
+		CapacityEvictions: local.CapacityEvictions,
+		ExpiredEvictions:  local.ExpiredEvictions,
+		Size:              local.Size,
+		Cost:              local.Cost,
+		Loads:             remote.Loads,
+		LoadTime:          remote.LoadTime,
+	}
+}
+
+// RecordLoad учитывает загрузку значения из источника
+func (c *tieredCache[K, V]) RecordLoad(d time.Duration) {
+	c.remote.RecordLoad(d)
+}
+
+// Close закрывает оба уровня
+func (c *tieredCache[K, V]) Close() error {
+	return errors.Join(c.local.Close(), c.remote.Close())
+}
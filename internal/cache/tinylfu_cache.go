@@ -0,0 +1,463 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"hash/maphash"
+	"sync"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+)
+
+const (
+	// windowRatio — доля кэша под окно LRU, куда попадают все новые ключи
+	windowRatio = 0.01
+	// protectedRatio — доля основной части под защищённый сегмент SLRU
+	protectedRatio = 0.8
+)
+
+type tinyLFUSegment int
+
+const (
+	segmentWindow tinyLFUSegment = iota
+	segmentProbation
+	segmentProtected
+)
+
+// tinyLFUEntry представляет элемент в W-TinyLFU кэше
+type tinyLFUEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	hash    uint64
+	cost    int64
+	expiry  time.Time
+	segment tinyLFUSegment
+}
+
+// tinyLFUCache реализует W-TinyLFU: новые ключи попадают в небольшое окно LRU, а в основную
+// SLRU часть их пускает фильтр, сравнивающий частоты кандидата и вытесняемого элемента
+type tinyLFUCache[K comparable, V any] struct {
+	mu           sync.Mutex
+	defaultTTL   time.Duration
+	cache        map[K]*list.Element
+	window       *list.List
+	probation    *list.List
+	protected    *list.List
+	maxWindow    int
+	maxMain      int
+	maxProtected int
+	// cost — суммарная стоимость элементов
+	cost      int64
+	sketch    *countMinSketch
+	hash      func(K) uint64
+	janitor   *janitor
+	options   options[V]
+	loader    *loader[K, V]
+	stats     statsCounter
+	evictions evictionQueue[K, V]
+}
+
+// NewTinyLFUCache создает новый W-TinyLFU кэш
+func NewTinyLFUCache[K comparable, V any](maxEntries int, defaultTTL, cleanupInterval time.Duration, opts ...Option[V]) interfaces.Cache[K, V] {
+	c := newTinyLFUSegment[K, V](maxEntries, defaultTTL, opts...)
+	c.janitor = startJanitor(cleanupInterval, c.cleanupExpired)
+	return c
+}
+
+// newTinyLFUSegment создает W-TinyLFU кэш без фоновой очистки, очисткой управляет владелец
+func newTinyLFUSegment[K comparable, V any](maxEntries int, defaultTTL time.Duration, opts ...Option[V]) *tinyLFUCache[K, V] {
+	maxWindow := int(float64(maxEntries) * windowRatio)
+	if maxWindow < 1 {
+		maxWindow = 1
+	}
+	maxMain := maxEntries - maxWindow
+	if maxMain < 1 {
+		maxMain = 1
+	}
+
+	o := newOptions(opts)
+	return &tinyLFUCache[K, V]{
+		defaultTTL:   defaultTTL,
+		cache:        make(map[K]*list.Element),
+		window:       list.New(),
+		probation:    list.New(),
+		protected:    list.New(),
+		maxWindow:    maxWindow,
+		maxMain:      maxMain,
+		maxProtected: int(float64(maxMain) * protectedRatio),
+		sketch:       newCountMinSketch(maxEntries),
+		hash:         newKeyHasher[K](maphash.MakeSeed()),
+		options:      o,
+		loader:       newLoader[K, V](maxEntries, o),
+	}
+}
+
+// Set добавляет или обновляет элемент в кэше
+func (c *tinyLFUCache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Duration) {
+	value = c.options.clone(value)
+	c.loader.forget(ctx, key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.set(key, value, resolveTTL(ttl, c.defaultTTL))
+}
+
+// SetMany добавляет или обновляет несколько элементов под одной блокировкой
+func (c *tinyLFUCache[K, V]) SetMany(ctx context.Context, values map[K]V, ttl ...time.Duration) {
+	itemTTL := resolveTTL(ttl, c.defaultTTL)
+
+	cloned := make(map[K]V, len(values))
+	for key, value := range values {
+		cloned[key] = c.options.clone(value)
+		c.loader.forget(ctx, key)
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	for key, value := range cloned {
+		c.set(key, value, itemTTL)
+	}
+}
+
+// Get возвращает значение элемента по ключу. Промахи тоже учитываются в частотах,
+// чтобы ключ, который часто ищут, быстрее прошёл фильтр при следующем Set
+func (c *tinyLFUCache[K, V]) Get(ctx context.Context, key K) (V, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	value, found := c.get(key)
+	if !found {
+		return value, false
+	}
+	return c.options.clone(value), true
+}
+
+// GetMany возвращает найденные элементы под одной блокировкой
+func (c *tinyLFUCache[K, V]) GetMany(ctx context.Context, keys []K) map[K]V {
+	found := make(map[K]V, len(keys))
+
+	c.mu.Lock()
+	for _, key := range keys {
+		if value, ok := c.get(key); ok {
+			found[key] = value
+		}
+	}
+	c.unlock()
+
+	for key, value := range found {
+		found[key] = c.options.clone(value)
+	}
+	return found
+}
+
+// GetOrLoad возвращает значение из кэша, а при промахе загружает его через load
+func (c *tinyLFUCache[K, V]) GetOrLoad(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error) {
+	return c.loader.getOrLoad(ctx, c, key, load)
+}
+
+// Delete удаляет элемент из кэша по ключу
+func (c *tinyLFUCache[K, V]) Delete(ctx context.Context, key K) {
+	c.loader.forget(ctx, key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.delete(key)
+}
+
+// DeleteMany удаляет несколько элементов под одной блокировкой
+func (c *tinyLFUCache[K, V]) DeleteMany(ctx context.Context, keys []K) {
+	for _, key := range keys {
+		c.loader.forget(ctx, key)
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	for _, key := range keys {
+		c.delete(key)
+	}
+}
+
+// Flush очищает весь кэш вместе с накопленными частотами
+func (c *tinyLFUCache[K, V]) Flush(ctx context.Context) {
+	c.loader.forgetAll(ctx)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	for _, elem := range c.cache {
+		e := elem.Value.(*tinyLFUEntry[K, V])
+		c.evictions.add(e.key, e.value, domain.EvictionDeleted)
+	}
+	c.cache = make(map[K]*list.Element)
+	c.window.Init()
+	c.probation.Init()
+	c.protected.Init()
+	c.sketch.Clear()
+	c.cost = 0
+}
+
+// Inspect возвращает элемент без учёта обращения в частотах и без переноса между сегментами
+func (c *tinyLFUCache[K, V]) Inspect(ctx context.Context, key K) (domain.CacheEntry[V], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, exists := c.cache[key]
+	if !exists {
+		return domain.CacheEntry[V]{}, false
+	}
+	e := elem.Value.(*tinyLFUEntry[K, V])
+	return domain.CacheEntry[V]{Value: c.options.clone(e.value), ExpiresAt: e.expiry}, true
+}
+
+// Hottest возвращает живые элементы защищённого сегмента, затем окна и испытательного сегмента,
+// внутри сегмента — от недавно использованных к давно использованным
+func (c *tinyLFUCache[K, V]) Hottest(ctx context.Context, limit int) []domain.CacheItem[K, V] {
+	var items []domain.CacheItem[K, V]
+
+	c.mu.Lock()
+	now := time.Now()
+	for _, l := range []*list.List{c.protected, c.window, c.probation} {
+		for elem := l.Front(); elem != nil && (limit <= 0 || len(items) < limit); elem = elem.Next() {
+			e := elem.Value.(*tinyLFUEntry[K, V])
+			if now.Before(e.expiry) {
+				items = append(items, domain.CacheItem[K, V]{Key: e.key, Value: e.value, ExpiresAt: e.expiry})
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	return cloneItems(items, c.options)
+}
+
+// OnEvict подписывает fn на удаление элементов из кэша, в том числе на кандидатов, которых
+// не пустил фильтр. fn вызывается после снятия блокировки
+func (c *tinyLFUCache[K, V]) OnEvict(fn func(key K, value V, reason domain.EvictionReason)) {
+	c.evictions.subscribe(fn)
+}
+
+// Stats возвращает статистику кэша
+func (c *tinyLFUCache[K, V]) Stats() domain.CacheStats {
+	c.mu.Lock()
+	size, cost := len(c.cache), c.cost
+	c.mu.Unlock()
+
+	return c.stats.snapshot(size, cost)
+}
+
+// RecordLoad учитывает загрузку значения из источника
+func (c *tinyLFUCache[K, V]) RecordLoad(d time.Duration) {
+	c.stats.recordLoad(d)
+}
+
+// set добавляет или обновляет элемент, вызывается под блокировкой
+func (c *tinyLFUCache[K, V]) set(key K, value V, ttl time.Duration) {
+	cost := c.options.costOf(value)
+	if c.options.overBudget(cost) {
+		c.reject(key, value)
+		return
+	}
+	expiry := time.Now().Add(c.options.jitter(ttl))
+
+	if elem, exists := c.cache[key]; exists {
+		e := elem.Value.(*tinyLFUEntry[K, V])
+		c.cost += cost - e.cost
+		e.value = value
+		e.cost = cost
+		e.expiry = expiry
+		c.onHit(elem)
+	} else {
+		hash := c.hash(key)
+		c.sketch.Increment(hash)
+
+		elem := c.window.PushFront(&tinyLFUEntry[K, V]{
+			key:     key,
+			value:   value,
+			hash:    hash,
+			cost:    cost,
+			expiry:  expiry,
+			segment: segmentWindow,
+		})
+		c.cache[key] = elem
+		c.cost += cost
+
+		if c.window.Len() > c.maxWindow {
+			c.admit(c.window.Back())
+		}
+	}
+
+	// Бюджет стоимости проверяется после фильтра: сначала вытесняются элементы испытательного
+	// сегмента, затем защищённого и только потом окна
+	for c.options.overBudget(c.cost) {
+		c.removeElement(c.costVictim(), domain.EvictionCapacity)
+	}
+}
+
+// costVictim возвращает элемент, который вытесняется при превышении бюджета стоимости
+func (c *tinyLFUCache[K, V]) costVictim() *list.Element {
+	if elem := c.probation.Back(); elem != nil {
+		return elem
+	}
+	if elem := c.protected.Back(); elem != nil {
+		return elem
+	}
+	return c.window.Back()
+}
+
+// reject отказывает в записи элементу, который дороже всего бюджета. Прежнее значение ключа
+// удаляется, чтобы Get не вернул устаревшие данные
+func (c *tinyLFUCache[K, V]) reject(key K, value V) {
+	if elem, exists := c.cache[key]; exists {
+		c.removeElement(elem, domain.EvictionCapacity)
+		return
+	}
+	c.stats.evictedByCapacity()
+	c.evictions.add(key, value, domain.EvictionCapacity)
+}
+
+// get возвращает значение без копирования, вызывается под блокировкой
+func (c *tinyLFUCache[K, V]) get(key K) (V, bool) {
+	var zero V
+
+	elem, exists := c.cache[key]
+	if !exists {
+		c.sketch.Increment(c.hash(key))
+		c.stats.miss()
+		return zero, false
+	}
+
+	e := elem.Value.(*tinyLFUEntry[K, V])
+	c.sketch.Increment(e.hash)
+	if time.Now().After(e.expiry) {
+		c.removeElement(elem, domain.EvictionExpired)
+		c.stats.miss()
+		return zero, false
+	}
+	c.onHit(elem)
+	c.stats.hit()
+	return e.value, true
+}
+
+// delete удаляет элемент, вызывается под блокировкой
+func (c *tinyLFUCache[K, V]) delete(key K) {
+	if elem, exists := c.cache[key]; exists {
+		c.removeElement(elem, domain.EvictionDeleted)
+	}
+}
+
+// unlock снимает блокировку и передаёт подписчикам элементы, удалённые под ней
+func (c *tinyLFUCache[K, V]) unlock() {
+	evicted := c.evictions.take()
+	c.mu.Unlock()
+	c.evictions.notify(evicted)
+}
+
+// onHit обновляет позицию элемента: из испытательного сегмента он переходит в защищённый
+func (c *tinyLFUCache[K, V]) onHit(elem *list.Element) {
+	e := elem.Value.(*tinyLFUEntry[K, V])
+	switch e.segment {
+	case segmentWindow:
+		c.window.MoveToFront(elem)
+	case segmentProtected:
+		c.protected.MoveToFront(elem)
+	case segmentProbation:
+		c.probation.Remove(elem)
+		e.segment = segmentProtected
+		c.cache[e.key] = c.protected.PushFront(e)
+
+		if c.protected.Len() > c.maxProtected {
+			demoted := c.protected.Remove(c.protected.Back()).(*tinyLFUEntry[K, V])
+			demoted.segment = segmentProbation
+			c.cache[demoted.key] = c.probation.PushFront(demoted)
+		}
+	}
+}
+
+// admit переносит кандидата из окна в основную часть. Если она заполнена, в кэше остаётся
+// тот из кандидата и жертвы из хвоста испытательного сегмента, к кому обращались чаще
+func (c *tinyLFUCache[K, V]) admit(elem *list.Element) {
+	candidate := c.window.Remove(elem).(*tinyLFUEntry[K, V])
+	candidate.segment = segmentProbation
+
+	if c.probation.Len()+c.protected.Len() >= c.maxMain {
+		victimElem := c.probation.Back()
+		if victimElem == nil {
+			victimElem = c.protected.Back()
+		}
+		victim := victimElem.Value.(*tinyLFUEntry[K, V])
+
+		if c.sketch.Estimate(candidate.hash) <= c.sketch.Estimate(victim.hash) {
+			delete(c.cache, candidate.key)
+			c.cost -= candidate.cost
+			c.stats.evictedByCapacity()
+			c.evictions.add(candidate.key, candidate.value, domain.EvictionCapacity)
+			return
+		}
+		c.removeElement(victimElem, domain.EvictionCapacity)
+	}
+
+	c.cache[candidate.key] = c.probation.PushFront(candidate)
+}
+
+func (c *tinyLFUCache[K, V]) listOf(segment tinyLFUSegment) *list.List {
+	switch segment {
+	case segmentWindow:
+		return c.window
+	case segmentProbation:
+		return c.probation
+	default:
+		return c.protected
+	}
+}
+
+// removeElement удаляет элемент из его сегмента и мапы и учитывает причину удаления
+func (c *tinyLFUCache[K, V]) removeElement(elem *list.Element, reason domain.EvictionReason) {
+	e := elem.Value.(*tinyLFUEntry[K, V])
+	c.listOf(e.segment).Remove(elem)
+	delete(c.cache, e.key)
+	c.cost -= e.cost
+
+	switch reason {
+	case domain.EvictionCapacity:
+		c.stats.evictedByCapacity()
+	case domain.EvictionExpired:
+		c.stats.evictedByTTL()
+	}
+	c.evictions.add(e.key, e.value, reason)
+}
+
+func (c *tinyLFUCache[K, V]) cleanupExpired() {
+	sweepIncrementally(c.sweepExpired)
+}
+
+// sweepExpired просматривает до limit элементов и удаляет просроченные
+func (c *tinyLFUCache[K, V]) sweepExpired(limit int) (scanned, removed int) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := time.Now()
+	for _, elem := range c.cache {
+		if scanned == limit {
+			break
+		}
+		scanned++
+		if now.After(elem.Value.(*tinyLFUEntry[K, V]).expiry) {
+			c.removeElement(elem, domain.EvictionExpired)
+			removed++
+		}
+	}
+	return scanned, removed
+}
+
+// Close останавливает процесс очистки и освобождает ресурсы
+func (c *tinyLFUCache[K, V]) Close() error {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+	return nil
+}
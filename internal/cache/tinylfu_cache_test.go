@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTinyLFUCache[K comparable, V any](maxEntries int, defaultTTL, cleanupInterval time.Duration) *tinyLFUCache[K, V] {
+	return NewTinyLFUCache[K, V](maxEntries, defaultTTL, cleanupInterval).(*tinyLFUCache[K, V])
+}
+
+func TestTinyLFUCache_SetGet(t *testing.T) {
+	cache := newTestTinyLFUCache[string, string](100, 5*time.Second, 1*time.Second)
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	cache.Set(ctx, "key1", "value1")
+	val, found := cache.Get(ctx, "key1")
+	require.True(t, found)
+	assert.Equal(t, "value1", val)
+
+	cache.Set(ctx, "key1", "value2")
+	val, found = cache.Get(ctx, "key1")
+	require.True(t, found)
+	assert.Equal(t, "value2", val)
+
+	cache.Delete(ctx, "key1")
+	_, found = cache.Get(ctx, "key1")
+	assert.False(t, found, "key1 should have been deleted")
+}
+
+func TestTinyLFUCache_MaxEntries(t *testing.T) {
+	cache := newTestTinyLFUCache[int, int](100, 5*time.Second, 1*time.Second)
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	for i := 0; i < 1000; i++ {
+		cache.Set(ctx, i, i)
+	}
+
+	assert.LessOrEqual(t, len(cache.cache), 100)
+	assert.Equal(t, len(cache.cache), cache.window.Len()+cache.probation.Len()+cache.protected.Len())
+}
+
+func TestTinyLFUCache_ScanResistance(t *testing.T) {
+	// Целые ключи хэшируются без случайного seed, поэтому коллизии в sketch и результат теста воспроизводимы
+	cache := newTestTinyLFUCache[int, int](100, 5*time.Second, 1*time.Second)
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	for i := 0; i < 50; i++ {
+		cache.Set(ctx, i, i)
+		for j := 0; j < 5; j++ {
+			cache.Get(ctx, i)
+		}
+	}
+
+	// Однократный проход по большому числу ключей не должен вытеснить часто читаемые
+	for i := 1000; i < 2000; i++ {
+		cache.Set(ctx, i, i)
+	}
+
+	for i := 0; i < 50; i++ {
+		_, found := cache.Get(ctx, i)
+		assert.True(t, found, "hot key %d should survive a scan", i)
+	}
+}
+
+func TestTinyLFUCache_TTLExpiration(t *testing.T) {
+	cache := newTestTinyLFUCache[string, string](10, 1*time.Second, 500*time.Millisecond)
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	cache.Set(ctx, "key1", "value1")
+	time.Sleep(2 * time.Second)
+
+	_, found := cache.Get(ctx, "key1")
+	assert.False(t, found, "key1 should have expired")
+}
+
+func TestTinyLFUCache_Flush(t *testing.T) {
+	cache := newTestTinyLFUCache[string, string](10, 5*time.Second, 1*time.Second)
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	cache.Set(ctx, "key1", "value1")
+	cache.Set(ctx, "key2", "value2")
+	cache.Flush(ctx)
+
+	_, found := cache.Get(ctx, "key1")
+	assert.False(t, found, "key1 should have been flushed")
+	_, found = cache.Get(ctx, "key2")
+	assert.False(t, found, "key2 should have been flushed")
+}
+
+func TestCountMinSketch_Decay(t *testing.T) {
+	sketch := newCountMinSketch(16)
+
+	for i := 0; i < 10; i++ {
+		sketch.Increment(42)
+	}
+	require.Equal(t, uint8(10), sketch.Estimate(42))
+
+	// Обращение, на котором набирается sampleSize, запускает старение счётчиков
+	sketch.additions = sketch.sampleSize - 1
+	sketch.Increment(42)
+	assert.Equal(t, uint8(5), sketch.Estimate(42))
+	assert.Less(t, sketch.additions, sketch.sampleSize)
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"time"
 
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/dto"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/events"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/kafka"
@@ -51,8 +52,28 @@ func (c *OrderController) AddOrder(ctx context.Context, req *dto.AddOrderDTO) er
 	return nil
 }
 
-func (c *OrderController) RemoveOrder(ctx context.Context, orderID string) error {
-	err := c.orderUseCase.RemoveOrder(ctx, orderID)
+func (c *OrderController) AddOrders(ctx context.Context, reqs []*dto.AddOrderDTO) error {
+	err := c.orderUseCase.AddOrders(ctx, reqs)
+	if err != nil {
+		log.Printf("Failed to add orders: %v", err)
+		return err
+	}
+
+	for _, req := range reqs {
+		event := events.OrderEvent{
+			OrderID:   req.OrderID,
+			Event:     "AddOrder",
+			Timestamp: time.Now().Format(time.RFC3339),
+			Details:   req,
+		}
+		c.sendEvent(req.OrderID, event)
+	}
+
+	return nil
+}
+
+func (c *OrderController) RemoveOrder(ctx context.Context, orderID string, expectedVersion int64, reason string) error {
+	err := c.orderUseCase.RemoveOrder(ctx, orderID, expectedVersion, reason)
 	if err != nil {
 		log.Printf("Failed to remove order: %v", err)
 		return err
@@ -88,11 +109,11 @@ func (c *OrderController) DeliverOrders(ctx context.Context, recipientID string,
 	return nil
 }
 
-func (c *OrderController) AcceptReturn(ctx context.Context, recipientID, orderID string) error {
-	err := c.orderUseCase.AcceptReturn(ctx, recipientID, orderID)
+func (c *OrderController) AcceptReturn(ctx context.Context, recipientID, orderID string, expectedVersion int64) (int64, error) {
+	version, err := c.orderUseCase.AcceptReturn(ctx, recipientID, orderID, expectedVersion)
 	if err != nil {
 		log.Printf("Failed to accept return: %v", err)
-		return err
+		return 0, err
 	}
 
 	event := events.OrderEvent{
@@ -103,25 +124,34 @@ func (c *OrderController) AcceptReturn(ctx context.Context, recipientID, orderID
 	}
 	c.sendEvent(orderID, event)
 
-	return nil
+	return version, nil
 }
 
-func (c *OrderController) GetOrders(ctx context.Context, recipientID string, lastN int) ([]*dto.OrderDTO, error) {
-	orders, err := c.orderUseCase.GetOrders(ctx, recipientID, lastN)
+func (c *OrderController) GetOrders(ctx context.Context, recipientID string, pageSize int, pageToken string) ([]*dto.OrderDTO, string, error) {
+	orders, nextPageToken, err := c.orderUseCase.GetOrders(ctx, recipientID, pageSize, pageToken)
 	if err != nil {
 		log.Printf("Failed to get orders: %v", err)
-		return nil, err
+		return nil, "", err
+	}
+	return orders, nextPageToken, nil
+}
+
+func (c *OrderController) ListOrders(ctx context.Context, filter domain.OrderFilter, pageSize int, pageToken string) ([]*dto.OrderDTO, string, error) {
+	orders, nextPageToken, err := c.orderUseCase.ListOrders(ctx, filter, pageSize, pageToken)
+	if err != nil {
+		log.Printf("Failed to list orders: %v", err)
+		return nil, "", err
 	}
-	return orders, nil
+	return orders, nextPageToken, nil
 }
 
-func (c *OrderController) GetReturns(ctx context.Context, page int) ([]*dto.ReturnDTO, error) {
-	returns, err := c.orderUseCase.GetReturns(ctx, page)
+func (c *OrderController) GetReturns(ctx context.Context, pageSize int, pageToken string) ([]*dto.ReturnDTO, string, error) {
+	returns, nextPageToken, err := c.orderUseCase.GetReturns(ctx, pageSize, pageToken)
 	if err != nil {
 		log.Printf("Failed to get returns: %v", err)
-		return nil, err
+		return nil, "", err
 	}
-	return returns, nil
+	return returns, nextPageToken, nil
 }
 
 func (c *OrderController) sendEvent(key string, event events.OrderEvent) {
@@ -6,13 +6,14 @@ import (
 	"testing"
 	"time"
 
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/dto"
-	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/postgres"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/memory"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/usecase"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type MockProducer struct {
@@ -29,21 +30,23 @@ func (m *MockProducer) Close() error {
 	return args.Error(0)
 }
 
-func TestOrderController_AddOrder(t *testing.T) {
-	db := sqlx.NewDb(nil, "postgres")
-	orderRepo := postgres.NewOrderRepository(db)
-	returnRepo := postgres.NewReturnRepository(db)
-	txManager := postgres.NewTxManager(db)
-	orderUseCase := usecase.NewOrderUseCase(orderRepo, returnRepo, txManager)
+type stubMetrics struct{}
+
+func (stubMetrics) IncOrdersServed()                         {}
+func (stubMetrics) IncInvalidationsPublished(err error)      {}
+func (stubMetrics) IncInvalidationsApplied(keys int)         {}
+func (stubMetrics) ObserveInvalidationLag(lag time.Duration) {}
+func (stubMetrics) IncTxRetries(sqlState string)             {}
+func (stubMetrics) IncTxRollbacks(reason string)             {}
+
+func newTestOrderUseCase(store *memory.Store) *usecase.OrderUseCase {
+	return usecase.NewOrderUseCase(memory.NewOrderRepository(store), memory.NewReturnRepository(store), memory.NewTxManager(store), stubMetrics{})
+}
 
+func TestOrderController_AddOrder(t *testing.T) {
 	mockProducer := new(MockProducer)
 	topic := "test-topic"
-
-	controller := &OrderController{
-		orderUseCase: orderUseCase,
-		producer:     mockProducer,
-		topic:        topic,
-	}
+	controller := NewOrderController(newTestOrderUseCase(memory.NewStore()), mockProducer, topic)
 
 	ctx := context.Background()
 	orderID := "order123"
@@ -73,25 +76,26 @@ func TestOrderController_AddOrder(t *testing.T) {
 }
 
 func TestOrderController_DeliverOrders(t *testing.T) {
-	db := sqlx.NewDb(nil, "postgres")
-	orderRepo := postgres.NewOrderRepository(db)
-	returnRepo := postgres.NewReturnRepository(db)
-	txManager := postgres.NewTxManager(db)
-	orderUseCase := usecase.NewOrderUseCase(orderRepo, returnRepo, txManager)
-
+	store := memory.NewStore()
 	mockProducer := new(MockProducer)
 	topic := "test-topic"
-
-	controller := &OrderController{
-		orderUseCase: orderUseCase,
-		producer:     mockProducer,
-		topic:        topic,
-	}
+	controller := NewOrderController(newTestOrderUseCase(store), mockProducer, topic)
 
 	ctx := context.Background()
 	recipientID := "recipient123"
 	orderIDs := []string{"order1", "order2"}
 
+	orderRepo := memory.NewOrderRepository(store)
+	for _, orderID := range orderIDs {
+		require.NoError(t, orderRepo.AddOrder(ctx, &domain.Order{
+			OrderID:     orderID,
+			RecipientID: recipientID,
+			ExpiryDate:  time.Now().Add(24 * time.Hour),
+			Status:      domain.OrderStatusNew,
+			AcceptedAt:  time.Now(),
+		}))
+	}
+
 	for _, orderID := range orderIDs {
 		mockProducer.On("SendMessage", topic, orderID, mock.Anything).Return(nil)
 	}
@@ -26,7 +26,7 @@ type RemoveOrderTask struct {
 }
 
 func (t *RemoveOrderTask) Execute() error {
-	return t.orderUseCase.RemoveOrder(context.Background(), t.orderID)
+	return t.orderUseCase.RemoveOrder(context.Background(), t.orderID, 0, "")
 }
 
 type DeliverOrdersTask struct {
@@ -46,5 +46,6 @@ type AcceptReturnTask struct {
 }
 
 func (t *AcceptReturnTask) Execute() error {
-	return t.orderUseCase.AcceptReturn(context.Background(), t.recipientID, t.orderID)
+	_, err := t.orderUseCase.AcceptReturn(context.Background(), t.recipientID, t.orderID, 0)
+	return err
 }
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/metrics"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/migrator"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/postgres"
+	"gitlab.ozon.dev/ashadkhamov/homework/migrations"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
+)
+
+// ErrDSNNotSet возвращается, если в конфиге не задан database.dsn
+var ErrDSNNotSet = errors.New("database connection string is not set in the config")
+
+// ConnectPrimary открывает пул подключений к основной базе из database.dsn и проверяет, что она доступна
+func ConnectPrimary(ctx context.Context) (*postgres.Pool, error) {
+	dsn := viper.GetString("database.dsn")
+	if dsn == "" {
+		return nil, ErrDSNNotSet
+	}
+
+	pool, err := OpenPool(ctx, "primary", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.Ping(ctx); err != nil {
+		_ = pool.Close()
+		return nil, fmt.Errorf("failed to connect to DB: %w", err)
+	}
+	return pool, nil
+}
+
+// OpenPool открывает пул pgx с настройками из database.pool и отдаёт его статистику в Prometheus с меткой pool=name
+func OpenPool(ctx context.Context, name, dsn string) (*postgres.Pool, error) {
+	pool, err := postgres.OpenPool(ctx, dsn, postgres.PoolConfig{
+		MaxConns:               viper.GetInt32("database.pool.max_conns"),
+		MinConns:               viper.GetInt32("database.pool.min_conns"),
+		MaxConnLifetime:        viper.GetDuration("database.pool.max_conn_lifetime"),
+		MaxConnIdleTime:        viper.GetDuration("database.pool.max_conn_idle_time"),
+		HealthCheckPeriod:      viper.GetDuration("database.pool.health_check_period"),
+		StatementCacheCapacity: viper.GetInt("database.pool.statement_cache_capacity"),
+		QueryTimeout:           viper.GetDuration("database.pool.query_timeout"),
+		DeadlineReserve:        viper.GetDuration("database.pool.deadline_reserve"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s database pool: %w", name, err)
+	}
+	prometheus.MustRegister(metrics.NewDBPoolCollector(name, pool.Stats))
+	return pool, nil
+}
+
+// NewMigrator создает мигратор схемы из встроенных в бинарник миграций
+func NewMigrator(db *sqlx.DB) (*migrator.Migrator, error) {
+	m, err := migrator.New(db.DB, migrations.FS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	return m, nil
+}
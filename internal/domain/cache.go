@@ -0,0 +1,88 @@
+package domain
+
+import "time"
+
+// CacheStats — накопленные счётчики кэша с момента его создания
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	// CapacityEvictions — элементы, вытесненные из-за лимита размера
+	CapacityEvictions uint64
+	// ExpiredEvictions — элементы, удалённые по истечении TTL
+	ExpiredEvictions uint64
+	Size             int
+	// Cost — суммарная стоимость элементов, если кэш ограничен по стоимости
+	Cost int64
+	// Loads и LoadTime — число загрузок из источника после промаха и их суммарная длительность
+	Loads    uint64
+	LoadTime time.Duration
+}
+
+// HitRate возвращает долю попаданий среди всех обращений
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// AverageLoadTime возвращает среднюю длительность загрузки из источника
+func (s CacheStats) AverageLoadTime() time.Duration {
+	if s.Loads == 0 {
+		return 0
+	}
+	return s.LoadTime / time.Duration(s.Loads)
+}
+
+// Add суммирует счётчики, например, по сегментам шардированного кэша
+func (s CacheStats) Add(other CacheStats) CacheStats {
+	return CacheStats{
+		Hits:              s.Hits + other.Hits,
+		Misses:            s.Misses + other.Misses,
+		CapacityEvictions: s.CapacityEvictions + other.CapacityEvictions,
+		ExpiredEvictions:  s.ExpiredEvictions + other.ExpiredEvictions,
+		Size:              s.Size + other.Size,
+		Cost:              s.Cost + other.Cost,
+		Loads:             s.Loads + other.Loads,
+		LoadTime:          s.LoadTime + other.LoadTime,
+	}
+}
+
+// CacheEntry — элемент кэша, который возвращается для диагностики
+type CacheEntry[V any] struct {
+	Value     V
+	ExpiresAt time.Time
+}
+
+// EvictionReason — причина, по которой элемент покинул кэш
+type EvictionReason int
+
+const (
+	// EvictionCapacity — вытеснен из-за лимита размера
+	EvictionCapacity EvictionReason = iota
+	// EvictionExpired — истёк TTL
+	EvictionExpired
+	// EvictionDeleted — удалён явно через Delete, DeleteMany или Flush
+	EvictionDeleted
+)
+
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionCapacity:
+		return "capacity"
+	case EvictionExpired:
+		return "expired"
+	case EvictionDeleted:
+		return "deleted"
+	default:
+		return "unknown"
+	}
+}
+
+// CacheItem — элемент кэша вместе с ключом, например, для снимка кэша
+type CacheItem[K comparable, V any] struct {
+	Key       K
+	Value     V
+	ExpiresAt time.Time
+}
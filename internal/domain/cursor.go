@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+type OrderCursor struct {
+	AcceptedAt time.Time `json:"accepted_at"`
+	OrderID    string    `json:"order_id"`
+}
+
+// Precedes сообщает, идёт ли курсор перед заказом в порядке убывания (accepted_at, order_id)
+func (c *OrderCursor) Precedes(order *Order) bool {
+	if cmp := order.AcceptedAt.Compare(c.AcceptedAt); cmp != 0 {
+		return cmp < 0
+	}
+	return order.OrderID < c.OrderID
+}
+
+type ReturnCursor struct {
+	ReturnDate time.Time `json:"return_date"`
+	ID         int       `json:"id"`
+}
+
+// Precedes сообщает, идёт ли курсор перед возвратом в порядке убывания (return_date, id)
+func (c *ReturnCursor) Precedes(ret *Return) bool {
+	if cmp := ret.ReturnDate.Compare(c.ReturnDate); cmp != 0 {
+		return cmp < 0
+	}
+	return ret.ID < c.ID
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// DBPoolStats — состояние пула подключений к базе в момент снятия
+type DBPoolStats struct {
+	// AcquiredConns — подключения, занятые запросами, IdleConns — свободные, TotalConns — все открытые
+	AcquiredConns int32
+	IdleConns     int32
+	TotalConns    int32
+	MaxConns      int32
+	// AcquireCount и AcquireDuration — число выдач подключений из пула и суммарное время их ожидания
+	AcquireCount    int64
+	AcquireDuration time.Duration
+	// EmptyAcquireCount — выдачи, которым пришлось ждать, потому что свободных подключений не было
+	EmptyAcquireCount int64
+	// CanceledAcquireCount — ожидания подключения, прерванные отменой контекста
+	CanceledAcquireCount int64
+	NewConnsCount        int64
+	// MaxLifetimeDestroyCount и MaxIdleDestroyCount — подключения, закрытые по времени жизни и простоя
+	MaxLifetimeDestroyCount int64
+	MaxIdleDestroyCount     int64
+}
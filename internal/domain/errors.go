@@ -7,6 +7,7 @@ import (
 
 var (
 	ErrOrderNotFound         = errors.New("order not found")
+	ErrOrderAlreadyExists    = errors.New("order already exists")
 	ErrInvalidInput          = errors.New("invalid input")
 	ErrOrderAlreadyDelivered = errors.New("order already delivered")
 	ErrOrderCannotBeRemoved  = errors.New("order cannot be removed")
@@ -14,5 +15,7 @@ var (
 	ErrOrderExpired          = errors.New("order has expired")
 	ErrReturnPeriodExpired   = errors.New("return period has expired")
 	ErrOrderNotDelivered     = errors.New("order was not delivered")
+	ErrOrderVersionConflict  = errors.New("order was modified concurrently")
+	ErrWeakETag              = errors.New("weak etag cannot be used for a conditional update")
 	ErrCacheMiss             = fmt.Errorf("cache miss")
 )
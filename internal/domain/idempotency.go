@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DefaultIdempotencyLease — срок резервирования ключа, если он не задан в настройках
+const DefaultIdempotencyLease = time.Minute
+
+type IdempotencyRecord struct {
+	Key           string        `db:"idempotency_key"`
+	Method        string        `db:"method"`
+	Fingerprint   string        `db:"fingerprint"`
+	StatusCode    sql.NullInt32 `db:"status_code"`
+	StatusMessage string        `db:"status_message"`
+	Response      []byte        `db:"response"`
+	// ResponseHeaders — заголовки ответа, например etag, которые повтор отдаёт вместе с сохранённым результатом
+	ResponseHeaders []byte    `db:"response_headers"`
+	CreatedAt       time.Time `db:"created_at"`
+	// Token отличает резервирование от следующих резервирований того же ключа: результат записывает
+	// и освобождает ключ только тот запрос, который его зарезервировал
+	Token string `db:"reservation_token"`
+	// ReservedUntil — срок резервирования. Незавершённое резервирование после этого срока считается
+	// брошенным, например после падения процесса, и ключ можно зарезервировать заново
+	ReservedUntil time.Time `db:"reserved_until"`
+}
+
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode.Valid
+}
+
+// Abandoned сообщает, что запрос, зарезервировавший ключ, так и не записал результат до конца срока
+func (r *IdempotencyRecord) Abandoned(now time.Time) bool {
+	return !r.Completed() && now.After(r.ReservedUntil)
+}
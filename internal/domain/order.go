@@ -2,7 +2,15 @@ package domain
 
 import (
 	"database/sql"
+	"slices"
 	"time"
+	"unsafe"
+)
+
+const (
+	OrderStatusNew       = "new"
+	OrderStatusDelivered = "delivered"
+	OrderStatusReturned  = "returned"
 )
 
 type Order struct {
@@ -15,4 +23,48 @@ type Order struct {
 	Weight        float32      `db:"weight"`
 	Cost          float32      `db:"cost"`
 	PackagingType string       `db:"packaging_type"`
+	AcceptedAt    time.Time    `db:"accepted_at"`
+	// Version увеличивается при каждом изменении заказа и защищает от одновременных обновлений
+	Version int64 `db:"version"`
+	// UpdatedAt — время последнего изменения заказа, его выставляет репозиторий
+	UpdatedAt time.Time `db:"updated_at"`
+	// DeletedAt и RemovalReason заполняются, когда заказ убирают с ПВЗ. Удалённые заказы не видны
+	// в обычных выборках и со временем переносятся в архив
+	DeletedAt     sql.NullTime `db:"deleted_at"`
+	RemovalReason string       `db:"removal_reason"`
+}
+
+// ArchivedOrder — закрытый заказ, перенесённый в архив
+type ArchivedOrder struct {
+	Order
+	ArchivedAt time.Time `db:"archived_at"`
+}
+
+// ReturnWindow — срок после выдачи, в течение которого принимаются возвраты. Выданный заказ должен
+// оставаться среди действующих хотя бы столько, поэтому срок хранения закрытых заказов больше него
+const ReturnWindow = 14 * 24 * time.Hour
+
+// ClosedStatuses — статусы заказов, которые покинули ПВЗ. Вместе с удалёнными такие заказы
+// переносятся в архив, когда с последнего изменения пройдёт срок хранения
+var ClosedStatuses = []string{OrderStatusDelivered}
+
+// IsClosed сообщает, что заказ удалён или выдан и его можно перенести в архив
+func (o *Order) IsClosed() bool {
+	return o.DeletedAt.Valid || slices.Contains(ClosedStatuses, o.Status)
+}
+
+// Size возвращает примерный размер заказа в памяти в байтах: саму структуру и содержимое строк
+func (o *Order) Size() int64 {
+	if o == nil {
+		return 0
+	}
+	return int64(unsafe.Sizeof(*o)) + int64(len(o.OrderID)+len(o.RecipientID)+len(o.Status)+len(o.PackagingType)+len(o.RemovalReason))
+}
+
+func (o *Order) Clone() *Order {
+	if o == nil {
+		return nil
+	}
+	clone := *o
+	return &clone
 }
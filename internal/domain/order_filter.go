@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+type OrderFilter struct {
+	RecipientID    string
+	Statuses       []string
+	PackagingTypes []string
+	ExpiryFrom     time.Time
+	ExpiryTo       time.Time
+	InStorage      bool
+	OrderIDPrefix  string
+}
+
+// InStorageStatuses — статусы заказов, которые физически находятся на ПВЗ
+var InStorageStatuses = []string{OrderStatusNew, OrderStatusReturned}
+
+// Matches сообщает, подходит ли заказ под фильтр. Нужен хранилищам, которые фильтруют без SQL
+func (f OrderFilter) Matches(order *Order) bool {
+	switch {
+	case f.RecipientID != "" && order.RecipientID != f.RecipientID:
+		return false
+	case len(f.Statuses) > 0 && !slices.Contains(f.Statuses, order.Status):
+		return false
+	case f.InStorage && !slices.Contains(InStorageStatuses, order.Status):
+		return false
+	case len(f.PackagingTypes) > 0 && !slices.Contains(f.PackagingTypes, order.PackagingType):
+		return false
+	case !f.ExpiryFrom.IsZero() && order.ExpiryDate.Before(f.ExpiryFrom):
+		return false
+	case !f.ExpiryTo.IsZero() && order.ExpiryDate.After(f.ExpiryTo):
+		return false
+	case f.OrderIDPrefix != "" && !strings.HasPrefix(order.OrderID, f.OrderIDPrefix):
+		return false
+	default:
+		return true
+	}
+}
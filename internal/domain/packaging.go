@@ -2,6 +2,12 @@ package domain
 
 import "fmt"
 
+const (
+	PackagingBag  = "bag"
+	PackagingBox  = "box"
+	PackagingFilm = "film"
+)
+
 type PackagingStrategy interface {
 	Apply(order *Order) error
 	GetCostIncrease() float32
@@ -45,11 +51,11 @@ func (p *FilmPackaging) GetCostIncrease() float32 {
 
 func GetPackagingStrategy(packagingType string) (PackagingStrategy, error) {
 	switch packagingType {
-	case "bag":
+	case PackagingBag:
 		return &BagPackaging{}, nil
-	case "box":
+	case PackagingBox:
 		return &BoxPackaging{}, nil
-	case "film":
+	case PackagingFilm:
 		return &FilmPackaging{}, nil
 	default:
 		return nil, fmt.Errorf("invalid packaging type")
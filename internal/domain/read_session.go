@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ReadSession запоминает, до какого момента запрос должен читать с основной базы, чтобы после записи
+// не получить с отстающей реплики данные без неё
+type ReadSession struct {
+	mu           sync.Mutex
+	primaryUntil time.Time
+}
+
+type readSessionKey struct{}
+
+func WithReadSession(ctx context.Context, session *ReadSession) context.Context {
+	return context.WithValue(ctx, readSessionKey{}, session)
+}
+
+// ReadSessionFrom возвращает сессию из ctx или nil, если её нет
+func ReadSessionFrom(ctx context.Context) *ReadSession {
+	session, _ := ctx.Value(readSessionKey{}).(*ReadSession)
+	return session
+}
+
+// StickToPrimary продлевает чтение с основной базы до until. Более ранний срок не сокращает уже назначенный
+func (s *ReadSession) StickToPrimary(until time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if until.After(s.primaryUntil) {
+		s.primaryUntil = until
+	}
+}
+
+func (s *ReadSession) PrimaryUntil() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.primaryUntil
+}
+
+// ReadFromPrimary сообщает, нужно ли в момент now читать с основной базы
+func (s *ReadSession) ReadFromPrimary(now time.Time) bool {
+	return now.Before(s.PrimaryUntil())
+}
@@ -1,13 +1,29 @@
 package dto
 
+import (
+	"database/sql"
+	"time"
+)
+
 type OrderDTO struct {
 	OrderID       string
 	RecipientID   string
-	ExpiryDate    string
+	ExpiryDate    time.Time
 	Status        string
-	DeliveryDate  string
-	ReturnDate    string
+	DeliveryDate  sql.NullTime
+	ReturnDate    sql.NullTime
 	Weight        float32
 	Cost          float32
 	PackagingType string
+	AcceptedAt    time.Time
+	Version       int64
+	UpdatedAt     time.Time
+}
+
+// ArchivedOrderDTO — заказ из архива. RemovedAt и RemovalReason заполнены, если заказ удалили с ПВЗ
+type ArchivedOrderDTO struct {
+	OrderDTO
+	RemovedAt     sql.NullTime
+	RemovalReason string
+	ArchivedAt    time.Time
 }
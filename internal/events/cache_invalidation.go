@@ -0,0 +1,10 @@
+package events
+
+import "time"
+
+// CacheInvalidation сообщает остальным репликам, что заказы Keys изменились и их нужно убрать из кэша
+type CacheInvalidation struct {
+	ReplicaID   string    `json:"replica_id"`
+	Keys        []string  `json:"keys"`
+	PublishedAt time.Time `json:"published_at"`
+}
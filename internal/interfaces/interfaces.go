@@ -12,26 +12,104 @@ type OrderRepository interface {
 	AddOrder(ctx context.Context, order *domain.Order) error
 	GetOrder(ctx context.Context, orderID string) (*domain.Order, error)
 	UpdateOrder(ctx context.Context, order *domain.Order) error
-	DeleteOrder(ctx context.Context, orderID string) error
-	ListOrdersByRecipient(ctx context.Context, recipientID string, limit int) ([]*domain.Order, error)
+	// DeleteOrder помечает заказ удалённым с причиной reason, после чего он не виден в выборках
+	DeleteOrder(ctx context.Context, orderID, reason string) error
+	ListOrdersByRecipient(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int) ([]*domain.Order, error)
+	ListOrders(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) ([]*domain.Order, error)
+	// AddOrders добавляет все заказы или ни одного, если хотя бы один уже есть
+	AddOrders(ctx context.Context, orders []*domain.Order) error
+	// GetOrdersByIDs возвращает найденные заказы в порядке orderIDs, отсутствующие пропускаются
+	GetOrdersByIDs(ctx context.Context, orderIDs []string) ([]*domain.Order, error)
+	// UpdateOrders обновляет заказы без повторов как UpdateOrder, но все или ни одного
+	UpdateOrders(ctx context.Context, orders []*domain.Order) error
+}
+
+// ArchiveRepository переносит закрытые заказы в архив и читает его
+type ArchiveRepository interface {
+	// ArchiveOrders переносит в архив до limit удалённых или выданных заказов, которые не менялись
+	// с closedBefore, и возвращает их число
+	ArchiveOrders(ctx context.Context, closedBefore time.Time, limit int) (int, error)
+	GetArchivedOrder(ctx context.Context, orderID string) (*domain.ArchivedOrder, error)
+	// ListArchivedOrders возвращает заказы из архива от последних принятых к первым, начиная со следующего
+	// после after. Пустой recipientID означает заказы всех получателей
+	ListArchivedOrders(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int) ([]*domain.ArchivedOrder, error)
 }
 
 type ReturnRepository interface {
 	AddReturn(ctx context.Context, ret *domain.Return) error
-	ListReturns(ctx context.Context, offset, limit int) ([]*domain.Return, error)
+	ListReturns(ctx context.Context, after *domain.ReturnCursor, limit int) ([]*domain.Return, error)
 }
 
 type TxManager interface {
 	RunInTransaction(ctx context.Context, fn func(ctx context.Context) error, opts *sql.TxOptions) error
 }
 
+type IdempotencyRepository interface {
+	// Reserve резервирует ключ за record и выставляет record.ReservedUntil. Если ключ занят живым
+	// резервированием или результатом, возвращает существующую запись
+	Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error)
+	// Complete и Release записывают результат или освобождают ключ, только пока он зарезервирован за record.Token
+	Complete(ctx context.Context, record *domain.IdempotencyRecord) error
+	Release(ctx context.Context, record *domain.IdempotencyRecord) error
+}
+
+// LegacyImportRepository записывает данные, перенесённые из старого файлового хранилища.
+// Методы работают в транзакции из ctx, если она есть
+type LegacyImportRepository interface {
+	// ExistingOrderIDs возвращает те из orderIDs, которые уже есть в хранилище
+	ExistingOrderIDs(ctx context.Context, orderIDs []string) ([]string, error)
+	// ReturnedOrderIDs возвращает те из orderIDs, по которым уже оформлен возврат
+	ReturnedOrderIDs(ctx context.Context, orderIDs []string) ([]string, error)
+	InsertOrders(ctx context.Context, orders []*domain.Order) error
+	InsertReturns(ctx context.Context, returns []*domain.Return) error
+}
+
 type Metrics interface {
 	IncOrdersServed()
+	IncInvalidationsPublished(err error)
+	IncInvalidationsApplied(keys int)
+	ObserveInvalidationLag(lag time.Duration)
+	// IncTxRetries считает повторы транзакций по SQLSTATE ошибки, из-за которой транзакция повторяется
+	IncTxRetries(sqlState string)
+	// IncTxRollbacks считает откаты транзакций и точек сохранения: error, panic, retryable или savepoint
+	IncTxRollbacks(reason string)
+}
+
+// CacheInvalidator сообщает другим репликам, что записи с ключами keys устарели
+type CacheInvalidator interface {
+	Invalidate(ctx context.Context, keys ...string)
 }
 
 type Cache[K comparable, V any] interface {
 	Set(ctx context.Context, key K, value V, ttl ...time.Duration)
 	Get(ctx context.Context, key K) (V, bool)
+	// GetOrLoad возвращает значение из кэша, а при промахе загружает его через load, выполняя одну загрузку
+	// на все конкурентные запросы ключа. Если ключа нет в источнике, load должен вернуть domain.ErrCacheMiss
+	GetOrLoad(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error)
 	Delete(ctx context.Context, key K)
 	Flush(ctx context.Context)
+	// GetMany, SetMany и DeleteMany обрабатывают несколько ключей за одну блокировку или один запрос.
+	// GetMany возвращает только найденные ключи
+	GetMany(ctx context.Context, keys []K) map[K]V
+	SetMany(ctx context.Context, values map[K]V, ttl ...time.Duration)
+	DeleteMany(ctx context.Context, keys []K)
+	// Hottest возвращает до limit живых элементов, начиная с самых востребованных, не меняя их позиций.
+	// При limit <= 0 возвращаются все элементы
+	Hottest(ctx context.Context, limit int) []domain.CacheItem[K, V]
+	// OnEvict подписывает fn на удаление элементов: вытеснение, истечение TTL, Delete и Flush.
+	// fn вызывается вне блокировок кэша и не должен долго блокироваться
+	OnEvict(fn func(key K, value V, reason domain.EvictionReason))
+	// Close останавливает фоновые задачи и освобождает ресурсы кэша
+	Close() error
+	// Inspect возвращает элемент, не меняя его позицию при вытеснении и не учитывая обращение в статистике
+	Inspect(ctx context.Context, key K) (domain.CacheEntry[V], bool)
+	Stats() domain.CacheStats
+	// RecordLoad учитывает длительность загрузки значения из источника после промаха
+	RecordLoad(d time.Duration)
+}
+
+// LocalInvalidator — кэш с локальным уровнем перед общим, например TieredBackend. InvalidateLocal удаляет
+// ключи только из памяти реплики: общий уровень другая реплика уже обновила сама
+type LocalInvalidator[K comparable] interface {
+	InvalidateLocal(ctx context.Context, keys []K)
 }
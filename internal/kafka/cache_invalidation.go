@@ -0,0 +1,199 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/events"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+)
+
+// CacheInvalidator публикует изменения заказов в топик инвалидации, чтобы другие реплики
+// убрали их из своих кэшей. Локальный кэш реплика обновляет сама
+type CacheInvalidator struct {
+	producer  Producer
+	topic     string
+	replicaID string
+	metrics   interfaces.Metrics
+}
+
+func NewCacheInvalidator(producer Producer, topic, replicaID string, metrics interfaces.Metrics) *CacheInvalidator {
+	return &CacheInvalidator{
+		producer:  producer,
+		topic:     topic,
+		replicaID: replicaID,
+		metrics:   metrics,
+	}
+}
+
+// Invalidate публикует сообщение об изменении keys. Запись в базу к этому моменту уже выполнена,
+// поэтому ошибка публикации только логируется: в худшем случае другие реплики отдадут
+// устаревшее значение до истечения TTL
+func (i *CacheInvalidator) Invalidate(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	message, err := json.Marshal(events.CacheInvalidation{
+		ReplicaID:   i.replicaID,
+		Keys:        keys,
+		PublishedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("Failed to marshal cache invalidation: %v", err)
+		return
+	}
+
+	// Ключ сообщения — реплика, чтобы её инвалидации читались в порядке публикации
+	err = i.producer.SendMessage(i.topic, i.replicaID, message)
+	i.metrics.IncInvalidationsPublished(err)
+	if err != nil {
+		log.Printf("Failed to publish cache invalidation for %v: %v", keys, err)
+	}
+}
+
+// CacheInvalidationHandler читает топик инвалидации и удаляет из локального кэша ключи,
+// изменённые другими репликами. Свои сообщения пропускаются: локальный кэш уже актуален.
+// У многоуровневого кэша очищается только локальный уровень: общий уже обновила реплика-автор изменения
+type CacheInvalidationHandler[V any] struct {
+	cache     interfaces.Cache[string, V]
+	replicaID string
+	metrics   interfaces.Metrics
+}
+
+func NewCacheInvalidationHandler[V any](cache interfaces.Cache[string, V], replicaID string, metrics interfaces.Metrics) *CacheInvalidationHandler[V] {
+	return &CacheInvalidationHandler[V]{
+		cache:     cache,
+		replicaID: replicaID,
+		metrics:   metrics,
+	}
+}
+
+func (h *CacheInvalidationHandler[V]) handle(ctx context.Context, value []byte) {
+	var invalidation events.CacheInvalidation
+	if err := json.Unmarshal(value, &invalidation); err != nil {
+		log.Printf("Skipping malformed cache invalidation: %v", err)
+		return
+	}
+	if invalidation.ReplicaID == h.replicaID {
+		return
+	}
+
+	if local, ok := h.cache.(interfaces.LocalInvalidator[string]); ok {
+		local.InvalidateLocal(ctx, invalidation.Keys)
+	} else {
+		h.cache.DeleteMany(ctx, invalidation.Keys)
+	}
+	h.metrics.IncInvalidationsApplied(len(invalidation.Keys))
+	h.metrics.ObserveInvalidationLag(time.Since(invalidation.PublishedAt))
+}
+
+// partitionRefreshInterval — как часто consumer проверяет, не добавили ли в топик инвалидации партиции
+const partitionRefreshInterval = time.Minute
+
+// RunCacheInvalidationConsumer подписывает кэш реплики на топик инвалидации. Сообщение должна получить каждая
+// реплика, поэтому топик читается напрямую из всех партиций, без группы потребителей и сохранённых смещений:
+// своя группа на каждую реплику оставалась бы в Kafka после её остановки. Чтение начинается с конца партиций,
+// зафиксированного до возврата из функции, поэтому инвалидации, опубликованные после запуска реплики, не теряются.
+// Партиции, добавленные в топик позже, подхватываются раз в partitionRefreshInterval и читаются с начала
+func RunCacheInvalidationConsumer[V any](ctx context.Context, brokers []string, topic, replicaID string, cache interfaces.Cache[string, V], metrics interfaces.Metrics) error {
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_5_0_0
+	// Список партиций берётся из метаданных клиента, поэтому они обновляются не реже, чем он проверяется
+	config.Metadata.RefreshFrequency = partitionRefreshInterval
+
+	consumer, err := sarama.NewConsumer(brokers, config)
+	if err != nil {
+		return err
+	}
+	return consumeInvalidations(ctx, consumer, topic, NewCacheInvalidationHandler(cache, replicaID, metrics), partitionRefreshInterval)
+}
+
+// invalidationConsumer читает партиции топика инвалидации и запоминает, какие из них уже читаются
+type invalidationConsumer[V any] struct {
+	consumer   sarama.Consumer
+	topic      string
+	handler    *CacheInvalidationHandler[V]
+	partitions map[int32]sarama.PartitionConsumer
+	wg         sync.WaitGroup
+}
+
+// consumeInvalidations читает новые сообщения всех партиций topic, пока не отменён ctx, и закрывает consumer.
+// Каждые refreshInterval список партиций перечитывается, и новые партиции читаются с самого начала:
+// сообщения в них могли появиться до того, как реплика их заметила
+func consumeInvalidations[V any](ctx context.Context, consumer sarama.Consumer, topic string, handler *CacheInvalidationHandler[V], refreshInterval time.Duration) error {
+	c := &invalidationConsumer[V]{
+		consumer:   consumer,
+		topic:      topic,
+		handler:    handler,
+		partitions: make(map[int32]sarama.PartitionConsumer),
+	}
+	if err := c.consumeNew(ctx, sarama.OffsetNewest); err != nil {
+		c.close()
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				c.close()
+				return
+			case <-ticker.C:
+				if err := c.consumeNew(ctx, sarama.OffsetOldest); err != nil {
+					log.Printf("Failed to refresh cache invalidation partitions: %v", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// consumeNew начинает читать с offset партиции топика, которые ещё не читаются
+func (c *invalidationConsumer[V]) consumeNew(ctx context.Context, offset int64) error {
+	partitions, err := c.consumer.Partitions(c.topic)
+	if err != nil {
+		return err
+	}
+
+	for _, partition := range partitions {
+		if _, ok := c.partitions[partition]; ok {
+			continue
+		}
+		partitionConsumer, err := c.consumer.ConsumePartition(c.topic, partition, offset)
+		if err != nil {
+			return err
+		}
+		if offset == sarama.OffsetOldest {
+			log.Printf("Consuming cache invalidations from new partition %d", partition)
+		}
+		c.partitions[partition] = partitionConsumer
+
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			for message := range partitionConsumer.Messages() {
+				c.handler.handle(ctx, message.Value)
+			}
+		}()
+	}
+	return nil
+}
+
+// close закрывает партиции, дожидается обработки прочитанных сообщений и закрывает клиент
+func (c *invalidationConsumer[V]) close() {
+	// Закрытие партиции закрывает её канал сообщений, а клиент закрывается после всех партиций
+	for _, partitionConsumer := range c.partitions {
+		partitionConsumer.AsyncClose()
+	}
+	c.wg.Wait()
+	if err := c.consumer.Close(); err != nil {
+		log.Printf("Failed to close cache invalidation consumer: %v", err)
+	}
+}
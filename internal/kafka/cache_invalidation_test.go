@@ -0,0 +1,225 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/cache"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/events"
+)
+
+type sentMessage struct {
+	topic string
+	key   string
+	value []byte
+}
+
+type mockProducer struct {
+	messages []sentMessage
+	err      error
+}
+
+func (m *mockProducer) SendMessage(topic string, key string, value []byte) error {
+	m.messages = append(m.messages, sentMessage{topic: topic, key: key, value: value})
+	return m.err
+}
+
+func (m *mockProducer) Close() error {
+	return nil
+}
+
+type mockMetrics struct {
+	// mu нужен, потому что партиции обрабатываются параллельно
+	mu                         sync.Mutex
+	published, failed, applied int
+	lags                       []time.Duration
+}
+
+func (m *mockMetrics) IncOrdersServed() {}
+
+func (m *mockMetrics) IncInvalidationsPublished(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		m.failed++
+		return
+	}
+	m.published++
+}
+
+func (m *mockMetrics) IncInvalidationsApplied(keys int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.applied += keys
+}
+
+func (m *mockMetrics) ObserveInvalidationLag(lag time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lags = append(m.lags, lag)
+}
+
+func (m *mockMetrics) IncTxRetries(sqlState string) {}
+
+func (m *mockMetrics) IncTxRollbacks(reason string) {}
+
+func TestCacheInvalidator_Invalidate(t *testing.T) {
+	producer := &mockProducer{}
+	metrics := &mockMetrics{}
+	invalidator := NewCacheInvalidator(producer, "pvz.cache-invalidation", "replica-a", metrics)
+
+	invalidator.Invalidate(context.Background(), "order1", "order2")
+	invalidator.Invalidate(context.Background())
+
+	require.Len(t, producer.messages, 1)
+	assert.Equal(t, "pvz.cache-invalidation", producer.messages[0].topic)
+	assert.Equal(t, "replica-a", producer.messages[0].key)
+
+	var invalidation events.CacheInvalidation
+	require.NoError(t, json.Unmarshal(producer.messages[0].value, &invalidation))
+	assert.Equal(t, "replica-a", invalidation.ReplicaID)
+	assert.Equal(t, []string{"order1", "order2"}, invalidation.Keys)
+	assert.WithinDuration(t, time.Now(), invalidation.PublishedAt, time.Second)
+	assert.Equal(t, 1, metrics.published)
+
+	producer.err = errors.New("broker unavailable")
+	invalidator.Invalidate(context.Background(), "order3")
+	assert.Equal(t, 1, metrics.failed)
+}
+
+func TestCacheInvalidationHandler_EvictsKeysFromOtherReplicas(t *testing.T) {
+	ctx := context.Background()
+
+	c := cache.NewLRUCache[string, string](10, time.Minute, time.Minute)
+	defer c.Close()
+	c.Set(ctx, "order1", "new")
+	c.Set(ctx, "order2", "new")
+
+	metrics := &mockMetrics{}
+	handler := NewCacheInvalidationHandler(c, "replica-b", metrics)
+
+	own, err := json.Marshal(events.CacheInvalidation{ReplicaID: "replica-b", Keys: []string{"order1"}, PublishedAt: time.Now()})
+	require.NoError(t, err)
+	handler.handle(ctx, own)
+
+	_, found := c.Get(ctx, "order1")
+	assert.True(t, found, "own invalidations must be skipped")
+
+	other, err := json.Marshal(events.CacheInvalidation{ReplicaID: "replica-a", Keys: []string{"order1", "order2"}, PublishedAt: time.Now().Add(-50 * time.Millisecond)})
+	require.NoError(t, err)
+	handler.handle(ctx, other)
+
+	_, found = c.Get(ctx, "order1")
+	assert.False(t, found)
+	_, found = c.Get(ctx, "order2")
+	assert.False(t, found)
+	assert.Equal(t, 2, metrics.applied)
+	require.Len(t, metrics.lags, 1)
+	assert.GreaterOrEqual(t, metrics.lags[0], 50*time.Millisecond)
+
+	handler.handle(ctx, []byte("not json"))
+	assert.Equal(t, 2, metrics.applied)
+}
+
+func TestCacheInvalidationHandler_KeepsSharedTier(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+
+	c, err := cache.NewCache[string, string](cache.CacheConfig{
+		Strategy:        cache.LRUStrategy,
+		MaxEntries:      10,
+		DefaultTTL:      time.Minute,
+		CleanupInterval: time.Minute,
+		Backend:         cache.TieredBackend,
+		Redis:           cache.RedisConfig{Addr: server.Addr(), KeyPrefix: "orders:"},
+	})
+	require.NoError(t, err)
+	defer c.Close()
+	c.Set(ctx, "order1", "old")
+
+	// Реплика-автор уже записала новое значение в общий кэш
+	require.NoError(t, server.Set("orders:order1", `"new"`))
+	invalidation, err := json.Marshal(events.CacheInvalidation{ReplicaID: "replica-a", Keys: []string{"order1"}, PublishedAt: time.Now()})
+	require.NoError(t, err)
+	NewCacheInvalidationHandler(c, "replica-b", &mockMetrics{}).handle(ctx, invalidation)
+
+	assert.True(t, server.Exists("orders:order1"), "the shared entry must survive a remote invalidation")
+	value, found := c.Get(ctx, "order1")
+	require.True(t, found)
+	assert.Equal(t, "new", value)
+}
+
+func TestConsumeInvalidations_ReadsAllPartitionsFromNewest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := cache.NewLRUCache[string, string](10, time.Minute, time.Minute)
+	defer c.Close()
+	c.Set(ctx, "order1", "new")
+	c.Set(ctx, "order2", "new")
+
+	message := func(key string) *sarama.ConsumerMessage {
+		value, err := json.Marshal(events.CacheInvalidation{ReplicaID: "replica-a", Keys: []string{key}, PublishedAt: time.Now()})
+		require.NoError(t, err)
+		return &sarama.ConsumerMessage{Value: value}
+	}
+
+	consumer := mocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{"pvz.cache-invalidation": {0, 1}})
+	consumer.ExpectConsumePartition("pvz.cache-invalidation", 0, sarama.OffsetNewest).YieldMessage(message("order1"))
+	consumer.ExpectConsumePartition("pvz.cache-invalidation", 1, sarama.OffsetNewest).YieldMessage(message("order2"))
+
+	metrics := &mockMetrics{}
+	require.NoError(t, consumeInvalidations(ctx, consumer, "pvz.cache-invalidation", NewCacheInvalidationHandler(c, "replica-b", metrics), time.Hour))
+
+	assert.Eventually(t, func() bool {
+		_, found1 := c.Get(ctx, "order1")
+		_, found2 := c.Get(ctx, "order2")
+		return !found1 && !found2
+	}, time.Second, 10*time.Millisecond, "invalidations from every partition must be applied")
+}
+
+func TestConsumeInvalidations_PicksUpNewPartitions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := cache.NewLRUCache[string, string](10, time.Minute, time.Minute)
+	defer c.Close()
+	c.Set(ctx, "order1", "new")
+
+	consumer := mocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{"pvz.cache-invalidation": {0}})
+	consumer.ExpectConsumePartition("pvz.cache-invalidation", 0, sarama.OffsetNewest)
+
+	require.NoError(t, consumeInvalidations(ctx, consumer, "pvz.cache-invalidation", NewCacheInvalidationHandler(c, "replica-b", &mockMetrics{}), 10*time.Millisecond))
+
+	// Сообщение в добавленной партиции могло появиться раньше, чем реплика её заметила, поэтому она читается с начала
+	value, err := json.Marshal(events.CacheInvalidation{ReplicaID: "replica-a", Keys: []string{"order1"}, PublishedAt: time.Now()})
+	require.NoError(t, err)
+	consumer.ExpectConsumePartition("pvz.cache-invalidation", 1, sarama.OffsetOldest).YieldMessage(&sarama.ConsumerMessage{Value: value})
+	consumer.SetTopicMetadata(map[string][]int32{"pvz.cache-invalidation": {0, 1}})
+
+	assert.Eventually(t, func() bool {
+		_, found := c.Get(ctx, "order1")
+		return !found
+	}, time.Second, 10*time.Millisecond, "invalidations from a partition added after startup must be applied")
+}
+
+func TestConsumeInvalidations_UnknownTopic(t *testing.T) {
+	consumer := mocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{})
+
+	c := cache.NewLRUCache[string, string](10, time.Minute, time.Minute)
+	defer c.Close()
+	err := consumeInvalidations(context.Background(), consumer, "pvz.cache-invalidation", NewCacheInvalidationHandler(c, "replica-b", &mockMetrics{}), time.Hour)
+	assert.Error(t, err)
+}
@@ -13,17 +13,18 @@ type ConsumerHandler interface {
 	ConsumeClaim(sarama.ConsumerGroupSession, sarama.ConsumerGroupClaim) error
 }
 
-func NewConsumer(brokers []string, groupID string, topics []string, handler ConsumerHandler) error {
+// NewConsumer подключается к группе groupID и читает topics, пока не отменён ctx
+func NewConsumer(ctx context.Context, brokers []string, groupID string, topics []string, handler ConsumerHandler) error {
 	config := sarama.NewConfig()
 	config.Version = sarama.V2_5_0_0
 
-	ctx := context.Background()
 	client, err := sarama.NewConsumerGroup(brokers, groupID, config)
 	if err != nil {
 		return err
 	}
 
 	go func() {
+		defer client.Close()
 		for {
 			if err := client.Consume(ctx, topics, handler); err != nil {
 				log.Printf("Error from consumer: %v", err)
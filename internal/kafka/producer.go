@@ -48,3 +48,14 @@ func (p *SyncProducer) SendMessage(topic string, key string, value []byte) error
 func (p *SyncProducer) Close() error {
 	return p.producer.Close()
 }
+
+// NopProducer отбрасывает сообщения. Его получает сервер, который работает без Kafka
+type NopProducer struct{}
+
+func (NopProducer) SendMessage(topic string, key string, value []byte) error {
+	return nil
+}
+
+func (NopProducer) Close() error {
+	return nil
+}
@@ -0,0 +1,236 @@
+package legacy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/orders"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/returns"
+)
+
+type ProblemKind string
+
+const (
+	// ProblemInvalid — запись не удалось перевести в новый формат
+	ProblemInvalid ProblemKind = "invalid"
+	// ProblemConflict — запись уже есть в хранилище
+	ProblemConflict ProblemKind = "conflict"
+)
+
+// Problem описывает запись старых файлов, которая не будет перенесена
+type Problem struct {
+	Kind ProblemKind
+	// Entity — order или return
+	Entity string
+	ID     string
+	Reason string
+}
+
+// Report — итог переноса. В пробном запуске Imported — сколько записей было бы перенесено
+type Report struct {
+	DryRun          bool
+	OrdersRead      int
+	ReturnsRead     int
+	OrdersImported  int
+	ReturnsImported int
+	Problems        []Problem
+}
+
+// Importer переносит данные пачками, каждая пачка записывается в своей транзакции.
+// Уже перенесённые записи при повторном запуске считаются конфликтами и пропускаются,
+// поэтому прерванный перенос можно просто запустить заново
+type Importer struct {
+	repo      interfaces.LegacyImportRepository
+	txManager interfaces.TxManager
+	batchSize int
+	now       func() time.Time
+}
+
+func NewImporter(repo interfaces.LegacyImportRepository, txManager interfaces.TxManager, batchSize int) *Importer {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	return &Importer{
+		repo:      repo,
+		txManager: txManager,
+		batchSize: batchSize,
+		now:       time.Now,
+	}
+}
+
+// Import проверяет записи старых файлов и, если dryRun не задан, переносит подходящие.
+// Сначала переносятся заказы, затем возвраты, которые на них ссылаются. При ошибке записи
+// возвращается отчёт о том, что успело перенестись
+func (i *Importer) Import(ctx context.Context, legacyOrders []orders.Order, legacyReturns []returns.Return, dryRun bool) (*Report, error) {
+	report := &Report{
+		DryRun:      dryRun,
+		OrdersRead:  len(legacyOrders),
+		ReturnsRead: len(legacyReturns),
+	}
+
+	importOrders, err := i.planOrders(ctx, legacyOrders, report)
+	if err != nil {
+		return report, err
+	}
+	importReturns, err := i.planReturns(ctx, legacyReturns, importOrders, report)
+	if err != nil {
+		return report, err
+	}
+
+	if dryRun {
+		report.OrdersImported = len(importOrders)
+		report.ReturnsImported = len(importReturns)
+		return report, nil
+	}
+
+	report.OrdersImported, err = i.inBatches(ctx, len(importOrders), func(ctx context.Context, from, to int) error {
+		return i.repo.InsertOrders(ctx, importOrders[from:to])
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to import orders: %w", err)
+	}
+	report.ReturnsImported, err = i.inBatches(ctx, len(importReturns), func(ctx context.Context, from, to int) error {
+		return i.repo.InsertReturns(ctx, importReturns[from:to])
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to import returns: %w", err)
+	}
+	return report, nil
+}
+
+// planOrders отбирает заказы для переноса: корректные, не повторяющиеся в файле и отсутствующие в хранилище
+func (i *Importer) planOrders(ctx context.Context, legacyOrders []orders.Order, report *Report) ([]*domain.Order, error) {
+	acceptedAt := i.now()
+	seen := make(map[string]struct{}, len(legacyOrders))
+	mapped := make([]*domain.Order, 0, len(legacyOrders))
+	for _, legacyOrder := range legacyOrders {
+		order, err := mapOrder(legacyOrder, acceptedAt)
+		if err != nil {
+			report.addProblem(ProblemInvalid, "order", legacyOrder.ID, err.Error())
+			continue
+		}
+		if _, ok := seen[order.OrderID]; ok {
+			report.addProblem(ProblemInvalid, "order", order.OrderID, "duplicate id in legacy file")
+			continue
+		}
+		seen[order.OrderID] = struct{}{}
+		mapped = append(mapped, order)
+	}
+
+	ids := make([]string, 0, len(mapped))
+	for _, order := range mapped {
+		ids = append(ids, order.OrderID)
+	}
+	existing, err := i.lookup(ctx, ids, i.repo.ExistingOrderIDs)
+	if err != nil {
+		return nil, err
+	}
+	planned := mapped[:0]
+	for _, order := range mapped {
+		if _, ok := existing[order.OrderID]; ok {
+			report.addProblem(ProblemConflict, "order", order.OrderID, "order already exists")
+			continue
+		}
+		planned = append(planned, order)
+	}
+	return planned, nil
+}
+
+// planReturns отбирает возвраты для переноса. Заказ возврата должен переноситься вместе с ним
+// или уже быть в хранилище, а возврат по этому заказу ещё не оформлен
+func (i *Importer) planReturns(ctx context.Context, legacyReturns []returns.Return, importOrders []*domain.Order, report *Report) ([]*domain.Return, error) {
+	planned := make(map[string]*domain.Order, len(importOrders))
+	for _, order := range importOrders {
+		planned[order.OrderID] = order
+	}
+
+	seen := make(map[string]struct{}, len(legacyReturns))
+	mapped := make([]*domain.Return, 0, len(legacyReturns))
+	for _, legacyReturn := range legacyReturns {
+		ret, err := mapReturn(legacyReturn)
+		if err != nil {
+			report.addProblem(ProblemInvalid, "return", legacyReturn.ID, err.Error())
+			continue
+		}
+		if _, ok := seen[ret.OrderID]; ok {
+			report.addProblem(ProblemInvalid, "return", ret.OrderID, "duplicate id in legacy file")
+			continue
+		}
+		seen[ret.OrderID] = struct{}{}
+		if order, ok := planned[ret.OrderID]; ok && order.RecipientID != ret.RecipientID {
+			report.addProblem(ProblemInvalid, "return", ret.OrderID, fmt.Sprintf("recipient %s does not match order recipient %s", ret.RecipientID, order.RecipientID))
+			continue
+		}
+		mapped = append(mapped, ret)
+	}
+
+	ids := make([]string, 0, len(mapped))
+	for _, ret := range mapped {
+		ids = append(ids, ret.OrderID)
+	}
+	existingOrders, err := i.lookup(ctx, ids, i.repo.ExistingOrderIDs)
+	if err != nil {
+		return nil, err
+	}
+	returned, err := i.lookup(ctx, ids, i.repo.ReturnedOrderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := mapped[:0]
+	for _, ret := range mapped {
+		order, inImport := planned[ret.OrderID]
+		_, inStorage := existingOrders[ret.OrderID]
+		_, isReturned := returned[ret.OrderID]
+		switch {
+		case !inImport && !inStorage:
+			report.addProblem(ProblemInvalid, "return", ret.OrderID, "order not found")
+			continue
+		case isReturned:
+			report.addProblem(ProblemConflict, "return", ret.OrderID, "return already exists")
+			continue
+		}
+		if inImport && order.Status == domain.OrderStatusReturned {
+			order.ReturnDate.Time, order.ReturnDate.Valid = ret.ReturnDate, true
+		}
+		result = append(result, ret)
+	}
+	return result, nil
+}
+
+// lookup передаёт идентификаторы в find пачками и возвращает найденные
+func (i *Importer) lookup(ctx context.Context, ids []string, find func(ctx context.Context, ids []string) ([]string, error)) (map[string]struct{}, error) {
+	found := make(map[string]struct{})
+	for from := 0; from < len(ids); from += i.batchSize {
+		batch, err := find(ctx, ids[from:min(from+i.batchSize, len(ids))])
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range batch {
+			found[id] = struct{}{}
+		}
+	}
+	return found, nil
+}
+
+// inBatches вызывает fn для каждой пачки [from, to) в отдельной транзакции
+// и возвращает число записей в зафиксированных пачках
+func (i *Importer) inBatches(ctx context.Context, total int, fn func(ctx context.Context, from, to int) error) (int, error) {
+	for from := 0; from < total; from += i.batchSize {
+		to := min(from+i.batchSize, total)
+		err := i.txManager.RunInTransaction(ctx, func(ctx context.Context) error {
+			return fn(ctx, from, to)
+		}, nil)
+		if err != nil {
+			return from, fmt.Errorf("batch %d-%d: %w", from, to, err)
+		}
+	}
+	return total, nil
+}
+
+func (r *Report) addProblem(kind ProblemKind, entity, id, reason string) {
+	r.Problems = append(r.Problems, Problem{Kind: kind, Entity: entity, ID: id, Reason: reason})
+}
@@ -0,0 +1,152 @@
+package legacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/orders"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/memory"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/returns"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeImportRepository struct {
+	existing    []string
+	returned    []string
+	orders      []*domain.Order
+	returns     []*domain.Return
+	insertCalls int
+	failOnCall  int
+}
+
+func (r *fakeImportRepository) ExistingOrderIDs(_ context.Context, orderIDs []string) ([]string, error) {
+	return intersect(orderIDs, r.existing), nil
+}
+
+func (r *fakeImportRepository) ReturnedOrderIDs(_ context.Context, orderIDs []string) ([]string, error) {
+	return intersect(orderIDs, r.returned), nil
+}
+
+func (r *fakeImportRepository) InsertOrders(_ context.Context, orders []*domain.Order) error {
+	r.insertCalls++
+	if r.insertCalls == r.failOnCall {
+		return errors.New("insert failed")
+	}
+	r.orders = append(r.orders, orders...)
+	return nil
+}
+
+func (r *fakeImportRepository) InsertReturns(_ context.Context, returns []*domain.Return) error {
+	r.returns = append(r.returns, returns...)
+	return nil
+}
+
+func intersect(ids, known []string) []string {
+	var found []string
+	for _, id := range ids {
+		if slices.Contains(known, id) {
+			found = append(found, id)
+		}
+	}
+	return found
+}
+
+func newTestImporter(repo *fakeImportRepository, batchSize int) *Importer {
+	importer := NewImporter(repo, memory.NewTxManager(memory.NewStore()), batchSize)
+	importer.now = func() time.Time { return time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC) }
+	return importer
+}
+
+func TestImporter_Import(t *testing.T) {
+	repo := &fakeImportRepository{existing: []string{"exists", "returned"}, returned: []string{"returned"}}
+	importer := newTestImporter(repo, 2)
+
+	legacyOrders := []orders.Order{
+		{ID: "order1", RecipientID: "recipient1", ExpiryDate: "2024-10-10", Status: "new"},
+		{ID: "order2", RecipientID: "recipient1", ExpiryDate: "2024-10-10T12:00:00Z", Status: "Returned"},
+		{ID: "order2", RecipientID: "recipient1", ExpiryDate: "2024-10-10", Status: "new"},
+		{ID: "order3", RecipientID: "recipient2", ExpiryDate: "10.10.2024"},
+		{ID: "bad-date", RecipientID: "recipient1", ExpiryDate: "tomorrow"},
+		{ID: "bad-status", RecipientID: "recipient1", ExpiryDate: "2024-10-10", Status: "lost"},
+		{ID: "", RecipientID: "recipient1", ExpiryDate: "2024-10-10"},
+		{ID: "exists", RecipientID: "recipient1", ExpiryDate: "2024-10-10"},
+	}
+	legacyReturns := []returns.Return{
+		{ID: "order2", RecipientID: "recipient1", ReceivedDate: "2024-10-05"},
+		{ID: "order3", RecipientID: "recipient1", ReceivedDate: "2024-10-05"},
+		{ID: "exists", RecipientID: "recipient1", ReceivedDate: "2024-10-05"},
+		{ID: "returned", RecipientID: "recipient1", ReceivedDate: "2024-10-05"},
+		{ID: "missing", RecipientID: "recipient1", ReceivedDate: "2024-10-05"},
+	}
+
+	report, err := importer.Import(context.Background(), legacyOrders, legacyReturns, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 8, report.OrdersRead)
+	assert.Equal(t, 3, report.OrdersImported)
+	assert.Equal(t, 5, report.ReturnsRead)
+	assert.Equal(t, 2, report.ReturnsImported)
+	assert.ElementsMatch(t, []Problem{
+		{Kind: ProblemInvalid, Entity: "order", ID: "order2", Reason: "duplicate id in legacy file"},
+		{Kind: ProblemInvalid, Entity: "order", ID: "bad-date", Reason: `invalid expiry date: "tomorrow" does not match any of 2006-01-02, 2006-01-02T15:04:05Z07:00, 02.01.2006`},
+		{Kind: ProblemInvalid, Entity: "order", ID: "bad-status", Reason: `unknown status "lost"`},
+		{Kind: ProblemInvalid, Entity: "order", ID: "", Reason: "empty order id"},
+		{Kind: ProblemConflict, Entity: "order", ID: "exists", Reason: "order already exists"},
+		{Kind: ProblemInvalid, Entity: "return", ID: "order3", Reason: "recipient recipient1 does not match order recipient recipient2"},
+		{Kind: ProblemConflict, Entity: "return", ID: "returned", Reason: "return already exists"},
+		{Kind: ProblemInvalid, Entity: "return", ID: "missing", Reason: "order not found"},
+	}, report.Problems)
+
+	require.Len(t, repo.orders, 3)
+	order2 := repo.orders[1]
+	assert.Equal(t, "order2", order2.OrderID)
+	assert.Equal(t, domain.OrderStatusReturned, order2.Status)
+	assert.True(t, order2.ReturnDate.Valid)
+	assert.Equal(t, domain.OrderStatusNew, repo.orders[2].Status)
+	assert.Equal(t, time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), repo.orders[2].AcceptedAt)
+
+	var returnedIDs []string
+	for _, ret := range repo.returns {
+		returnedIDs = append(returnedIDs, ret.OrderID)
+	}
+	assert.Equal(t, []string{"order2", "exists"}, returnedIDs)
+}
+
+func TestImporter_DryRun(t *testing.T) {
+	repo := &fakeImportRepository{}
+	importer := newTestImporter(repo, 10)
+
+	report, err := importer.Import(context.Background(),
+		[]orders.Order{{ID: "order1", RecipientID: "recipient1", ExpiryDate: "2024-10-10"}},
+		[]returns.Return{{ID: "order1", RecipientID: "recipient1", ReceivedDate: "2024-10-05"}},
+		true)
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.OrdersImported)
+	assert.Equal(t, 1, report.ReturnsImported)
+	assert.Empty(t, repo.orders)
+	assert.Empty(t, repo.returns)
+}
+
+func TestImporter_StopsOnFailedBatch(t *testing.T) {
+	repo := &fakeImportRepository{failOnCall: 3}
+	importer := newTestImporter(repo, 2)
+
+	var legacyOrders []orders.Order
+	for i := 0; i < 5; i++ {
+		legacyOrders = append(legacyOrders, orders.Order{ID: fmt.Sprintf("order%d", i), RecipientID: "recipient1", ExpiryDate: "2024-10-10"})
+	}
+
+	report, err := importer.Import(context.Background(), legacyOrders, nil, false)
+	assert.ErrorContains(t, err, "batch 4-5")
+	assert.Equal(t, 4, report.OrdersImported)
+	assert.Len(t, repo.orders, 4)
+}
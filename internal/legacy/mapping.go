@@ -0,0 +1,80 @@
+// Package legacy переносит заказы и возвраты из JSON-файлов старого хранилища (internal/orders,
+// internal/returns) в основное хранилище
+package legacy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/orders"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/returns"
+)
+
+// dateLayouts — форматы дат, которые встречаются в старых файлах
+var dateLayouts = []string{time.DateOnly, time.RFC3339, "02.01.2006"}
+
+var knownStatuses = []string{domain.OrderStatusNew, domain.OrderStatusDelivered, domain.OrderStatusReturned}
+
+// mapOrder переводит заказ старого формата в domain.Order. В старом формате нет веса, стоимости,
+// упаковки и даты приёма: вес и стоимость остаются нулевыми, а датой приёма считается acceptedAt
+func mapOrder(legacy orders.Order, acceptedAt time.Time) (*domain.Order, error) {
+	if strings.TrimSpace(legacy.ID) == "" {
+		return nil, fmt.Errorf("empty order id")
+	}
+	if strings.TrimSpace(legacy.RecipientID) == "" {
+		return nil, fmt.Errorf("empty recipient id")
+	}
+	expiryDate, err := parseDate(legacy.ExpiryDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiry date: %w", err)
+	}
+
+	status := strings.ToLower(strings.TrimSpace(legacy.Status))
+	if status == "" {
+		status = domain.OrderStatusNew
+	}
+	if !slices.Contains(knownStatuses, status) {
+		return nil, fmt.Errorf("unknown status %q", legacy.Status)
+	}
+
+	return &domain.Order{
+		OrderID:     legacy.ID,
+		RecipientID: legacy.RecipientID,
+		ExpiryDate:  expiryDate,
+		Status:      status,
+		AcceptedAt:  acceptedAt,
+	}, nil
+}
+
+// mapReturn переводит возврат старого формата в domain.Return. В старом формате идентификатор
+// возврата совпадает с идентификатором заказа, а ReceivedDate — дата возврата
+func mapReturn(legacy returns.Return) (*domain.Return, error) {
+	if strings.TrimSpace(legacy.ID) == "" {
+		return nil, fmt.Errorf("empty order id")
+	}
+	if strings.TrimSpace(legacy.RecipientID) == "" {
+		return nil, fmt.Errorf("empty recipient id")
+	}
+	returnDate, err := parseDate(legacy.ReceivedDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid received date: %w", err)
+	}
+	return &domain.Return{
+		OrderID:     legacy.ID,
+		RecipientID: legacy.RecipientID,
+		ReturnDate:  returnDate,
+	}, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q does not match any of %s", value, strings.Join(dateLayouts, ", "))
+}
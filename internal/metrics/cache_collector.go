@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+)
+
+var (
+	cacheHitsDesc = prometheus.NewDesc(
+		"cache_hits_total", "Total number of cache hits", []string{"cache"}, nil,
+	)
+	cacheMissesDesc = prometheus.NewDesc(
+		"cache_misses_total", "Total number of cache misses", []string{"cache"}, nil,
+	)
+	cacheEvictionsDesc = prometheus.NewDesc(
+		"cache_evictions_total", "Total number of cache evictions by reason", []string{"cache", "reason"}, nil,
+	)
+	cacheSizeDesc = prometheus.NewDesc(
+		"cache_entries", "Current number of entries in the cache", []string{"cache"}, nil,
+	)
+	cacheCostDesc = prometheus.NewDesc(
+		"cache_cost", "Current total cost of cache entries, usually an approximate size in bytes", []string{"cache"}, nil,
+	)
+	cacheLoadDesc = prometheus.NewDesc(
+		"cache_load_duration_seconds", "Time spent loading values into the cache after a miss", []string{"cache"}, nil,
+	)
+)
+
+// cacheCollector снимает статистику кэша в момент сбора метрик, поэтому кэшу
+// не нужно знать о Prometheus
+type cacheCollector struct {
+	name  string
+	stats func() domain.CacheStats
+}
+
+// NewCacheCollector возвращает коллектор статистики кэша с меткой cache=name
+func NewCacheCollector(name string, stats func() domain.CacheStats) prometheus.Collector {
+	return &cacheCollector{name: name, stats: stats}
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheHitsDesc
+	ch <- cacheMissesDesc
+	ch <- cacheEvictionsDesc
+	ch <- cacheSizeDesc
+	ch <- cacheCostDesc
+	ch <- cacheLoadDesc
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+
+	ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(stats.Hits), c.name)
+	ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(stats.Misses), c.name)
+	ch <- prometheus.MustNewConstMetric(cacheEvictionsDesc, prometheus.CounterValue, float64(stats.CapacityEvictions), c.name, "capacity")
+	ch <- prometheus.MustNewConstMetric(cacheEvictionsDesc, prometheus.CounterValue, float64(stats.ExpiredEvictions), c.name, "ttl")
+	ch <- prometheus.MustNewConstMetric(cacheSizeDesc, prometheus.GaugeValue, float64(stats.Size), c.name)
+	ch <- prometheus.MustNewConstMetric(cacheCostDesc, prometheus.GaugeValue, float64(stats.Cost), c.name)
+	ch <- prometheus.MustNewConstSummary(cacheLoadDesc, stats.Loads, stats.LoadTime.Seconds(), nil, c.name)
+}
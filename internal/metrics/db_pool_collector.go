@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+)
+
+var (
+	dbPoolConnsDesc = prometheus.NewDesc(
+		"db_pool_connections", "Current number of pool connections by state", []string{"pool", "state"}, nil,
+	)
+	dbPoolMaxConnsDesc = prometheus.NewDesc(
+		"db_pool_max_connections", "Maximum size of the pool", []string{"pool"}, nil,
+	)
+	dbPoolAcquiresDesc = prometheus.NewDesc(
+		"db_pool_acquires_total", "Total number of connection acquires by result", []string{"pool", "result"}, nil,
+	)
+	dbPoolAcquireDurationDesc = prometheus.NewDesc(
+		"db_pool_acquire_duration_seconds_total", "Total time spent waiting for a pool connection", []string{"pool"}, nil,
+	)
+	dbPoolNewConnsDesc = prometheus.NewDesc(
+		"db_pool_new_connections_total", "Total number of connections opened by the pool", []string{"pool"}, nil,
+	)
+	dbPoolClosedConnsDesc = prometheus.NewDesc(
+		"db_pool_closed_connections_total", "Total number of connections closed by the pool by reason", []string{"pool", "reason"}, nil,
+	)
+)
+
+// dbPoolCollector снимает статистику пула подключений в момент сбора метрик
+type dbPoolCollector struct {
+	name  string
+	stats func() domain.DBPoolStats
+}
+
+// NewDBPoolCollector возвращает коллектор статистики пула подключений с меткой pool=name
+func NewDBPoolCollector(name string, stats func() domain.DBPoolStats) prometheus.Collector {
+	return &dbPoolCollector{name: name, stats: stats}
+}
+
+func (c *dbPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dbPoolConnsDesc
+	ch <- dbPoolMaxConnsDesc
+	ch <- dbPoolAcquiresDesc
+	ch <- dbPoolAcquireDurationDesc
+	ch <- dbPoolNewConnsDesc
+	ch <- dbPoolClosedConnsDesc
+}
+
+func (c *dbPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+
+	ch <- prometheus.MustNewConstMetric(dbPoolConnsDesc, prometheus.GaugeValue, float64(stats.AcquiredConns), c.name, "acquired")
+	ch <- prometheus.MustNewConstMetric(dbPoolConnsDesc, prometheus.GaugeValue, float64(stats.IdleConns), c.name, "idle")
+	ch <- prometheus.MustNewConstMetric(dbPoolConnsDesc, prometheus.GaugeValue, float64(stats.TotalConns), c.name, "total")
+	ch <- prometheus.MustNewConstMetric(dbPoolMaxConnsDesc, prometheus.GaugeValue, float64(stats.MaxConns), c.name)
+	// Выдачи без ожидания, с ожиданием и прерванные ожидания
+	immediate := stats.AcquireCount - stats.EmptyAcquireCount
+	ch <- prometheus.MustNewConstMetric(dbPoolAcquiresDesc, prometheus.CounterValue, float64(immediate), c.name, "immediate")
+	ch <- prometheus.MustNewConstMetric(dbPoolAcquiresDesc, prometheus.CounterValue, float64(stats.EmptyAcquireCount), c.name, "waited")
+	ch <- prometheus.MustNewConstMetric(dbPoolAcquiresDesc, prometheus.CounterValue, float64(stats.CanceledAcquireCount), c.name, "canceled")
+	ch <- prometheus.MustNewConstMetric(dbPoolAcquireDurationDesc, prometheus.CounterValue, stats.AcquireDuration.Seconds(), c.name)
+	ch <- prometheus.MustNewConstMetric(dbPoolNewConnsDesc, prometheus.CounterValue, float64(stats.NewConnsCount), c.name)
+	ch <- prometheus.MustNewConstMetric(dbPoolClosedConnsDesc, prometheus.CounterValue, float64(stats.MaxLifetimeDestroyCount), c.name, "max_lifetime")
+	ch <- prometheus.MustNewConstMetric(dbPoolClosedConnsDesc, prometheus.CounterValue, float64(stats.MaxIdleDestroyCount), c.name, "max_idle")
+}
@@ -2,13 +2,19 @@ package metrics
 
 import (
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
 )
 
 type metrics struct {
-	ordersServed prometheus.Counter
+	ordersServed           prometheus.Counter
+	invalidationsPublished *prometheus.CounterVec
+	invalidationsApplied   prometheus.Counter
+	invalidationLag        prometheus.Histogram
+	txRetries              *prometheus.CounterVec
+	txRollbacks            *prometheus.CounterVec
 }
 
 var once sync.Once
@@ -16,14 +22,36 @@ var instance interfaces.Metrics
 
 func GetMetrics() interfaces.Metrics {
 	once.Do(func() {
-		instance = &metrics{
+		m := &metrics{
 			ordersServed: prometheus.NewCounter(prometheus.CounterOpts{
 				Name: "orders_served_total",
 				Help: "Total number of orders served",
 			}),
+			invalidationsPublished: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "cache_invalidations_published_total",
+				Help: "Total number of cache invalidation messages published, by result",
+			}, []string{"result"}),
+			invalidationsApplied: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "cache_invalidations_applied_total",
+				Help: "Total number of cache keys evicted on invalidation messages from other replicas",
+			}),
+			invalidationLag: prometheus.NewHistogram(prometheus.HistogramOpts{
+				Name:    "cache_invalidation_lag_seconds",
+				Help:    "Time between publishing a cache invalidation and applying it on another replica",
+				Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+			}),
+			txRetries: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "db_tx_retries_total",
+				Help: "Total number of transactions retried after a serialization failure or deadlock, by SQLSTATE",
+			}, []string{"sqlstate"}),
+			txRollbacks: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "db_tx_rollbacks_total",
+				Help: "Total number of rolled back transactions and savepoints, by reason",
+			}, []string{"reason"}),
 		}
 
-		prometheus.MustRegister(instance.(*metrics).ordersServed)
+		prometheus.MustRegister(m.ordersServed, m.invalidationsPublished, m.invalidationsApplied, m.invalidationLag, m.txRetries, m.txRollbacks)
+		instance = m
 	})
 	return instance
 }
@@ -31,3 +59,27 @@ func GetMetrics() interfaces.Metrics {
 func (m *metrics) IncOrdersServed() {
 	m.ordersServed.Inc()
 }
+
+func (m *metrics) IncInvalidationsPublished(err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	m.invalidationsPublished.WithLabelValues(result).Inc()
+}
+
+func (m *metrics) IncInvalidationsApplied(keys int) {
+	m.invalidationsApplied.Add(float64(keys))
+}
+
+func (m *metrics) ObserveInvalidationLag(lag time.Duration) {
+	m.invalidationLag.Observe(lag.Seconds())
+}
+
+func (m *metrics) IncTxRetries(sqlState string) {
+	m.txRetries.WithLabelValues(sqlState).Inc()
+}
+
+func (m *metrics) IncTxRollbacks(reason string) {
+	m.txRollbacks.WithLabelValues(reason).Inc()
+}
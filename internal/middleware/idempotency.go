@@ -0,0 +1,240 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const IdempotencyKeyHeader = "idempotency-key"
+
+// ifMatchHeader — метаданные с ожидаемой версией заказа. Они меняют смысл запроса, поэтому входят в его отпечаток
+const ifMatchHeader = "if-match"
+
+// idempotencyStoreTimeout ограничивает сохранение результата и освобождение ключа. Они выполняются
+// и после отмены запроса, иначе ключ остался бы зарезервированным до истечения аренды
+const idempotencyStoreTimeout = 5 * time.Second
+
+// IdempotencyInterceptor сохраняет результат запросов с заголовком idempotency-key вместе с заголовками
+// ответа и возвращает их при повторе. Повторное использование ключа с другим телом запроса или другим
+// If-Match отклоняется с кодом FailedPrecondition
+func IdempotencyInterceptor(store interfaces.IdempotencyRepository, methods ...string) grpc.UnaryServerInterceptor {
+	enabled := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		enabled[method] = struct{}{}
+	}
+
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if _, ok := enabled[info.FullMethod]; !ok {
+			return handler(ctx, req)
+		}
+		key := idempotencyKey(ctx)
+		if key == "" {
+			return handler(ctx, req)
+		}
+
+		fingerprint, err := requestFingerprint(ctx, req)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to fingerprint request: %v", err)
+		}
+
+		token, err := reservationToken()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to generate reservation token: %v", err)
+		}
+
+		record := &domain.IdempotencyRecord{
+			Key:         key,
+			Method:      info.FullMethod,
+			Fingerprint: fingerprint,
+			CreatedAt:   time.Now(),
+			Token:       token,
+		}
+		existing, err := store.Reserve(ctx, record)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to reserve idempotency key: %v", err)
+		}
+		if existing != nil {
+			return replay(ctx, existing, fingerprint)
+		}
+
+		stream := &headerCapture{ServerTransportStream: grpc.ServerTransportStreamFromContext(ctx)}
+		resp, err := handler(grpc.NewContextWithServerTransportStream(ctx, stream), req)
+
+		storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), idempotencyStoreTimeout)
+		defer cancel()
+
+		st := status.Convert(err)
+		if isRetryable(st.Code()) {
+			if releaseErr := store.Release(storeCtx, record); releaseErr != nil {
+				log.Printf("Failed to release idempotency key %s: %v", key, releaseErr)
+			}
+			return resp, err
+		}
+
+		record.StatusCode = sql.NullInt32{Int32: int32(st.Code()), Valid: true}
+		record.StatusMessage = st.Message()
+		if len(stream.header) > 0 {
+			record.ResponseHeaders, err = json.Marshal(stream.header)
+			if err != nil {
+				log.Printf("Failed to marshal response headers for idempotency key %s: %v", key, err)
+				_ = store.Release(storeCtx, record)
+				return resp, st.Err()
+			}
+		}
+		if st.Code() == codes.OK {
+			record.Response, err = marshalResponse(resp)
+			if err != nil {
+				log.Printf("Failed to marshal response for idempotency key %s: %v", key, err)
+				_ = store.Release(storeCtx, record)
+				return resp, nil
+			}
+		}
+		if completeErr := store.Complete(storeCtx, record); completeErr != nil {
+			log.Printf("Failed to store result for idempotency key %s: %v", key, completeErr)
+		}
+
+		return resp, st.Err()
+	}
+}
+
+func idempotencyKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(IdempotencyKeyHeader)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// reservationToken возвращает случайный токен, по которому резервирование отличается от перехватившего ключ повтора
+func reservationToken() (string, error) {
+	var token [16]byte
+	if _, err := rand.Read(token[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token[:]), nil
+}
+
+// headerCapture запоминает заголовки ответа, которые отдаёт обработчик, чтобы сохранить их вместе с результатом
+type headerCapture struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *headerCapture) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	if s.ServerTransportStream == nil {
+		return nil
+	}
+	return s.ServerTransportStream.SetHeader(md)
+}
+
+func (s *headerCapture) SendHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	if s.ServerTransportStream == nil {
+		return nil
+	}
+	return s.ServerTransportStream.SendHeader(md)
+}
+
+// requestFingerprint хэширует тело запроса и If-Match: повтор с тем же телом, но другой ожидаемой версией
+// заказа — это другой запрос
+func requestFingerprint(ctx context.Context, req interface{}) (string, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return "", fmt.Errorf("request of type %T is not a proto message", req)
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	hash.Write(data)
+	for _, value := range metadata.ValueFromIncomingContext(ctx, ifMatchHeader) {
+		hash.Write([]byte{0})
+		hash.Write([]byte(value))
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func marshalResponse(resp interface{}) ([]byte, error) {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("response of type %T is not a proto message", resp)
+	}
+	packed, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(packed)
+}
+
+func replay(ctx context.Context, record *domain.IdempotencyRecord, fingerprint string) (interface{}, error) {
+	if record.Fingerprint != fingerprint {
+		return nil, status.Errorf(codes.FailedPrecondition, "idempotency key %q was already used with a different request", record.Key)
+	}
+	if !record.Completed() {
+		return nil, status.Errorf(codes.Aborted, "request with idempotency key %q is still in progress", record.Key)
+	}
+
+	if len(record.ResponseHeaders) > 0 {
+		var header metadata.MD
+		if err := json.Unmarshal(record.ResponseHeaders, &header); err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to decode stored response headers: %v", err)
+		}
+		if err := grpc.SetHeader(ctx, header); err != nil {
+			log.Printf("Failed to replay response headers for idempotency key %s: %v", record.Key, err)
+		}
+	}
+
+	code := codes.Code(record.StatusCode.Int32)
+	if code != codes.OK {
+		return nil, status.Error(code, record.StatusMessage)
+	}
+
+	var packed anypb.Any
+	if err := proto.Unmarshal(record.Response, &packed); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to decode stored response: %v", err)
+	}
+	resp, err := packed.UnmarshalNew()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to decode stored response: %v", err)
+	}
+	return resp, nil
+}
+
+// isRetryable сообщает, что ошибка временная и ключ стоит освободить для повтора
+func isRetryable(code codes.Code) bool {
+	switch code {
+	case codes.Internal, codes.Unavailable, codes.DeadlineExceeded, codes.Aborted,
+		codes.Canceled, codes.Unknown, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,217 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]domain.IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]domain.IdempotencyRecord)}
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[record.Method+record.Key]; ok {
+		return &existing, nil
+	}
+	s.records[record.Method+record.Key] = *record
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[record.Method+record.Key]; ok && existing.Token == record.Token {
+		s.records[record.Method+record.Key] = *record
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, record *domain.IdempotencyRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[record.Method+record.Key]; ok && existing.Token == record.Token {
+		delete(s.records, record.Method+record.Key)
+	}
+	return nil
+}
+
+func withIdempotencyKey(key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(IdempotencyKeyHeader, key))
+}
+
+func TestIdempotencyInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: order_service.OrderService_AddOrder_FullMethodName}
+	req := &order_service.AddOrderRequest{OrderId: "order1", RecipientId: "recipient1"}
+
+	t.Run("Identical retry returns original result", func(t *testing.T) {
+		interceptor := IdempotencyInterceptor(newMemoryIdempotencyStore(), info.FullMethod)
+		calls := 0
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			calls++
+			if calls > 1 {
+				return nil, status.Error(codes.AlreadyExists, "order already exists")
+			}
+			return &emptypb.Empty{}, nil
+		}
+
+		for i := 0; i < 2; i++ {
+			resp, err := interceptor(withIdempotencyKey("key1"), req, info, handler)
+			require.NoError(t, err)
+			assert.IsType(t, &emptypb.Empty{}, resp)
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Original error is replayed", func(t *testing.T) {
+		interceptor := IdempotencyInterceptor(newMemoryIdempotencyStore(), info.FullMethod)
+		calls := 0
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			calls++
+			return nil, status.Error(codes.NotFound, "order not found")
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err := interceptor(withIdempotencyKey("key1"), req, info, handler)
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Conflicting reuse is rejected", func(t *testing.T) {
+		interceptor := IdempotencyInterceptor(newMemoryIdempotencyStore(), info.FullMethod)
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &emptypb.Empty{}, nil
+		}
+
+		_, err := interceptor(withIdempotencyKey("key1"), req, info, handler)
+		require.NoError(t, err)
+
+		other := &order_service.AddOrderRequest{OrderId: "order2", RecipientId: "recipient1"}
+		_, err = interceptor(withIdempotencyKey("key1"), other, info, handler)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("Reuse with another If-Match is rejected", func(t *testing.T) {
+		interceptor := IdempotencyInterceptor(newMemoryIdempotencyStore(), info.FullMethod)
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &emptypb.Empty{}, nil
+		}
+		withIfMatch := func(etag string) context.Context {
+			return metadata.NewIncomingContext(context.Background(), metadata.Pairs(IdempotencyKeyHeader, "key1", ifMatchHeader, etag))
+		}
+
+		_, err := interceptor(withIfMatch(`"1"`), req, info, handler)
+		require.NoError(t, err)
+
+		_, err = interceptor(withIfMatch(`"1"`), req, info, handler)
+		require.NoError(t, err)
+
+		_, err = interceptor(withIfMatch(`"2"`), req, info, handler)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("Response headers are replayed", func(t *testing.T) {
+		interceptor := IdempotencyInterceptor(newMemoryIdempotencyStore(), info.FullMethod)
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			if err := grpc.SetHeader(ctx, metadata.Pairs("etag", `"2"`, ReadPrimaryUntilHeader, "2026-10-19T12:00:00Z")); err != nil {
+				return nil, err
+			}
+			return &emptypb.Empty{}, nil
+		}
+
+		for i := 0; i < 2; i++ {
+			stream := &headerRecorder{}
+			_, err := interceptor(grpc.NewContextWithServerTransportStream(withIdempotencyKey("key1"), stream), req, info, handler)
+			require.NoError(t, err)
+			assert.Equal(t, []string{`"2"`}, stream.header.Get("etag"))
+			assert.Equal(t, []string{"2026-10-19T12:00:00Z"}, stream.header.Get(ReadPrimaryUntilHeader))
+		}
+	})
+
+	t.Run("Retryable error releases key", func(t *testing.T) {
+		interceptor := IdempotencyInterceptor(newMemoryIdempotencyStore(), info.FullMethod)
+		calls := 0
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			calls++
+			if calls == 1 {
+				return nil, status.Error(codes.Unavailable, "db is down")
+			}
+			return &emptypb.Empty{}, nil
+		}
+
+		_, err := interceptor(withIdempotencyKey("key1"), req, info, handler)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+
+		_, err = interceptor(withIdempotencyKey("key1"), req, info, handler)
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Result is stored after client cancels", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		interceptor := IdempotencyInterceptor(store, info.FullMethod)
+		ctx, cancel := context.WithCancel(withIdempotencyKey("key1"))
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			cancel()
+			return &emptypb.Empty{}, nil
+		}
+
+		_, err := interceptor(ctx, req, info, handler)
+		require.NoError(t, err)
+
+		stored := store.records[info.FullMethod+"key1"]
+		assert.True(t, stored.Completed())
+		assert.Equal(t, int32(codes.OK), stored.StatusCode.Int32)
+	})
+
+	t.Run("Key is released after client cancels", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		interceptor := IdempotencyInterceptor(store, info.FullMethod)
+		ctx, cancel := context.WithCancel(withIdempotencyKey("key1"))
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			cancel()
+			return nil, status.Error(codes.Canceled, "context canceled")
+		}
+
+		_, err := interceptor(ctx, req, info, handler)
+		assert.Equal(t, codes.Canceled, status.Code(err))
+		assert.Empty(t, store.records)
+	})
+
+	t.Run("Requests without key are not tracked", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		interceptor := IdempotencyInterceptor(store, info.FullMethod)
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &emptypb.Empty{}, nil
+		}
+
+		_, err := interceptor(context.Background(), req, info, handler)
+		require.NoError(t, err)
+		assert.Empty(t, store.records)
+	})
+}
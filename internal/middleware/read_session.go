@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ReadPrimaryUntilHeader — метаданные с моментом в формате RFC 3339, до которого запросы клиента читают
+// с основной базы. Сервер отдаёт их после записи, а клиент передаёт в следующих запросах, чтобы увидеть свою запись
+const ReadPrimaryUntilHeader = "x-read-primary-until"
+
+// ReadSessionInterceptor кладёт в ctx запроса domain.ReadSession, чтобы чтения после записи шли на основную базу.
+// Срок из заголовка x-read-primary-until продлевает это на следующие запросы клиента, а если запрос что-то
+// записал, новый срок возвращается в том же заголовке ответа. Срок из заголовка не может быть дальше
+// stickyWindow от текущего момента, иначе клиент навсегда закрепил бы свои чтения за основной базой
+func ReadSessionInterceptor(stickyWindow time.Duration) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		session := &domain.ReadSession{}
+		if values := metadata.ValueFromIncomingContext(ctx, ReadPrimaryUntilHeader); len(values) > 0 {
+			if until, err := time.Parse(time.RFC3339Nano, values[0]); err == nil {
+				now := time.Now()
+				if limit := now.Add(stickyWindow); until.After(limit) {
+					until = limit
+				}
+				if until.After(now) {
+					session.StickToPrimary(until)
+				}
+			}
+		}
+		before := session.PrimaryUntil()
+
+		resp, err := handler(domain.WithReadSession(ctx, session), req)
+
+		if until := session.PrimaryUntil(); until.After(before) {
+			header := metadata.Pairs(ReadPrimaryUntilHeader, until.UTC().Format(time.RFC3339Nano))
+			if err := grpc.SetHeader(ctx, header); err != nil {
+				log.Printf("Failed to set %s header: %v", ReadPrimaryUntilHeader, err)
+			}
+		}
+		return resp, err
+	}
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// headerRecorder запоминает метаданные, которые обработчик отдаёт через grpc.SetHeader
+type headerRecorder struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *headerRecorder) Method() string { return "/test" }
+
+func (s *headerRecorder) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestReadSessionInterceptor(t *testing.T) {
+	interceptor := ReadSessionInterceptor(time.Minute)
+	info := &grpc.UnaryServerInfo{FullMethod: "/test"}
+	until := time.Now().Add(time.Minute).UTC()
+
+	// Запрос без записи не отдаёт заголовок, но учитывает срок из запроса
+	stream := &headerRecorder{}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ReadPrimaryUntilHeader, until.Format(time.RFC3339Nano)))
+	_, err := interceptor(grpc.NewContextWithServerTransportStream(ctx, stream), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		session := domain.ReadSessionFrom(ctx)
+		require.NotNil(t, session)
+		assert.True(t, session.ReadFromPrimary(time.Now()))
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, stream.header.Get(ReadPrimaryUntilHeader))
+
+	// Запись продлевает срок и возвращает его клиенту
+	stream = &headerRecorder{}
+	_, err = interceptor(grpc.NewContextWithServerTransportStream(context.Background(), stream), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		domain.ReadSessionFrom(ctx).StickToPrimary(until)
+		return nil, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, stream.header.Get(ReadPrimaryUntilHeader), 1)
+	got, err := time.Parse(time.RFC3339Nano, stream.header.Get(ReadPrimaryUntilHeader)[0])
+	require.NoError(t, err)
+	assert.True(t, until.Equal(got))
+}
+
+func TestReadSessionInterceptor_BoundsClientDeadline(t *testing.T) {
+	interceptor := ReadSessionInterceptor(5 * time.Second)
+	info := &grpc.UnaryServerInfo{FullMethod: "/test"}
+
+	run := func(until time.Time) time.Time {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ReadPrimaryUntilHeader, until.Format(time.RFC3339Nano)))
+		var got time.Time
+		_, err := interceptor(grpc.NewContextWithServerTransportStream(ctx, &headerRecorder{}), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			got = domain.ReadSessionFrom(ctx).PrimaryUntil()
+			return nil, nil
+		})
+		require.NoError(t, err)
+		return got
+	}
+
+	// Срок из далёкого будущего обрезается до окна закрепления
+	before := time.Now()
+	got := run(before.Add(24 * time.Hour))
+	assert.False(t, got.After(time.Now().Add(5*time.Second)))
+	assert.True(t, got.After(before))
+
+	// Уже прошедший срок игнорируется
+	assert.True(t, run(time.Now().Add(-time.Minute)).IsZero())
+}
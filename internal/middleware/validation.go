@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bufbuild/protovalidate-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ValidationInterceptor проверяет входящие сообщения по правилам buf.validate из proto-файлов
+// и отклоняет невалидные запросы с кодом InvalidArgument и списком нарушений в BadRequest
+func ValidationInterceptor(validator *protovalidate.Validator) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		if err := validator.Validate(msg); err != nil {
+			return nil, validationStatus(err)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func validationStatus(err error) error {
+	var valErr *protovalidate.ValidationError
+	if !errors.As(err, &valErr) {
+		return status.Errorf(codes.Internal, "Failed to validate request: %v", err)
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range valErr.ToProto().GetViolations() {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.GetFieldPath(),
+			Description: violation.GetMessage(),
+		})
+	}
+
+	st := status.New(codes.InvalidArgument, valErr.Error())
+	if detailed, detailsErr := st.WithDetails(badRequest); detailsErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
+
+	"github.com/bufbuild/protovalidate-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestValidationInterceptor(t *testing.T) {
+	validator, err := protovalidate.New()
+	require.NoError(t, err)
+
+	interceptor := ValidationInterceptor(validator)
+	info := &grpc.UnaryServerInfo{FullMethod: "test"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &emptypb.Empty{}, nil
+	}
+
+	t.Run("Valid request passes", func(t *testing.T) {
+		req := &order_service.DeliverOrdersRequest{RecipientId: "recipient1", OrderIds: []string{"order1"}}
+		_, err := interceptor(context.Background(), req, info, handler)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Empty order IDs are rejected", func(t *testing.T) {
+		req := &order_service.DeliverOrdersRequest{RecipientId: "recipient1"}
+		_, err := interceptor(context.Background(), req, info, handler)
+		assertFieldViolation(t, err, "order_ids")
+	})
+
+	t.Run("Negative page size is rejected", func(t *testing.T) {
+		req := &order_service.GetReturnsRequest{PageSize: -5}
+		_, err := interceptor(context.Background(), req, info, handler)
+		assertFieldViolation(t, err, "page_size")
+	})
+
+	t.Run("Weight out of bounds is rejected", func(t *testing.T) {
+		req := &order_service.AddOrderRequest{
+			OrderId:       "order1",
+			RecipientId:   "recipient1",
+			ExpiryDate:    "2030-01-01",
+			Weight:        0,
+			PackagingType: "box",
+		}
+		_, err := interceptor(context.Background(), req, info, handler)
+		assertFieldViolation(t, err, "weight")
+	})
+}
+
+func assertFieldViolation(t *testing.T, err error, field string) {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	var fields []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+	assert.Contains(t, fields, field)
+}
@@ -0,0 +1,110 @@
+// Package migrator применяет SQL-миграции в формате goose без внешней утилиты.
+// Версии хранятся в той же таблице goose_db_version, поэтому базы, размеченные goose, продолжают работать
+package migrator
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	upMarker   = "-- +goose Up"
+	downMarker = "-- +goose Down"
+)
+
+// Migration — одна миграция: версия из префикса имени файла и SQL для применения и отката
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load читает из fsys файлы *.sql вида <версия>_<название>.sql и возвращает их по возрастанию версий
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(names))
+	for _, name := range names {
+		migration, err := loadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", name, err)
+		}
+		migrations = append(migrations, migration)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", migrations[i].Version, migrations[i-1].Name, migrations[i].Name)
+		}
+	}
+	return migrations, nil
+}
+
+func loadFile(fsys fs.FS, name string) (Migration, error) {
+	base := strings.TrimSuffix(path.Base(name), ".sql")
+	prefix, _, ok := strings.Cut(base, "_")
+	if !ok {
+		return Migration{}, fmt.Errorf("file name must look like <version>_<name>.sql")
+	}
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil || version <= 0 {
+		return Migration{}, fmt.Errorf("invalid version %q", prefix)
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return Migration{}, err
+	}
+	up, down, err := splitSections(string(data))
+	if err != nil {
+		return Migration{}, err
+	}
+	return Migration{Version: version, Name: base, Up: up, Down: down}, nil
+}
+
+// splitSections делит файл на части после маркеров Up и Down. Строки до первого маркера не выполняются
+func splitSections(content string) (up, down string, err error) {
+	var (
+		section *strings.Builder
+		upSQL   strings.Builder
+		downSQL strings.Builder
+		seenUp  bool
+	)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch strings.TrimSpace(line) {
+		case upMarker:
+			section, seenUp = &upSQL, true
+			continue
+		case downMarker:
+			section = &downSQL
+			continue
+		}
+		if section != nil {
+			section.WriteString(line)
+			section.WriteByte('\n')
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", err
+	}
+	if !seenUp {
+		return "", "", fmt.Errorf("missing %q section", upMarker)
+	}
+	return strings.TrimSpace(upSQL.String()), strings.TrimSpace(downSQL.String()), nil
+}
@@ -0,0 +1,80 @@
+package migrator
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20240102000000_second.sql": {Data: []byte("-- +goose Up\nALTER TABLE t ADD COLUMN b INT;\n\n-- +goose Down\nALTER TABLE t DROP COLUMN b;\n")},
+		"20240101000000_first.sql":  {Data: []byte("-- комментарий до маркера\n-- +goose Up\nCREATE TABLE t (a INT);\n")},
+		"README.md":                 {Data: []byte("not a migration")},
+	}
+
+	migrations, err := Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+
+	assert.Equal(t, Migration{Version: 20240101000000, Name: "20240101000000_first", Up: "CREATE TABLE t (a INT);"}, migrations[0])
+	assert.Equal(t, Migration{
+		Version: 20240102000000,
+		Name:    "20240102000000_second",
+		Up:      "ALTER TABLE t ADD COLUMN b INT;",
+		Down:    "ALTER TABLE t DROP COLUMN b;",
+	}, migrations[1])
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		err  string
+	}{
+		{
+			name: "no version",
+			fsys: fstest.MapFS{"create.sql": {Data: []byte("-- +goose Up\nSELECT 1;")}},
+			err:  "file name must look like",
+		},
+		{
+			name: "invalid version",
+			fsys: fstest.MapFS{"v1_create.sql": {Data: []byte("-- +goose Up\nSELECT 1;")}},
+			err:  `invalid version "v1"`,
+		},
+		{
+			name: "missing up",
+			fsys: fstest.MapFS{"1_create.sql": {Data: []byte("SELECT 1;")}},
+			err:  "missing",
+		},
+		{
+			name: "duplicate version",
+			fsys: fstest.MapFS{
+				"1_a.sql": {Data: []byte("-- +goose Up\nSELECT 1;")},
+				"1_b.sql": {Data: []byte("-- +goose Up\nSELECT 1;")},
+			},
+			err: "duplicate migration version 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.fsys)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	loaded, err := Load(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+
+	for _, migration := range loaded {
+		assert.NotEmpty(t, migration.Up, migration.Name)
+		assert.NotEmpty(t, migration.Down, migration.Name)
+	}
+}
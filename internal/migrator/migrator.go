@@ -0,0 +1,230 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+)
+
+// lockID — ключ advisory lock, под которым реплики применяют миграции по одной
+const lockID int64 = 0x70767a5f6d6967 // "pvz_mig"
+
+// ErrSchemaMismatch возвращается, если версия схемы в базе не совпадает с последней миграцией в коде
+var ErrSchemaMismatch = errors.New("database schema version does not match the code")
+
+// Status — состояние миграции в базе
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator применяет и откатывает миграции в postgres
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest возвращает версию последней миграции, которую ожидает код
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up применяет все неприменённые миграции по возрастанию версий, каждую в своей транзакции.
+// Одновременный запуск на нескольких репликах безопасен: они ждут друг друга на advisory lock
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		states, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := states[migration.Version]; ok {
+				continue
+			}
+			if err := run(ctx, conn, migration.Up, migration.Version, true); err != nil {
+				return fmt.Errorf("failed to apply %s: %w", migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down откатывает последнюю применённую миграцию. Если применённых нет, возвращает nil
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	var rolledBack *Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		states, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := states[migration.Version]; !ok {
+				continue
+			}
+			if err := run(ctx, conn, migration.Down, migration.Version, false); err != nil {
+				return fmt.Errorf("failed to roll back %s: %w", migration.Name, err)
+			}
+			rolledBack = &migration
+			return nil
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Status возвращает состояние всех миграций из кода
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	states, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, ok := states[migration.Version]
+		statuses = append(statuses, Status{Migration: migration, Applied: ok, AppliedAt: appliedAt})
+	}
+	return statuses, nil
+}
+
+// CheckVersion проверяет, что в базе применены все миграции кода и нет миграций новее
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	states, err := m.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, migration := range m.migrations {
+		if _, ok := states[migration.Version]; !ok {
+			return fmt.Errorf("%w: migration %s is not applied", ErrSchemaMismatch, migration.Name)
+		}
+	}
+	for version := range states {
+		if version > m.Latest() {
+			return fmt.Errorf("%w: database has migration %d, code expects at most %d", ErrSchemaMismatch, version, m.Latest())
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int64]time.Time, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	return appliedVersions(ctx, conn)
+}
+
+// withLock выполняет fn на отдельном соединении, удерживая на нём advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// Блокировка снимается и при закрытии соединения, поэтому ошибку отпускания можно не возвращать
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID)
+	}()
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func ensureVersionTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+        CREATE TABLE IF NOT EXISTS goose_db_version (
+            id SERIAL PRIMARY KEY,
+            version_id BIGINT NOT NULL,
+            is_applied BOOLEAN NOT NULL,
+            tstamp TIMESTAMP DEFAULT now()
+        )
+    `)
+	if err != nil {
+		return fmt.Errorf("failed to create version table: %w", err)
+	}
+	return nil
+}
+
+// appliedVersions возвращает применённые версии со временем применения. Как и в goose, откат
+// добавляет строку с is_applied = false, поэтому состояние версии определяет её последняя строка
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `
+        SELECT DISTINCT ON (version_id) version_id, is_applied, tstamp
+        FROM goose_db_version
+        WHERE version_id > 0
+        ORDER BY version_id, id DESC
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version   int64
+			isApplied bool
+			tstamp    sql.NullTime
+		)
+		if err := rows.Scan(&version, &isApplied, &tstamp); err != nil {
+			return nil, err
+		}
+		if isApplied {
+			applied[version] = tstamp.Time
+		}
+	}
+	return applied, rows.Err()
+}
+
+// run выполняет SQL миграции и отмечает результат в таблице версий в одной транзакции
+func run(ctx context.Context, conn *sql.Conn, query string, version int64, isApplied bool) (err error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if query != "" {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO goose_db_version (version_id, is_applied) VALUES ($1, $2)`, version, isApplied); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
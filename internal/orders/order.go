@@ -32,21 +32,28 @@ func AddOrder(order Order) {
 }
 
 func ListOrders() []Order {
-	file, err := os.ReadFile(ordersFile)
+	orders, err := ReadOrders(ordersFile)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []Order{}
-		}
 		log.Fatal(err)
 	}
+	return orders
+}
 
-	var orders []Order
-	err = json.Unmarshal(file, &orders)
+// ReadOrders читает заказы из файла в формате этого пакета. Отсутствующий файл считается пустым
+func ReadOrders(path string) ([]Order, error) {
+	file, err := os.ReadFile(path)
 	if err != nil {
-		log.Fatal(err)
+		if os.IsNotExist(err) {
+			return []Order{}, nil
+		}
+		return nil, err
 	}
 
-	return orders
+	var orders []Order
+	if err := json.Unmarshal(file, &orders); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return orders, nil
 }
 
 func saveOrders(orders []Order) {
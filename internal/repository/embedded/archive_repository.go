@@ -0,0 +1,150 @@
+package embedded
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ArchiveRepository переносит закрытые заказы в бакет архива. Ключ архива — order_id, нулевой байт
+// и время переноса, поэтому записи одного заказа лежат подряд от старых к новым. Внешних ключей
+// здесь нет, поэтому возвраты архивных заказов остаются в списке возвратов
+type ArchiveRepository struct {
+	store *Store
+}
+
+func NewArchiveRepository(store *Store) *ArchiveRepository {
+	return &ArchiveRepository{store: store}
+}
+
+// ArchiveOrders переносит в архив до limit удалённых или выданных заказов, которые не менялись
+// с closedBefore, начиная с давно изменённых
+func (r *ArchiveRepository) ArchiveOrders(ctx context.Context, closedBefore time.Time, limit int) (int, error) {
+	var archived int
+	err := r.store.update(ctx, func(tx *bolt.Tx) error {
+		var closed []*domain.Order
+		for _, name := range [][]byte{ordersBucket, removedOrdersBucket} {
+			err := tx.Bucket(name).ForEach(func(_, v []byte) error {
+				order, err := decodeOrder(v)
+				if err != nil {
+					return err
+				}
+				if order.IsClosed() && order.UpdatedAt.Before(closedBefore) {
+					closed = append(closed, order)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		slices.SortFunc(closed, func(a, b *domain.Order) int {
+			return a.UpdatedAt.Compare(b.UpdatedAt)
+		})
+		if len(closed) > limit {
+			closed = closed[:limit]
+		}
+
+		archive := tx.Bucket(ordersArchiveBucket)
+		archivedAt := timestamp()
+		for _, order := range closed {
+			if order.DeletedAt.Valid {
+				if err := tx.Bucket(removedOrdersBucket).Delete([]byte(order.OrderID)); err != nil {
+					return err
+				}
+			} else if err := unlinkOrder(tx, order); err != nil {
+				return err
+			}
+
+			data, err := json.Marshal(&domain.ArchivedOrder{Order: *order, ArchivedAt: archivedAt})
+			if err != nil {
+				return fmt.Errorf("failed to encode archived order: %w", err)
+			}
+			if err := archive.Put(archiveKey(order.OrderID, archivedAt), data); err != nil {
+				return err
+			}
+		}
+		archived = len(closed)
+		return nil
+	})
+	return archived, err
+}
+
+// GetArchivedOrder возвращает последнюю запись архива с этим order_id
+func (r *ArchiveRepository) GetArchivedOrder(ctx context.Context, orderID string) (*domain.ArchivedOrder, error) {
+	var order *domain.ArchivedOrder
+	err := r.store.view(ctx, func(tx *bolt.Tx) error {
+		prefix := append([]byte(orderID), 0)
+		c := tx.Bucket(ordersArchiveBucket).Cursor()
+		// Первый ключ за всеми записями заказа, от него шаг назад даёт последнюю запись
+		k, _ := c.Seek(append([]byte(orderID), 1))
+		if k == nil {
+			k, _ = c.Last()
+		} else {
+			k, _ = c.Prev()
+		}
+		if k == nil || !bytes.HasPrefix(k, prefix) {
+			return domain.ErrOrderNotFound
+		}
+
+		var err error
+		order, err = decodeArchivedOrder(tx.Bucket(ordersArchiveBucket).Get(k))
+		return err
+	})
+	return order, err
+}
+
+// ListArchivedOrders возвращает заказы из архива от последних принятых к первым, начиная со следующего
+// после after. Пустой recipientID означает заказы всех получателей. Архив читается перебором
+func (r *ArchiveRepository) ListArchivedOrders(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int) ([]*domain.ArchivedOrder, error) {
+	var orders []*domain.ArchivedOrder
+	err := r.store.view(ctx, func(tx *bolt.Tx) error {
+		return tx.Bucket(ordersArchiveBucket).ForEach(func(_, v []byte) error {
+			order, err := decodeArchivedOrder(v)
+			if err != nil {
+				return err
+			}
+			if (recipientID == "" || order.RecipientID == recipientID) && (after == nil || after.Precedes(&order.Order)) {
+				orders = append(orders, order)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(orders, func(a, b *domain.ArchivedOrder) int {
+		if c := b.AcceptedAt.Compare(a.AcceptedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.OrderID, a.OrderID)
+	})
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+func archiveKey(orderID string, archivedAt time.Time) []byte {
+	key := make([]byte, 0, len(orderID)+1+timeKeyLen)
+	key = append(key, orderID...)
+	key = append(key, 0)
+	return appendTimeKey(key, archivedAt)
+}
+
+func decodeArchivedOrder(data []byte) (*domain.ArchivedOrder, error) {
+	var order domain.ArchivedOrder
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, fmt.Errorf("failed to decode archived order: %w", err)
+	}
+	return &order, nil
+}
@@ -0,0 +1,105 @@
+package embedded
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+type IdempotencyRepository struct {
+	store *Store
+	ttl   time.Duration
+	lease time.Duration
+}
+
+// NewIdempotencyRepository создает хранилище ключей идемпотентности. Результат хранится ttl,
+// а незавершённое резервирование — lease (по умолчанию domain.DefaultIdempotencyLease), после чего ключ
+// можно зарезервировать заново
+func NewIdempotencyRepository(store *Store, ttl, lease time.Duration) *IdempotencyRepository {
+	if lease <= 0 {
+		lease = domain.DefaultIdempotencyLease
+	}
+	return &IdempotencyRepository{
+		store: store,
+		ttl:   ttl,
+		lease: lease,
+	}
+}
+
+// Reserve резервирует ключ за запросом. Если ключ уже занят, возвращает существующую запись
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	var existing *domain.IdempotencyRecord
+	now := timestamp()
+	err := r.store.update(ctx, func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(idempotencyBucket)
+		key := idempotencyKey(record.Key, record.Method)
+		stored, err := getRecord(bucket, key)
+		if err != nil {
+			return err
+		}
+		if stored != nil && !stored.CreatedAt.Before(now.Add(-r.ttl)) && !stored.Abandoned(now) {
+			existing = stored
+			return nil
+		}
+		record.ReservedUntil = now.Add(r.lease)
+		return putRecord(bucket, key, record)
+	})
+	return existing, err
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	return r.store.update(ctx, func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(idempotencyBucket)
+		key := idempotencyKey(record.Key, record.Method)
+		stored, err := getRecord(bucket, key)
+		if err != nil || stored == nil || stored.Token != record.Token {
+			return err
+		}
+		stored.StatusCode = record.StatusCode
+		stored.StatusMessage = record.StatusMessage
+		stored.Response = record.Response
+		stored.ResponseHeaders = record.ResponseHeaders
+		return putRecord(bucket, key, stored)
+	})
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, record *domain.IdempotencyRecord) error {
+	return r.store.update(ctx, func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(idempotencyBucket)
+		key := idempotencyKey(record.Key, record.Method)
+		stored, err := getRecord(bucket, key)
+		if err != nil || stored == nil || stored.Token != record.Token {
+			return err
+		}
+		return bucket.Delete(key)
+	})
+}
+
+func idempotencyKey(key, method string) []byte {
+	return append(append([]byte(method), 0), key...)
+}
+
+func getRecord(bucket *bolt.Bucket, key []byte) (*domain.IdempotencyRecord, error) {
+	data := bucket.Get(key)
+	if data == nil {
+		return nil, nil
+	}
+	var record domain.IdempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode idempotency record: %w", err)
+	}
+	return &record, nil
+}
+
+func putRecord(bucket *bolt.Bucket, key []byte, record *domain.IdempotencyRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotency record: %w", err)
+	}
+	return bucket.Put(key, data)
+}
@@ -0,0 +1,320 @@
+package embedded
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// OrderRepository хранит заказы по order_id и индекс по получателю.
+// Ключ индекса — recipient_id, нулевой байт, accepted_at и order_id, поэтому заказы получателя
+// лежат подряд в порядке приёма. Удалённые заказы до переноса в архив лежат в отдельном бакете
+// и не попадают в индекс
+type OrderRepository struct {
+	store *Store
+}
+
+func NewOrderRepository(store *Store) *OrderRepository {
+	return &OrderRepository{store: store}
+}
+
+func (r *OrderRepository) AddOrder(ctx context.Context, order *domain.Order) error {
+	return r.store.update(ctx, func(tx *bolt.Tx) error {
+		orders := tx.Bucket(ordersBucket)
+		if orderExists(tx, order.OrderID) {
+			return domain.ErrOrderAlreadyExists
+		}
+		order.UpdatedAt = timestamp()
+		if err := putOrder(orders, order); err != nil {
+			return err
+		}
+		return tx.Bucket(ordersByRecipientBucket).Put(recipientKey(order.RecipientID, order.AcceptedAt, order.OrderID), nil)
+	})
+}
+
+func (r *OrderRepository) GetOrder(ctx context.Context, orderID string) (*domain.Order, error) {
+	var order *domain.Order
+	err := r.store.view(ctx, func(tx *bolt.Tx) error {
+		var err error
+		order, err = getOrder(tx.Bucket(ordersBucket), orderID)
+		return err
+	})
+	return order, err
+}
+
+// UpdateOrder меняет статус и даты выдачи и возврата и увеличивает order.Version. Как и в postgres,
+// если заказ изменили или удалили после чтения, возвращает domain.ErrOrderVersionConflict.
+// Получатель и дата приёма не меняются, поэтому индекс остаётся прежним
+func (r *OrderRepository) UpdateOrder(ctx context.Context, order *domain.Order) error {
+	now := timestamp()
+	err := r.store.update(ctx, func(tx *bolt.Tx) error {
+		orders := tx.Bucket(ordersBucket)
+		stored, err := getOrder(orders, order.OrderID)
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return domain.ErrOrderVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+		if stored.Version != order.Version {
+			return domain.ErrOrderVersionConflict
+		}
+		stored.Status = order.Status
+		stored.DeliveryDate = order.DeliveryDate
+		stored.ReturnDate = order.ReturnDate
+		stored.Version++
+		stored.UpdatedAt = now
+		return putOrder(orders, stored)
+	})
+	if err != nil {
+		return err
+	}
+	order.Version++
+	order.UpdatedAt = now
+	return nil
+}
+
+// DeleteOrder помечает заказ удалённым с причиной reason и переносит его в бакет удалённых заказов
+func (r *OrderRepository) DeleteOrder(ctx context.Context, orderID, reason string) error {
+	return r.store.update(ctx, func(tx *bolt.Tx) error {
+		orders := tx.Bucket(ordersBucket)
+		stored, err := getOrder(orders, orderID)
+		if err != nil {
+			return err
+		}
+		if err := unlinkOrder(tx, stored); err != nil {
+			return err
+		}
+		stored.DeletedAt = sql.NullTime{Time: timestamp(), Valid: true}
+		stored.RemovalReason = reason
+		stored.UpdatedAt = stored.DeletedAt.Time
+		stored.Version++
+		return putOrder(tx.Bucket(removedOrdersBucket), stored)
+	})
+}
+
+// unlinkOrder убирает действующий заказ из бакета заказов и индекса получателя
+func unlinkOrder(tx *bolt.Tx, order *domain.Order) error {
+	if err := tx.Bucket(ordersBucket).Delete([]byte(order.OrderID)); err != nil {
+		return err
+	}
+	return tx.Bucket(ordersByRecipientBucket).Delete(recipientKey(order.RecipientID, order.AcceptedAt, order.OrderID))
+}
+
+// orderExists сообщает, что order_id занят действующим или удалённым заказом
+func orderExists(tx *bolt.Tx, orderID string) bool {
+	return tx.Bucket(ordersBucket).Get([]byte(orderID)) != nil || tx.Bucket(removedOrdersBucket).Get([]byte(orderID)) != nil
+}
+
+// AddOrders добавляет все заказы или ни одного, если хотя бы один уже есть
+func (r *OrderRepository) AddOrders(ctx context.Context, orders []*domain.Order) error {
+	return r.store.update(ctx, func(tx *bolt.Tx) error {
+		ordersBkt := tx.Bucket(ordersBucket)
+		index := tx.Bucket(ordersByRecipientBucket)
+		// Условия проверяются до записи, чтобы не оставить части пачки во внешней транзакции
+		seen := make(map[string]bool, len(orders))
+		for _, order := range orders {
+			if orderExists(tx, order.OrderID) || seen[order.OrderID] {
+				return fmt.Errorf("%w: %s", domain.ErrOrderAlreadyExists, order.OrderID)
+			}
+			seen[order.OrderID] = true
+		}
+		now := timestamp()
+		for _, order := range orders {
+			order.UpdatedAt = now
+			if err := putOrder(ordersBkt, order); err != nil {
+				return err
+			}
+			if err := index.Put(recipientKey(order.RecipientID, order.AcceptedAt, order.OrderID), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetOrdersByIDs возвращает найденные заказы в порядке orderIDs, отсутствующие пропускаются
+func (r *OrderRepository) GetOrdersByIDs(ctx context.Context, orderIDs []string) ([]*domain.Order, error) {
+	var orders []*domain.Order
+	err := r.store.view(ctx, func(tx *bolt.Tx) error {
+		ordersBkt := tx.Bucket(ordersBucket)
+		for _, orderID := range orderIDs {
+			order, err := getOrder(ordersBkt, orderID)
+			if errors.Is(err, domain.ErrOrderNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			orders = append(orders, order)
+		}
+		return nil
+	})
+	return orders, err
+}
+
+// UpdateOrders обновляет заказы как UpdateOrder, но если хотя бы один изменили или удалили после чтения,
+// не меняет ни один
+func (r *OrderRepository) UpdateOrders(ctx context.Context, orders []*domain.Order) error {
+	now := timestamp()
+	err := r.store.update(ctx, func(tx *bolt.Tx) error {
+		ordersBkt := tx.Bucket(ordersBucket)
+		stored := make([]*domain.Order, len(orders))
+		for i, order := range orders {
+			var err error
+			stored[i], err = getOrder(ordersBkt, order.OrderID)
+			if errors.Is(err, domain.ErrOrderNotFound) {
+				return domain.ErrOrderVersionConflict
+			}
+			if err != nil {
+				return err
+			}
+			if stored[i].Version != order.Version {
+				return domain.ErrOrderVersionConflict
+			}
+		}
+		for i, order := range orders {
+			stored := stored[i]
+			stored.Status = order.Status
+			stored.DeliveryDate = order.DeliveryDate
+			stored.ReturnDate = order.ReturnDate
+			stored.Version++
+			stored.UpdatedAt = now
+			if err := putOrder(ordersBkt, stored); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, order := range orders {
+		order.Version++
+		order.UpdatedAt = now
+	}
+	return nil
+}
+
+// ListOrdersByRecipient возвращает заказы получателя от последних принятых к первым,
+// начиная со следующего после after. Если after равен nil, выборка идёт с начала
+func (r *OrderRepository) ListOrdersByRecipient(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int) ([]*domain.Order, error) {
+	return r.ListOrders(ctx, domain.OrderFilter{RecipientID: recipientID}, after, limit)
+}
+
+// ListOrders возвращает заказы, подходящие под filter, в том же порядке, что и ListOrdersByRecipient.
+// С получателем в фильтре заказы читаются по индексу, без него — перебором всех заказов
+func (r *OrderRepository) ListOrders(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) ([]*domain.Order, error) {
+	var orders []*domain.Order
+	err := r.store.view(ctx, func(tx *bolt.Tx) error {
+		var err error
+		if filter.RecipientID != "" {
+			orders, err = listByRecipient(tx, filter, after, limit)
+		} else {
+			orders, err = listAll(tx, filter, after, limit)
+		}
+		return err
+	})
+	return orders, err
+}
+
+// listByRecipient идёт по индексу получателя назад от ключа курсора
+func listByRecipient(tx *bolt.Tx, filter domain.OrderFilter, after *domain.OrderCursor, limit int) ([]*domain.Order, error) {
+	prefix := append([]byte(filter.RecipientID), 0)
+	// Граница выборки: ключ курсора или первый ключ за всеми заказами получателя
+	bound := append([]byte(filter.RecipientID), 1)
+	if after != nil {
+		bound = recipientKey(filter.RecipientID, after.AcceptedAt, after.OrderID)
+	}
+
+	ordersBkt := tx.Bucket(ordersBucket)
+	c := tx.Bucket(ordersByRecipientBucket).Cursor()
+	k, _ := c.Seek(bound)
+	if k == nil {
+		k, _ = c.Last()
+	} else {
+		k, _ = c.Prev()
+	}
+
+	var orders []*domain.Order
+	for ; k != nil && bytes.HasPrefix(k, prefix) && len(orders) < limit; k, _ = c.Prev() {
+		order, err := getOrder(ordersBkt, string(k[len(prefix)+timeKeyLen:]))
+		if err != nil {
+			return nil, fmt.Errorf("broken recipient index entry %q: %w", k, err)
+		}
+		if filter.Matches(order) {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func listAll(tx *bolt.Tx, filter domain.OrderFilter, after *domain.OrderCursor, limit int) ([]*domain.Order, error) {
+	var orders []*domain.Order
+	err := tx.Bucket(ordersBucket).ForEach(func(_, v []byte) error {
+		order, err := decodeOrder(v)
+		if err != nil {
+			return err
+		}
+		if filter.Matches(order) && (after == nil || after.Precedes(order)) {
+			orders = append(orders, order)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(orders, func(a, b *domain.Order) int {
+		if c := b.AcceptedAt.Compare(a.AcceptedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.OrderID, a.OrderID)
+	})
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+func recipientKey(recipientID string, acceptedAt time.Time, orderID string) []byte {
+	key := make([]byte, 0, len(recipientID)+1+timeKeyLen+len(orderID))
+	key = append(key, recipientID...)
+	key = append(key, 0)
+	key = appendTimeKey(key, acceptedAt)
+	return append(key, orderID...)
+}
+
+func getOrder(orders *bolt.Bucket, orderID string) (*domain.Order, error) {
+	data := orders.Get([]byte(orderID))
+	if data == nil {
+		return nil, domain.ErrOrderNotFound
+	}
+	return decodeOrder(data)
+}
+
+func putOrder(orders *bolt.Bucket, order *domain.Order) error {
+	data, err := json.Marshal(order)
+	if err != nil {
+		return fmt.Errorf("failed to encode order: %w", err)
+	}
+	return orders.Put([]byte(order.OrderID), data)
+}
+
+func decodeOrder(data []byte) (*domain.Order, error) {
+	var order domain.Order
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, fmt.Errorf("failed to decode order: %w", err)
+	}
+	return &order, nil
+}
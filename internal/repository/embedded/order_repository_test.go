@@ -0,0 +1,33 @@
+package embedded
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/repotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_SurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pvz.db")
+	ctx := context.Background()
+
+	store, err := Open(path, time.Second)
+	require.NoError(t, err)
+	order := repotest.NewOrder("order1", "recipient1", domain.OrderStatusNew, repotest.AcceptedAt)
+	require.NoError(t, NewOrderRepository(store).AddOrder(ctx, order))
+	require.NoError(t, store.Close())
+
+	store, err = Open(path, time.Second)
+	require.NoError(t, err)
+	defer store.Close()
+
+	got, err := NewOrderRepository(store).GetOrder(ctx, "order1")
+	require.NoError(t, err)
+	assert.Equal(t, order, got)
+}
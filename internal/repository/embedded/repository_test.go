@@ -0,0 +1,32 @@
+package embedded
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/repotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "pvz.db"), time.Second)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestRepositoryContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Backend {
+		store := newTestStore(t)
+		return repotest.Backend{
+			Orders:      NewOrderRepository(store),
+			Returns:     NewReturnRepository(store),
+			Archive:     NewArchiveRepository(store),
+			TxManager:   NewTxManager(store),
+			ErrReadOnly: ErrReadOnlyTransaction,
+		}
+	})
+}
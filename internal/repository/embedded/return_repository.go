@@ -0,0 +1,83 @@
+package embedded
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ReturnRepository хранит возвраты по идентификатору и индекс по дате возврата
+type ReturnRepository struct {
+	store *Store
+}
+
+func NewReturnRepository(store *Store) *ReturnRepository {
+	return &ReturnRepository{store: store}
+}
+
+// AddReturn сохраняет возврат с очередным идентификатором, как последовательность в postgres
+func (r *ReturnRepository) AddReturn(ctx context.Context, ret *domain.Return) error {
+	return r.store.update(ctx, func(tx *bolt.Tx) error {
+		returns := tx.Bucket(returnsBucket)
+		id, err := returns.NextSequence()
+		if err != nil {
+			return err
+		}
+		stored := *ret
+		stored.ID = int(id)
+		data, err := json.Marshal(&stored)
+		if err != nil {
+			return fmt.Errorf("failed to encode return: %w", err)
+		}
+		if err := returns.Put(itob(id), data); err != nil {
+			return err
+		}
+		return tx.Bucket(returnsByDateBucket).Put(returnDateKey(&stored), nil)
+	})
+}
+
+// ListReturns возвращает возвраты от последних к первым, начиная со следующего после after.
+// Если after равен nil, выборка идёт с начала
+func (r *ReturnRepository) ListReturns(ctx context.Context, after *domain.ReturnCursor, limit int) ([]*domain.Return, error) {
+	var returns []*domain.Return
+	err := r.store.view(ctx, func(tx *bolt.Tx) error {
+		returnsBkt := tx.Bucket(returnsBucket)
+		c := tx.Bucket(returnsByDateBucket).Cursor()
+
+		var k []byte
+		if after == nil {
+			k, _ = c.Last()
+		} else if k, _ = c.Seek(returnDateKey(&domain.Return{ID: after.ID, ReturnDate: after.ReturnDate})); k == nil {
+			k, _ = c.Last()
+		} else {
+			k, _ = c.Prev()
+		}
+
+		for ; k != nil && len(returns) < limit; k, _ = c.Prev() {
+			id := k[timeKeyLen:]
+			data := returnsBkt.Get(id)
+			if data == nil {
+				return fmt.Errorf("broken return date index entry %x", k)
+			}
+			var ret domain.Return
+			if err := json.Unmarshal(data, &ret); err != nil {
+				return fmt.Errorf("failed to decode return: %w", err)
+			}
+			returns = append(returns, &ret)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return returns, nil
+}
+
+func returnDateKey(ret *domain.Return) []byte {
+	key := appendTimeKey(make([]byte, 0, timeKeyLen+8), ret.ReturnDate)
+	return append(key, itob(uint64(ret.ID))...)
+}
@@ -0,0 +1,178 @@
+// Package embedded хранит заказы, возвраты и ключи идемпотентности в одном файле на диске (bbolt).
+// Режим для небольших ПВЗ без postgres: каждая запись фиксируется атомарно и переживает падение процесса,
+// а файл блокируется, чтобы с ним не работали два сервера сразу. Заменяет JSON-файлы из internal/orders
+// и internal/returns, которые перезаписывались целиком и без блокировок
+package embedded
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrReadOnlyTransaction возвращается при попытке записи в транзакции только для чтения
+var ErrReadOnlyTransaction = errors.New("cannot write in a read-only transaction")
+
+var (
+	ordersBucket            = []byte("orders")
+	ordersByRecipientBucket = []byte("orders_by_recipient")
+	removedOrdersBucket     = []byte("removed_orders")
+	ordersArchiveBucket     = []byte("orders_archive")
+	returnsBucket           = []byte("returns")
+	returnsByDateBucket     = []byte("returns_by_date")
+	idempotencyBucket       = []byte("idempotency")
+)
+
+type txKey struct{}
+
+// Store — файл хранилища, общий для репозиториев.
+//
+// Пишущие транзакции выполняются по одной и видят данные на момент начала, поэтому они сериализуемы.
+// Читающие транзакции не блокируют запись и работают со снимком данных
+type Store struct {
+	db *bolt.DB
+}
+
+// Open открывает файл хранилища и создаёт его, если файла нет. Пока файл открыт другим процессом,
+// Open ждёт не дольше lockTimeout, а при нулевом lockTimeout — без ограничения
+func Open(path string, lockTimeout time.Duration) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: lockTimeout})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, fmt.Errorf("storage file %s is locked by another process: %w", path, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage file: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{ordersBucket, ordersByRecipientBucket, removedOrdersBucket, ordersArchiveBucket, returnsBucket, returnsByDateBucket, idempotencyBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create buckets: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close закрывает файл и снимает с него блокировку
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// view выполняет fn в транзакции из ctx или в отдельной читающей транзакции
+func (s *Store) view(ctx context.Context, fn func(tx *bolt.Tx) error) error {
+	if tx := s.txFrom(ctx); tx != nil {
+		return fn(tx)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.db.View(fn)
+}
+
+// update выполняет fn в транзакции из ctx, а без неё — как отдельную транзакцию из одной операции
+func (s *Store) update(ctx context.Context, fn func(tx *bolt.Tx) error) error {
+	if tx := s.txFrom(ctx); tx != nil {
+		if !tx.Writable() {
+			return ErrReadOnlyTransaction
+		}
+		return fn(tx)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.db.Update(fn)
+}
+
+func (s *Store) txFrom(ctx context.Context) *bolt.Tx {
+	tx, ok := ctx.Value(txKey{}).(*bolt.Tx)
+	if !ok || tx.DB() != s.db {
+		return nil
+	}
+	return tx
+}
+
+// TxManager выполняет функции в транзакциях файла хранилища
+type TxManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) *TxManager {
+	return &TxManager{store: store}
+}
+
+// RunInTransaction выполняет fn в транзакции. Если в ctx уже есть транзакция этого хранилища,
+// fn выполняется в ней. Уровень изоляции из opts не учитывается: транзакции всегда сериализуемы.
+// Пишущая транзакция держит файл, поэтому репозитории внутри fn должны получать ctx транзакции
+func (m *TxManager) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error, opts *sql.TxOptions) (err error) {
+	if m.store.txFrom(ctx) != nil {
+		return fn(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	readOnly := opts != nil && opts.ReadOnly
+	tx, err := m.store.db.Begin(!readOnly)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			err = fmt.Errorf("panic occurred during transaction: %v", p)
+			log.Println(err)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if readOnly {
+		return tx.Rollback()
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// timeKeyLen — длина ключа времени: секунды и наносекунды
+const timeKeyLen = 12
+
+// appendTimeKey дописывает время так, чтобы байтовый порядок ключей совпадал с хронологическим
+func appendTimeKey(key []byte, t time.Time) []byte {
+	key = binary.BigEndian.AppendUint64(key, uint64(t.Unix())^(1<<63))
+	return binary.BigEndian.AppendUint32(key, uint32(t.Nanosecond()))
+}
+
+func itob(v uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, v)
+}
+
+// timestamp возвращает текущее время в том виде, в каком оно вернётся после чтения из файла:
+// в UTC и без показаний монотонных часов
+func timestamp() time.Time {
+	return time.Now().UTC()
+}
@@ -0,0 +1,20 @@
+package embedded
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen_LockedByAnotherProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pvz.db")
+	store, err := Open(path, time.Second)
+	require.NoError(t, err)
+	defer store.Close()
+
+	_, err = Open(path, 50*time.Millisecond)
+	assert.ErrorContains(t, err, "locked by another process")
+}
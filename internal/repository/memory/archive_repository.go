@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+)
+
+// ArchiveRepository переносит закрытые заказы в архив. Внешних ключей здесь нет,
+// поэтому возвраты архивных заказов остаются в списке возвратов
+type ArchiveRepository struct {
+	store *Store
+}
+
+func NewArchiveRepository(store *Store) *ArchiveRepository {
+	return &ArchiveRepository{store: store}
+}
+
+// ArchiveOrders переносит в архив до limit удалённых или выданных заказов, которые не менялись
+// с closedBefore, начиная с давно изменённых
+func (r *ArchiveRepository) ArchiveOrders(ctx context.Context, closedBefore time.Time, limit int) (int, error) {
+	var archived int
+	err := r.store.write(ctx, func(st *state) error {
+		var closed []*domain.Order
+		for _, orders := range []map[string]*domain.Order{st.orders, st.removed} {
+			for _, order := range orders {
+				if order.IsClosed() && order.UpdatedAt.Before(closedBefore) {
+					closed = append(closed, order)
+				}
+			}
+		}
+		slices.SortFunc(closed, func(a, b *domain.Order) int {
+			return a.UpdatedAt.Compare(b.UpdatedAt)
+		})
+		if len(closed) > limit {
+			closed = closed[:limit]
+		}
+
+		archivedAt := time.Now()
+		for _, order := range closed {
+			delete(st.orders, order.OrderID)
+			delete(st.removed, order.OrderID)
+			st.archive = append(st.archive, &domain.ArchivedOrder{Order: *order, ArchivedAt: archivedAt})
+		}
+		archived = len(closed)
+		return nil
+	})
+	return archived, err
+}
+
+// GetArchivedOrder возвращает последнюю запись архива с этим order_id
+func (r *ArchiveRepository) GetArchivedOrder(ctx context.Context, orderID string) (*domain.ArchivedOrder, error) {
+	var order *domain.ArchivedOrder
+	err := r.store.read(ctx, func(st *state) error {
+		for i := len(st.archive) - 1; i >= 0; i-- {
+			if st.archive[i].OrderID == orderID {
+				archived := *st.archive[i]
+				order = &archived
+				return nil
+			}
+		}
+		return domain.ErrOrderNotFound
+	})
+	return order, err
+}
+
+// ListArchivedOrders возвращает заказы из архива от последних принятых к первым, начиная со следующего
+// после after. Пустой recipientID означает заказы всех получателей
+func (r *ArchiveRepository) ListArchivedOrders(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int) ([]*domain.ArchivedOrder, error) {
+	var orders []*domain.ArchivedOrder
+	err := r.store.read(ctx, func(st *state) error {
+		for _, order := range st.archive {
+			if (recipientID == "" || order.RecipientID == recipientID) && (after == nil || after.Precedes(&order.Order)) {
+				archived := *order
+				orders = append(orders, &archived)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(orders, func(a, b *domain.ArchivedOrder) int {
+		if c := b.AcceptedAt.Compare(a.AcceptedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.OrderID, a.OrderID)
+	})
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+)
+
+type idempotencyKey struct {
+	key    string
+	method string
+}
+
+type IdempotencyRepository struct {
+	store *Store
+	ttl   time.Duration
+	lease time.Duration
+}
+
+// NewIdempotencyRepository создает хранилище ключей идемпотентности. Результат хранится ttl,
+// а незавершённое резервирование — lease (по умолчанию domain.DefaultIdempotencyLease), после чего ключ
+// можно зарезервировать заново
+func NewIdempotencyRepository(store *Store, ttl, lease time.Duration) *IdempotencyRepository {
+	if lease <= 0 {
+		lease = domain.DefaultIdempotencyLease
+	}
+	return &IdempotencyRepository{
+		store: store,
+		ttl:   ttl,
+		lease: lease,
+	}
+}
+
+// Reserve резервирует ключ за запросом. Если ключ уже занят, возвращает существующую запись
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	var existing *domain.IdempotencyRecord
+	now := time.Now()
+	err := r.store.write(ctx, func(st *state) error {
+		k := idempotencyKey{key: record.Key, method: record.Method}
+		if stored, ok := st.idempotency[k]; ok && !stored.CreatedAt.Before(now.Add(-r.ttl)) && !stored.Abandoned(now) {
+			copied := *stored
+			existing = &copied
+			return nil
+		}
+		record.ReservedUntil = now.Add(r.lease)
+		reserved := *record
+		st.idempotency[k] = &reserved
+		return nil
+	})
+	return existing, err
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	return r.store.write(ctx, func(st *state) error {
+		k := idempotencyKey{key: record.Key, method: record.Method}
+		stored, ok := st.idempotency[k]
+		if !ok || stored.Token != record.Token {
+			return nil
+		}
+		completed := *stored
+		completed.StatusCode = record.StatusCode
+		completed.StatusMessage = record.StatusMessage
+		completed.Response = slices.Clone(record.Response)
+		completed.ResponseHeaders = slices.Clone(record.ResponseHeaders)
+		st.idempotency[k] = &completed
+		return nil
+	})
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, record *domain.IdempotencyRecord) error {
+	return r.store.write(ctx, func(st *state) error {
+		k := idempotencyKey{key: record.Key, method: record.Method}
+		if stored, ok := st.idempotency[k]; ok && stored.Token == record.Token {
+			delete(st.idempotency, k)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRepository_AbandonedReservation(t *testing.T) {
+	repo := NewIdempotencyRepository(NewStore(), time.Hour, time.Millisecond)
+	ctx := context.Background()
+
+	abandoned := &domain.IdempotencyRecord{Key: "key1", Method: "/add", Fingerprint: "f", CreatedAt: time.Now(), Token: "first"}
+	existing, err := repo.Reserve(ctx, abandoned)
+	require.NoError(t, err)
+	require.Nil(t, existing)
+
+	time.Sleep(5 * time.Millisecond)
+
+	// Резервирование с истёкшей арендой перехватывается повтором
+	retry := &domain.IdempotencyRecord{Key: "key1", Method: "/add", Fingerprint: "f", CreatedAt: time.Now(), Token: "second"}
+	existing, err = repo.Reserve(ctx, retry)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// Завершение и освобождение по старому токену не трогают новое резервирование
+	abandoned.StatusCode = sql.NullInt32{Int32: 0, Valid: true}
+	require.NoError(t, repo.Complete(ctx, abandoned))
+	require.NoError(t, repo.Release(ctx, abandoned))
+
+	retry.StatusCode = sql.NullInt32{Int32: 0, Valid: true}
+	require.NoError(t, repo.Complete(ctx, retry))
+
+	time.Sleep(5 * time.Millisecond)
+
+	// Завершённый результат хранится ttl, даже если аренда истекла
+	existing, err = repo.Reserve(ctx, &domain.IdempotencyRecord{Key: "key1", Method: "/add", Fingerprint: "f", CreatedAt: time.Now(), Token: "third"})
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, "second", existing.Token)
+	assert.True(t, existing.Completed())
+}
+
+func TestIdempotencyRepository_LiveReservation(t *testing.T) {
+	repo := NewIdempotencyRepository(NewStore(), time.Hour, time.Minute)
+	ctx := context.Background()
+
+	record := &domain.IdempotencyRecord{Key: "key1", Method: "/add", Fingerprint: "f", CreatedAt: time.Now(), Token: "first"}
+	existing, err := repo.Reserve(ctx, record)
+	require.NoError(t, err)
+	require.Nil(t, existing)
+
+	existing, err = repo.Reserve(ctx, &domain.IdempotencyRecord{Key: "key1", Method: "/add", Fingerprint: "f", CreatedAt: time.Now(), Token: "second"})
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, "first", existing.Token)
+	assert.False(t, existing.Completed())
+}
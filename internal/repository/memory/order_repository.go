@@ -0,0 +1,190 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+)
+
+type OrderRepository struct {
+	store *Store
+}
+
+func NewOrderRepository(store *Store) *OrderRepository {
+	return &OrderRepository{store: store}
+}
+
+// AddOrder добавляет заказ. Как и в postgres, order_id удалённого заказа занят, пока заказ не перенесён в архив
+func (r *OrderRepository) AddOrder(ctx context.Context, order *domain.Order) error {
+	return r.store.write(ctx, func(st *state) error {
+		if st.exists(order.OrderID) {
+			return domain.ErrOrderAlreadyExists
+		}
+		order.UpdatedAt = time.Now()
+		st.orders[order.OrderID] = order.Clone()
+		return nil
+	})
+}
+
+func (r *OrderRepository) GetOrder(ctx context.Context, orderID string) (*domain.Order, error) {
+	var order *domain.Order
+	err := r.store.read(ctx, func(st *state) error {
+		stored, exists := st.orders[orderID]
+		if !exists {
+			return domain.ErrOrderNotFound
+		}
+		order = stored.Clone()
+		return nil
+	})
+	return order, err
+}
+
+// UpdateOrder меняет статус и даты выдачи и возврата и увеличивает order.Version. Как и в postgres,
+// если заказ изменили или удалили после чтения, возвращает domain.ErrOrderVersionConflict
+func (r *OrderRepository) UpdateOrder(ctx context.Context, order *domain.Order) error {
+	now := time.Now()
+	err := r.store.write(ctx, func(st *state) error {
+		stored, exists := st.orders[order.OrderID]
+		if !exists || stored.Version != order.Version {
+			return domain.ErrOrderVersionConflict
+		}
+		updated := stored.Clone()
+		updated.Status = order.Status
+		updated.DeliveryDate = order.DeliveryDate
+		updated.ReturnDate = order.ReturnDate
+		updated.Version++
+		updated.UpdatedAt = now
+		st.orders[order.OrderID] = updated
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	order.Version++
+	order.UpdatedAt = now
+	return nil
+}
+
+// DeleteOrder помечает заказ удалённым с причиной reason и убирает его из выборок
+func (r *OrderRepository) DeleteOrder(ctx context.Context, orderID, reason string) error {
+	return r.store.write(ctx, func(st *state) error {
+		stored, exists := st.orders[orderID]
+		if !exists {
+			return domain.ErrOrderNotFound
+		}
+		removed := stored.Clone()
+		removed.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		removed.RemovalReason = reason
+		removed.UpdatedAt = removed.DeletedAt.Time
+		removed.Version++
+		delete(st.orders, orderID)
+		st.removed[orderID] = removed
+		return nil
+	})
+}
+
+// AddOrders добавляет все заказы или ни одного, если хотя бы один уже есть
+func (r *OrderRepository) AddOrders(ctx context.Context, orders []*domain.Order) error {
+	return r.store.write(ctx, func(st *state) error {
+		seen := make(map[string]bool, len(orders))
+		for _, order := range orders {
+			if st.exists(order.OrderID) || seen[order.OrderID] {
+				return fmt.Errorf("%w: %s", domain.ErrOrderAlreadyExists, order.OrderID)
+			}
+			seen[order.OrderID] = true
+		}
+		now := time.Now()
+		for _, order := range orders {
+			order.UpdatedAt = now
+			st.orders[order.OrderID] = order.Clone()
+		}
+		return nil
+	})
+}
+
+// GetOrdersByIDs возвращает найденные заказы в порядке orderIDs, отсутствующие пропускаются
+func (r *OrderRepository) GetOrdersByIDs(ctx context.Context, orderIDs []string) ([]*domain.Order, error) {
+	var orders []*domain.Order
+	err := r.store.read(ctx, func(st *state) error {
+		for _, orderID := range orderIDs {
+			if stored, exists := st.orders[orderID]; exists {
+				orders = append(orders, stored.Clone())
+			}
+		}
+		return nil
+	})
+	return orders, err
+}
+
+// UpdateOrders обновляет заказы как UpdateOrder, но если хотя бы один изменили или удалили после чтения,
+// не меняет ни один
+func (r *OrderRepository) UpdateOrders(ctx context.Context, orders []*domain.Order) error {
+	now := time.Now()
+	err := r.store.write(ctx, func(st *state) error {
+		for _, order := range orders {
+			stored, exists := st.orders[order.OrderID]
+			if !exists || stored.Version != order.Version {
+				return domain.ErrOrderVersionConflict
+			}
+		}
+		for _, order := range orders {
+			updated := st.orders[order.OrderID].Clone()
+			updated.Status = order.Status
+			updated.DeliveryDate = order.DeliveryDate
+			updated.ReturnDate = order.ReturnDate
+			updated.Version++
+			updated.UpdatedAt = now
+			st.orders[order.OrderID] = updated
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, order := range orders {
+		order.Version++
+		order.UpdatedAt = now
+	}
+	return nil
+}
+
+// ListOrdersByRecipient возвращает заказы получателя от последних принятых к первым,
+// начиная со следующего после after. Если after равен nil, выборка идёт с начала
+func (r *OrderRepository) ListOrdersByRecipient(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int) ([]*domain.Order, error) {
+	return r.ListOrders(ctx, domain.OrderFilter{RecipientID: recipientID}, after, limit)
+}
+
+// ListOrders возвращает заказы, подходящие под filter, в том же порядке, что и ListOrdersByRecipient
+func (r *OrderRepository) ListOrders(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) ([]*domain.Order, error) {
+	var orders []*domain.Order
+	err := r.store.read(ctx, func(st *state) error {
+		for _, order := range st.orders {
+			if filter.Matches(order) && (after == nil || after.Precedes(order)) {
+				orders = append(orders, order)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(orders, func(a, b *domain.Order) int {
+		if c := b.AcceptedAt.Compare(a.AcceptedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.OrderID, a.OrderID)
+	})
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	for i, order := range orders {
+		orders[i] = order.Clone()
+	}
+	return orders, nil
+}
@@ -0,0 +1,20 @@
+package memory
+
+import (
+	"testing"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/repotest"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Backend {
+		store := NewStore()
+		return repotest.Backend{
+			Orders:      NewOrderRepository(store),
+			Returns:     NewReturnRepository(store),
+			Archive:     NewArchiveRepository(store),
+			TxManager:   NewTxManager(store),
+			ErrReadOnly: ErrReadOnlyTransaction,
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+)
+
+type ReturnRepository struct {
+	store *Store
+}
+
+func NewReturnRepository(store *Store) *ReturnRepository {
+	return &ReturnRepository{store: store}
+}
+
+// AddReturn сохраняет возврат с очередным идентификатором, как последовательность в postgres
+func (r *ReturnRepository) AddReturn(ctx context.Context, ret *domain.Return) error {
+	return r.store.write(ctx, func(st *state) error {
+		stored := *ret
+		stored.ID = st.nextReturnID
+		st.nextReturnID++
+		st.returns = append(st.returns, &stored)
+		return nil
+	})
+}
+
+// ListReturns возвращает возвраты от последних к первым, начиная со следующего после after.
+// Если after равен nil, выборка идёт с начала
+func (r *ReturnRepository) ListReturns(ctx context.Context, after *domain.ReturnCursor, limit int) ([]*domain.Return, error) {
+	var returns []*domain.Return
+	err := r.store.read(ctx, func(st *state) error {
+		for _, ret := range st.returns {
+			if after == nil || after.Precedes(ret) {
+				copied := *ret
+				returns = append(returns, &copied)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(returns, func(a, b *domain.Return) int {
+		if c := b.ReturnDate.Compare(a.ReturnDate); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	if len(returns) > limit {
+		returns = returns[:limit]
+	}
+	return returns, nil
+}
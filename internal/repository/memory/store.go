@@ -0,0 +1,190 @@
+// Package memory хранит заказы, возвраты и ключи идемпотентности в памяти процесса.
+// Репозитории реализуют те же интерфейсы, что и postgres, и подходят для тестов и локального запуска
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+)
+
+// ErrReadOnlyTransaction возвращается при попытке записи в транзакции только для чтения
+var ErrReadOnlyTransaction = errors.New("cannot write in a read-only transaction")
+
+type txKey struct{}
+
+// state — данные хранилища. Значения не изменяются на месте: запись кладёт новую копию,
+// поэтому для снимка достаточно скопировать мапы и срезы
+type state struct {
+	orders map[string]*domain.Order
+	// removed — удалённые заказы, которые ещё не перенесены в архив
+	removed      map[string]*domain.Order
+	archive      []*domain.ArchivedOrder
+	returns      []*domain.Return
+	nextReturnID int
+	idempotency  map[idempotencyKey]*domain.IdempotencyRecord
+}
+
+// exists сообщает, что order_id занят действующим или удалённым заказом
+func (s *state) exists(orderID string) bool {
+	_, live := s.orders[orderID]
+	_, removed := s.removed[orderID]
+	return live || removed
+}
+
+func newState() *state {
+	return &state{
+		orders:       make(map[string]*domain.Order),
+		removed:      make(map[string]*domain.Order),
+		nextReturnID: 1,
+		idempotency:  make(map[idempotencyKey]*domain.IdempotencyRecord),
+	}
+}
+
+func (s *state) clone() *state {
+	return &state{
+		orders:       maps.Clone(s.orders),
+		removed:      maps.Clone(s.removed),
+		archive:      slices.Clone(s.archive),
+		returns:      slices.Clone(s.returns),
+		nextReturnID: s.nextReturnID,
+		idempotency:  maps.Clone(s.idempotency),
+	}
+}
+
+// tx — транзакция, которая работает со своей копией данных
+type tx struct {
+	store    *Store
+	state    *state
+	readOnly bool
+}
+
+// Store — общее хранилище репозиториев в памяти.
+//
+// Пишущие транзакции и одиночные записи выполняются по одной, поэтому транзакции сериализуемы
+// при любом уровне изоляции. Транзакция видит снимок данных на момент начала и свои изменения,
+// остальные видят их только после фиксации, а при ошибке изменения отбрасываются
+type Store struct {
+	// writer пропускает одного писателя за раз и, в отличие от мьютекса, позволяет ждать с учётом ctx
+	writer chan struct{}
+	mu     sync.RWMutex
+	state  *state
+}
+
+// NewStore создает пустое хранилище
+func NewStore() *Store {
+	return &Store{
+		writer: make(chan struct{}, 1),
+		state:  newState(),
+	}
+}
+
+// read выполняет fn над данными транзакции из ctx или над зафиксированными данными
+func (s *Store) read(ctx context.Context, fn func(st *state) error) error {
+	if t := s.txFrom(ctx); t != nil {
+		return fn(t.state)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(s.state)
+}
+
+// write выполняет fn в транзакции из ctx, а без неё — как отдельную транзакцию из одной операции.
+// fn должна проверить все условия до изменения данных
+func (s *Store) write(ctx context.Context, fn func(st *state) error) error {
+	if t := s.txFrom(ctx); t != nil {
+		if t.readOnly {
+			return ErrReadOnlyTransaction
+		}
+		return fn(t.state)
+	}
+
+	if err := s.lockWriter(ctx); err != nil {
+		return err
+	}
+	defer s.unlockWriter()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.state)
+}
+
+func (s *Store) txFrom(ctx context.Context) *tx {
+	t, ok := ctx.Value(txKey{}).(*tx)
+	if !ok || t.store != s {
+		return nil
+	}
+	return t
+}
+
+func (s *Store) lockWriter(ctx context.Context) error {
+	select {
+	case s.writer <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Store) unlockWriter() {
+	<-s.writer
+}
+
+// TxManager выполняет функции в транзакциях хранилища в памяти
+type TxManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) *TxManager {
+	return &TxManager{store: store}
+}
+
+// RunInTransaction выполняет fn в транзакции. Если в ctx уже есть транзакция этого хранилища,
+// fn выполняется в ней. Уровень изоляции из opts не учитывается: транзакции всегда сериализуемы
+func (m *TxManager) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error, opts *sql.TxOptions) (err error) {
+	if m.store.txFrom(ctx) != nil {
+		return fn(ctx)
+	}
+
+	readOnly := opts != nil && opts.ReadOnly
+	if !readOnly {
+		if err := m.store.lockWriter(ctx); err != nil {
+			return err
+		}
+		defer m.store.unlockWriter()
+	}
+
+	m.store.mu.RLock()
+	t := &tx{store: m.store, state: m.store.state.clone(), readOnly: readOnly}
+	m.store.mu.RUnlock()
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic occurred during transaction: %v", p)
+			log.Println(err)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, t)); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if readOnly {
+		return nil
+	}
+
+	m.store.mu.Lock()
+	m.store.state = t.state
+	m.store.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/repotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxManager_WritesWaitForTransaction(t *testing.T) {
+	store := NewStore()
+	txManager := NewTxManager(store)
+	orderRepo := NewOrderRepository(store)
+	ctx := context.Background()
+
+	inTx := make(chan struct{})
+	commit := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- txManager.RunInTransaction(ctx, func(ctx context.Context) error {
+			if err := orderRepo.AddOrder(ctx, repotest.NewOrder("order1", "recipient1", domain.OrderStatusNew, repotest.AcceptedAt)); err != nil {
+				return err
+			}
+			close(inTx)
+			<-commit
+			return nil
+		}, nil)
+	}()
+
+	<-inTx
+	// Запись вне транзакции ждёт её завершения
+	writeCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	err := orderRepo.AddOrder(writeCtx, repotest.NewOrder("order2", "recipient1", domain.OrderStatusNew, repotest.AcceptedAt))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(commit)
+	require.NoError(t, <-done)
+}
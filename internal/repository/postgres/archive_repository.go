@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// closedCondition отбирает закрытые заказы, как domain.Order.IsClosed, и совпадает с условием
+// частичного индекса orders_closed_updated_at_idx, чтобы поиск шёл по нему
+const closedCondition = "(deleted_at IS NOT NULL OR status = 'delivered')"
+
+// ArchiveRepository переносит закрытые заказы в orders_archive, а их возвраты — в returns_archive,
+// и читает архив заказов
+type ArchiveRepository struct {
+	db          *Cluster
+	cache       interfaces.Cache[string, *domain.Order]
+	invalidator interfaces.CacheInvalidator
+}
+
+// NewArchiveRepository создает репозиторий архива. Перенесённые заказы убираются из кэша заказов
+// этой реплики и других реплик. Архив читается с реплик db
+func NewArchiveRepository(db *Cluster, cache interfaces.Cache[string, *domain.Order], invalidator interfaces.CacheInvalidator) *ArchiveRepository {
+	return &ArchiveRepository{
+		db:          db,
+		cache:       cache,
+		invalidator: invalidator,
+	}
+}
+
+// ArchiveOrders переносит в архив до limit закрытых заказов, которые не менялись с closedBefore, вместе
+// с их возвратами и возвращает число перенесённых заказов. Заказы, заблокированные другими транзакциями,
+// пропускаются до следующего запуска
+func (r *ArchiveRepository) ArchiveOrders(ctx context.Context, closedBefore time.Time, limit int) (int, error) {
+	var orderIDs []string
+	err := withTx(ctx, r.db.Primary(), func(ctx context.Context, tx *sqlx.Tx) error {
+		err := tx.SelectContext(ctx, &orderIDs, `
+        SELECT order_id FROM orders
+        WHERE `+closedCondition+` AND updated_at < $1
+        ORDER BY updated_at
+        LIMIT $2
+        FOR UPDATE SKIP LOCKED
+    `, closedBefore, limit)
+		if err != nil || len(orderIDs) == 0 {
+			return err
+		}
+
+		ids := pq.Array(orderIDs)
+		archivedAt := time.Now()
+		statements := []struct {
+			query string
+			args  []any
+		}{
+			{`INSERT INTO orders_archive (` + orderColumns + `, archived_at)
+        SELECT ` + orderColumns + `, $2 FROM orders WHERE order_id = ANY($1)`, []any{ids, archivedAt}},
+			{`INSERT INTO returns_archive (id, order_id, recipient_id, return_date, archived_at)
+        SELECT id, order_id, recipient_id, return_date, $2 FROM returns WHERE order_id = ANY($1)`, []any{ids, archivedAt}},
+			// Возвраты удаляются раньше заказов, на которые они ссылаются
+			{`DELETE FROM returns WHERE order_id = ANY($1)`, []any{ids}},
+			{`DELETE FROM orders WHERE order_id = ANY($1)`, []any{ids}},
+		}
+		for _, statement := range statements {
+			if _, err := tx.ExecContext(ctx, statement.query, statement.args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive orders: %w", err)
+	}
+
+	if len(orderIDs) > 0 {
+		afterCommit(ctx, func() {
+			r.cache.DeleteMany(ctx, orderIDs)
+			r.invalidator.Invalidate(ctx, orderIDs...)
+		})
+	}
+	return len(orderIDs), nil
+}
+
+// GetArchivedOrder возвращает заказ из архива. Если заказ с этим order_id архивировали несколько раз,
+// возвращается последняя запись
+func (r *ArchiveRepository) GetArchivedOrder(ctx context.Context, orderID string) (*domain.ArchivedOrder, error) {
+	query := `
+        SELECT ` + orderColumns + `, archived_at
+        FROM orders_archive
+        WHERE order_id = $1
+        ORDER BY archived_at DESC
+        LIMIT 1
+    `
+
+	var order domain.ArchivedOrder
+	db, _ := r.db.reader(ctx)
+	err := sqlx.GetContext(ctx, db, &order, query, orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get archived order: %w", err)
+	}
+	return &order, nil
+}
+
+// ListArchivedOrders возвращает заказы получателя из архива в том же порядке, что и ListOrdersByRecipient.
+// Пустой recipientID означает заказы всех получателей
+func (r *ArchiveRepository) ListArchivedOrders(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int) ([]*domain.ArchivedOrder, error) {
+	var (
+		conditions = "TRUE"
+		args       = []any{limit}
+	)
+	if recipientID != "" {
+		args = append(args, recipientID)
+		conditions += fmt.Sprintf(" AND recipient_id = $%d", len(args))
+	}
+	if after != nil {
+		args = append(args, after.AcceptedAt, after.OrderID)
+		conditions += fmt.Sprintf(" AND (accepted_at, order_id) < ($%d, $%d)", len(args)-1, len(args))
+	}
+
+	query := `
+        SELECT ` + orderColumns + `, archived_at
+        FROM orders_archive
+        WHERE ` + conditions + `
+        ORDER BY accepted_at DESC, order_id DESC
+        LIMIT $1
+    `
+
+	var orders []*domain.ArchivedOrder
+	db, _ := r.db.reader(ctx)
+	err := sqlx.SelectContext(ctx, db, &orders, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archived orders: %w", err)
+	}
+	return orders, nil
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// replicationLagQuery возвращает отставание реплики в секундах. Если реплика применила всё полученное,
+// отставание считается нулевым, иначе на простаивающей основной базе оно росло бы без записей
+const replicationLagQuery = `
+    SELECT CASE
+        WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+        ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+    END`
+
+const defaultCheckInterval = 5 * time.Second
+
+// ClusterConfig задаёт маршрутизацию чтений по репликам
+type ClusterConfig struct {
+	// StickyWindow — сколько после записи запрос читает с основной базы, пока реплики её не получат
+	StickyWindow time.Duration
+	// CheckInterval — период проверки реплик, по умолчанию 5 секунд
+	CheckInterval time.Duration
+	// MaxLag — отставание, после которого реплика перестаёт получать чтения
+	MaxLag time.Duration
+}
+
+type replica struct {
+	db      *sqlx.DB
+	name    string
+	healthy atomic.Bool
+}
+
+// Cluster направляет записи и чтения в транзакциях на основную базу, а остальные чтения — по кругу
+// на исправные реплики. Если исправных реплик нет, все чтения идут на основную базу
+type Cluster struct {
+	primary  *sqlx.DB
+	replicas []*replica
+	next     atomic.Uint64
+	config   ClusterConfig
+}
+
+// NewCluster создает кластер из основной базы и реплик. Реплики получают чтения только после первой
+// успешной проверки в Run
+func NewCluster(primary *sqlx.DB, replicas []*sqlx.DB, config ClusterConfig) *Cluster {
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = defaultCheckInterval
+	}
+	c := &Cluster{
+		primary: primary,
+		config:  config,
+	}
+	for i, db := range replicas {
+		c.replicas = append(c.replicas, &replica{db: db, name: fmt.Sprintf("replica-%d", i+1)})
+	}
+	return c
+}
+
+// Primary возвращает подключение к основной базе
+func (c *Cluster) Primary() *sqlx.DB {
+	return c.primary
+}
+
+// Run проверяет реплики каждые CheckInterval, пока не отменят ctx
+func (c *Cluster) Run(ctx context.Context) {
+	if len(c.replicas) == 0 {
+		return
+	}
+
+	c.checkReplicas(ctx)
+	ticker := time.NewTicker(c.config.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.checkReplicas(ctx)
+		}
+	}
+}
+
+func (c *Cluster) checkReplicas(ctx context.Context) {
+	for _, r := range c.replicas {
+		err := c.checkReplica(ctx, r)
+		healthy := err == nil
+		if r.healthy.Swap(healthy) != healthy {
+			if healthy {
+				log.Printf("Database %s is healthy, routing reads to it", r.name)
+			} else {
+				log.Printf("Database %s is unhealthy, routing its reads to primary: %v", r.name, err)
+			}
+		}
+	}
+}
+
+func (c *Cluster) checkReplica(ctx context.Context, r *replica) error {
+	ctx, cancel := context.WithTimeout(ctx, c.config.CheckInterval)
+	defer cancel()
+
+	var lag float64
+	if err := r.db.GetContext(ctx, &lag, replicationLagQuery); err != nil {
+		return err
+	}
+	if c.config.MaxLag > 0 && time.Duration(lag*float64(time.Second)) > c.config.MaxLag {
+		return fmt.Errorf("replication lag %.1fs exceeds %s", lag, c.config.MaxLag)
+	}
+	return nil
+}
+
+// writer возвращает транзакцию из ctx, а без неё — основную базу
+func (c *Cluster) writer(ctx context.Context) sqlx.ExtContext {
+	return extFrom(ctx, c.primary)
+}
+
+// reader возвращает подключение для чтения и сообщает, ведёт ли оно на реплику. В транзакции чтение
+// идёт в ней, а после записи в том же запросе — с основной базы
+func (c *Cluster) reader(ctx context.Context) (sqlx.ExtContext, bool) {
+	if txStateFrom(ctx) != nil {
+		return c.writer(ctx), false
+	}
+	if session := domain.ReadSessionFrom(ctx); session != nil && session.ReadFromPrimary(time.Now()) {
+		return c.primary, false
+	}
+	if r := c.pickReplica(); r != nil {
+		return r.db, true
+	}
+	return c.primary, false
+}
+
+func (c *Cluster) pickReplica() *replica {
+	n := len(c.replicas)
+	if n == 0 {
+		return nil
+	}
+	start := c.next.Add(1)
+	for i := 0; i < n; i++ {
+		r := c.replicas[(start+uint64(i))%uint64(n)]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// markWritten направляет чтения запроса из ctx на основную базу на StickyWindow
+func (c *Cluster) markWritten(ctx context.Context) {
+	if session := domain.ReadSessionFrom(ctx); session != nil {
+		session.StickToPrimary(time.Now().Add(c.config.StickyWindow))
+	}
+}
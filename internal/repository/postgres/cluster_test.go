@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/cache"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openLazy открывает подключение без обращения к базе: для маршрутизации оно не нужно
+func openLazy(t *testing.T) *sqlx.DB {
+	db, err := sqlx.Open("postgres", "postgres://localhost/unused?sslmode=disable")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestCluster_Reader(t *testing.T) {
+	primary, first, second := openLazy(t), openLazy(t), openLazy(t)
+	cluster := NewCluster(primary, []*sqlx.DB{first, second}, ClusterConfig{StickyWindow: time.Minute})
+	ctx := context.Background()
+
+	db, fromReplica := cluster.reader(ctx)
+	assert.Same(t, primary, db, "replicas must not get reads before the first health check")
+	assert.False(t, fromReplica)
+
+	for _, r := range cluster.replicas {
+		r.healthy.Store(true)
+	}
+	seen := map[*sqlx.DB]int{}
+	for i := 0; i < 4; i++ {
+		db, fromReplica := cluster.reader(ctx)
+		assert.True(t, fromReplica)
+		seen[db.(*sqlx.DB)]++
+	}
+	assert.Equal(t, map[*sqlx.DB]int{first: 2, second: 2}, seen)
+
+	cluster.replicas[0].healthy.Store(false)
+	for i := 0; i < 2; i++ {
+		db, _ := cluster.reader(ctx)
+		assert.Same(t, second, db)
+	}
+
+	cluster.replicas[1].healthy.Store(false)
+	db, fromReplica = cluster.reader(ctx)
+	assert.Same(t, primary, db)
+	assert.False(t, fromReplica)
+}
+
+func TestCluster_ReadYourWrites(t *testing.T) {
+	primary, replicaDB := openLazy(t), openLazy(t)
+	cluster := NewCluster(primary, []*sqlx.DB{replicaDB}, ClusterConfig{StickyWindow: time.Minute})
+	cluster.replicas[0].healthy.Store(true)
+
+	session := &domain.ReadSession{}
+	ctx := domain.WithReadSession(context.Background(), session)
+
+	db, _ := cluster.reader(ctx)
+	assert.Same(t, replicaDB, db)
+
+	cluster.markWritten(ctx)
+	db, fromReplica := cluster.reader(ctx)
+	assert.Same(t, primary, db, "reads after a write must go to primary")
+	assert.False(t, fromReplica)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), session.PrimaryUntil(), time.Second)
+
+	expired := &domain.ReadSession{}
+	expired.StickToPrimary(time.Now().Add(-time.Second))
+	db, _ = cluster.reader(domain.WithReadSession(context.Background(), expired))
+	assert.Same(t, replicaDB, db)
+}
+
+// acceptingDriver выполняет любой запрос без базы: каждая команда меняет одну строку, а выборки пусты.
+// Его достаточно, чтобы проверить, что делают репозитории после успешной записи
+type acceptingDriver struct{}
+
+func (acceptingDriver) Open(name string) (driver.Conn, error) { return acceptingConn{}, nil }
+
+type acceptingConn struct{}
+
+func (acceptingConn) Prepare(query string) (driver.Stmt, error)      { return acceptingStmt{}, nil }
+func (acceptingConn) Close() error                                   { return nil }
+func (acceptingConn) Begin() (driver.Tx, error)                      { return acceptingTx{}, nil }
+func (acceptingConn) CheckNamedValue(value *driver.NamedValue) error { return nil }
+
+type acceptingStmt struct{}
+
+func (acceptingStmt) Close() error  { return nil }
+func (acceptingStmt) NumInput() int { return -1 }
+func (acceptingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+func (acceptingStmt) Query(args []driver.Value) (driver.Rows, error) { return emptyRows{}, nil }
+
+type acceptingTx struct{}
+
+func (acceptingTx) Commit() error   { return nil }
+func (acceptingTx) Rollback() error { return nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return nil }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+func init() {
+	sql.Register("accepting", acceptingDriver{})
+}
+
+func TestCluster_WritesStickToPrimary(t *testing.T) {
+	db, err := sql.Open("accepting", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	// Имя драйвера задаёт плейсхолдеры $n, как у postgres
+	primary := sqlx.NewDb(db, "postgres")
+
+	cluster := NewCluster(primary, []*sqlx.DB{openLazy(t)}, ClusterConfig{StickyWindow: time.Minute})
+	cluster.replicas[0].healthy.Store(true)
+	orderCache := cache.NewLRUCache[string, *domain.Order](10, time.Minute, time.Minute)
+	t.Cleanup(func() { _ = orderCache.Close() })
+	orderRepo := NewOrderRepository(cluster, orderCache, nopInvalidator{})
+	returnRepo := NewReturnRepository(cluster)
+
+	newOrder := func() *domain.Order {
+		return &domain.Order{OrderID: "order1", RecipientID: "recipient1", Status: domain.OrderStatusNew, Version: 1}
+	}
+	for name, write := range map[string]func(ctx context.Context) error{
+		"AddOrder":     func(ctx context.Context) error { return orderRepo.AddOrder(ctx, newOrder()) },
+		"AddOrders":    func(ctx context.Context) error { return orderRepo.AddOrders(ctx, []*domain.Order{newOrder()}) },
+		"UpdateOrder":  func(ctx context.Context) error { return orderRepo.UpdateOrder(ctx, newOrder()) },
+		"UpdateOrders": func(ctx context.Context) error { return orderRepo.UpdateOrders(ctx, []*domain.Order{newOrder()}) },
+		"DeleteOrder":  func(ctx context.Context) error { return orderRepo.DeleteOrder(ctx, "order1", "damaged") },
+		"AddReturn": func(ctx context.Context) error {
+			return returnRepo.AddReturn(ctx, &domain.Return{OrderID: "order1", RecipientID: "recipient1"})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			session := &domain.ReadSession{}
+			ctx := domain.WithReadSession(context.Background(), session)
+
+			require.NoError(t, write(ctx))
+			assert.WithinDuration(t, time.Now().Add(time.Minute), session.PrimaryUntil(), time.Second)
+			reader, fromReplica := cluster.reader(ctx)
+			assert.Same(t, primary, reader, "reads after %s must go to primary", name)
+			assert.False(t, fromReplica)
+		})
+	}
+}
+
+func TestOrderRepository_WarmUpCacheReadsPrimary(t *testing.T) {
+	db, err := sql.Open("accepting", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	primary := sqlx.NewDb(db, "postgres")
+
+	// Реплика никуда не подключается, поэтому чтение с неё закончилось бы ошибкой
+	cluster := NewCluster(primary, []*sqlx.DB{openLazy(t)}, ClusterConfig{StickyWindow: time.Minute})
+	cluster.replicas[0].healthy.Store(true)
+	orderCache := cache.NewLRUCache[string, *domain.Order](10, time.Minute, time.Minute)
+	t.Cleanup(func() { _ = orderCache.Close() })
+	orderRepo := NewOrderRepository(cluster, orderCache, nopInvalidator{})
+
+	loaded, err := orderRepo.WarmUpCache(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Zero(t, loaded)
+}
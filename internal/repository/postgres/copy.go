@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// copyFrom загружает rows в table одной командой COPY в транзакции из ctx. Через pgx COPY идёт напрямую
+// по подключению транзакции, а через lib/pq — подготовленным запросом в tx
+func copyFrom(ctx context.Context, tx *sqlx.Tx, table string, columns []string, rows [][]any) error {
+	if tx.DriverName() != pgxDriver {
+		return copyIn(ctx, tx, table, columns, rows)
+	}
+
+	state := txStateFrom(ctx)
+	if state == nil {
+		return fmt.Errorf("transaction not found in context")
+	}
+	return state.conn.Raw(func(driverConn any) error {
+		conn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unexpected pgx driver connection %T", driverConn)
+		}
+		_, err := conn.Conn().CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows))
+		return err
+	})
+}
+
+func copyIn(ctx context.Context, tx *sqlx.Tx, table string, columns []string, rows [][]any) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return err
+		}
+	}
+	// Пустой Exec отправляет накопленные строки и завершает COPY
+	_, err = stmt.ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// pgError возвращает SQLSTATE и подробности ошибки postgres от драйвера pgx или lib/pq
+func pgError(err error) (code, detail string, ok bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code, pgErr.Detail, true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code), pqErr.Detail, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type IdempotencyRepository struct {
+	db    *sqlx.DB
+	ttl   time.Duration
+	lease time.Duration
+}
+
+// NewIdempotencyRepository создает хранилище ключей идемпотентности. Результат хранится ttl,
+// а незавершённое резервирование — lease (по умолчанию domain.DefaultIdempotencyLease), после чего ключ
+// можно зарезервировать заново
+func NewIdempotencyRepository(db *sqlx.DB, ttl, lease time.Duration) *IdempotencyRepository {
+	if lease <= 0 {
+		lease = domain.DefaultIdempotencyLease
+	}
+	return &IdempotencyRepository{
+		db:    db,
+		ttl:   ttl,
+		lease: lease,
+	}
+}
+
+// Reserve резервирует ключ за запросом. Если ключ уже занят, возвращает существующую запись
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	now := time.Now()
+	_, err := extFrom(ctx, r.db).ExecContext(ctx, `
+        DELETE FROM idempotency_keys
+        WHERE idempotency_key = $1 AND method = $2
+            AND (created_at < $3 OR (status_code IS NULL AND reserved_until < $4))
+    `, record.Key, record.Method, now.Add(-r.ttl), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge expired idempotency key: %w", err)
+	}
+
+	record.ReservedUntil = now.Add(r.lease)
+	query := `
+        INSERT INTO idempotency_keys (idempotency_key, method, fingerprint, created_at, reservation_token, reserved_until)
+        VALUES (:idempotency_key, :method, :fingerprint, :created_at, :reservation_token, :reserved_until)
+        ON CONFLICT (idempotency_key, method) DO NOTHING
+    `
+
+	result, err := sqlx.NamedExecContext(ctx, extFrom(ctx, r.db), query, record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing domain.IdempotencyRecord
+	err = sqlx.GetContext(ctx, extFrom(ctx, r.db), &existing, `
+        SELECT idempotency_key, method, fingerprint, status_code, status_message, response, response_headers,
+            created_at, reservation_token, reserved_until
+        FROM idempotency_keys WHERE idempotency_key = $1 AND method = $2
+    `, record.Key, record.Method)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return &existing, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	query := `
+        UPDATE idempotency_keys SET
+            status_code = :status_code,
+            status_message = :status_message,
+            response = :response,
+            response_headers = :response_headers
+        WHERE idempotency_key = :idempotency_key AND method = :method AND reservation_token = :reservation_token
+    `
+
+	_, err := sqlx.NamedExecContext(ctx, extFrom(ctx, r.db), query, record)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, record *domain.IdempotencyRecord) error {
+	_, err := extFrom(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE idempotency_key = $1 AND method = $2 AND reservation_token = $3`,
+		record.Key, record.Method, record.Token)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// LegacyImportRepository переносит заказы и возвраты из старого файлового хранилища.
+// Пишет напрямую в таблицы, минуя кэш заказов, и работает в транзакции из ctx
+type LegacyImportRepository struct {
+	db *sqlx.DB
+}
+
+func NewLegacyImportRepository(db *sqlx.DB) *LegacyImportRepository {
+	return &LegacyImportRepository{db: db}
+}
+
+func (r *LegacyImportRepository) ExistingOrderIDs(ctx context.Context, orderIDs []string) ([]string, error) {
+	var existing []string
+	err := sqlx.SelectContext(ctx, extFrom(ctx, r.db), &existing,
+		`SELECT order_id FROM orders WHERE order_id = ANY($1)`, pq.Array(orderIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing orders: %w", err)
+	}
+	return existing, nil
+}
+
+func (r *LegacyImportRepository) ReturnedOrderIDs(ctx context.Context, orderIDs []string) ([]string, error) {
+	var returned []string
+	err := sqlx.SelectContext(ctx, extFrom(ctx, r.db), &returned,
+		`SELECT DISTINCT order_id FROM returns WHERE order_id = ANY($1)`, pq.Array(orderIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing returns: %w", err)
+	}
+	return returned, nil
+}
+
+// InsertOrders добавляет заказы одним запросом
+func (r *LegacyImportRepository) InsertOrders(ctx context.Context, orders []*domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	query := `
+        INSERT INTO orders (
+            order_id, recipient_id, expiry_date, status, delivery_date, return_date, weight, cost, packaging_type, accepted_at
+        ) VALUES (
+            :order_id, :recipient_id, :expiry_date, :status, :delivery_date, :return_date, :weight, :cost, :packaging_type, :accepted_at
+        )
+    `
+	if _, err := sqlx.NamedExecContext(ctx, extFrom(ctx, r.db), query, orders); err != nil {
+		return fmt.Errorf("failed to insert orders: %w", err)
+	}
+	return nil
+}
+
+// InsertReturns добавляет возвраты одним запросом
+func (r *LegacyImportRepository) InsertReturns(ctx context.Context, returns []*domain.Return) error {
+	if len(returns) == 0 {
+		return nil
+	}
+	query :=
+		`INSERT INTO returns (order_id, recipient_id, return_date)
+	VALUES (:order_id, :recipient_id, :return_date)`
+	if _, err := sqlx.NamedExecContext(ctx, extFrom(ctx, r.db), query, returns); err != nil {
+		return fmt.Errorf("failed to insert returns: %w", err)
+	}
+	return nil
+}
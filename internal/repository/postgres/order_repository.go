@@ -6,41 +6,62 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+const uniqueViolation = "23505"
+
+// orderColumns — столбцы заказа в порядке полей domain.Order
+const orderColumns = "order_id, recipient_id, expiry_date, status, delivery_date, return_date, weight, cost, packaging_type, accepted_at, version, updated_at, deleted_at, removal_reason"
+
 type OrderRepository struct {
-	db    *sqlx.DB
-	cache interfaces.Cache[string, *domain.Order]
+	db          *Cluster
+	cache       interfaces.Cache[string, *domain.Order]
+	invalidator interfaces.CacheInvalidator
 }
 
-func NewOrderRepository(db *sqlx.DB, cache interfaces.Cache[string, *domain.Order]) *OrderRepository {
+// NewOrderRepository создает репозиторий заказов. После каждой записи локальный кэш обновляется сразу,
+// а кэши других реплик получают инвалидацию через invalidator. Внутри транзакции кэш обновляется
+// только после её фиксации, а заказы читаются в обход кэша. Выборки списков идут на реплики db,
+// остальные запросы — на основную базу
+func NewOrderRepository(db *Cluster, cache interfaces.Cache[string, *domain.Order], invalidator interfaces.CacheInvalidator) *OrderRepository {
 	return &OrderRepository{
-		db:    db,
-		cache: cache,
+		db:          db,
+		cache:       cache,
+		invalidator: invalidator,
 	}
 }
 
 func (r *OrderRepository) AddOrder(ctx context.Context, order *domain.Order) error {
 	query := `
         INSERT INTO orders (
-            order_id, recipient_id, expiry_date, status, weight, cost, packaging_type
+            order_id, recipient_id, expiry_date, status, weight, cost, packaging_type, accepted_at, version, updated_at
         ) VALUES (
-            :order_id, :recipient_id, :expiry_date, :status, :weight, :cost, :packaging_type
+            :order_id, :recipient_id, :expiry_date, :status, :weight, :cost, :packaging_type, :accepted_at, :version, :updated_at
         )
     `
 
-	_, err := r.db.NamedExecContext(ctx, query, order)
+	order.UpdatedAt = time.Now()
+
+	_, err := sqlx.NamedExecContext(ctx, r.db.writer(ctx), query, order)
 	if err != nil {
+		if code, _, ok := pgError(err); ok && code == uniqueViolation {
+			return domain.ErrOrderAlreadyExists
+		}
 		return fmt.Errorf("failed to add order: %w", err)
 	}
 
-	r.cache.Set(ctx, order.OrderID, order)
+	r.db.markWritten(ctx)
+	// Другие реплики могли запомнить этот заказ как отсутствующий
+	r.setAfterCommit(ctx, order)
 	return nil
 }
 
@@ -48,50 +69,91 @@ func (r *OrderRepository) GetOrder(ctx context.Context, orderID string) (*domain
 	span, ctx := opentracing.StartSpanFromContext(ctx, "OrderRepository.GetOrder")
 	defer span.Finish()
 
-	order, found := r.cache.Get(ctx, orderID)
-	if found {
-		return order, nil
-	}
-
 	query := `
-        SELECT order_id, recipient_id, expiry_date, status, delivery_date, return_date, weight, cost, packaging_type
-        FROM orders WHERE order_id = $1
+        SELECT ` + orderColumns + `
+        FROM orders WHERE order_id = $1 AND deleted_at IS NULL
     `
 
-	var fetchedOrder domain.Order
-	err := r.db.GetContext(ctx, &fetchedOrder, query, orderID)
+	load := func(ctx context.Context) (*domain.Order, error) {
+		var fetchedOrder domain.Order
+		err := sqlx.GetContext(ctx, r.db.writer(ctx), &fetchedOrder, query, orderID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, domain.ErrCacheMiss
+			}
+			return nil, fmt.Errorf("failed to get order: %w", err)
+		}
+		return &fetchedOrder, nil
+	}
+
+	var (
+		order *domain.Order
+		err   error
+	)
+	if txStateFrom(ctx) != nil {
+		// Транзакция должна видеть свои изменения и не должна класть в кэш то, что ещё могут откатить
+		order, err = load(ctx)
+	} else {
+		order, err = r.cache.GetOrLoad(ctx, orderID, load)
+	}
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, domain.ErrCacheMiss) {
 			return nil, domain.ErrOrderNotFound
 		}
-		return nil, fmt.Errorf("failed to get order: %w", err)
+		return nil, err
 	}
 
-	r.cache.Set(ctx, fetchedOrder.OrderID, &fetchedOrder)
-	return &fetchedOrder, nil
+	return order, nil
 }
 
+// UpdateOrder меняет статус и даты выдачи и возврата, если версия заказа в базе совпадает с order.Version,
+// и увеличивает order.Version. Если заказ изменили или удалили после чтения, возвращает domain.ErrOrderVersionConflict
 func (r *OrderRepository) UpdateOrder(ctx context.Context, order *domain.Order) error {
 	query := `
         UPDATE orders SET
             status = :status,
             delivery_date = :delivery_date,
-            return_date = :return_date
-        WHERE order_id = :order_id
+            return_date = :return_date,
+            version = version + 1,
+            updated_at = :updated_at
+        WHERE order_id = :order_id AND version = :version AND deleted_at IS NULL
     `
 
-	_, err := r.db.NamedExecContext(ctx, query, order)
+	updated := *order
+	updated.UpdatedAt = time.Now()
+	result, err := sqlx.NamedExecContext(ctx, r.db.writer(ctx), query, &updated)
 	if err != nil {
 		return fmt.Errorf("failed to update order: %w", err)
 	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		// Заказ могли прочитать из устаревшей записи кэша, повторная попытка должна читать из базы
+		r.cache.Delete(ctx, order.OrderID)
+		return domain.ErrOrderVersionConflict
+	}
+	order.Version++
+	order.UpdatedAt = updated.UpdatedAt
 
-	r.cache.Set(ctx, order.OrderID, order)
+	r.db.markWritten(ctx)
+	r.setAfterCommit(ctx, order)
 	return nil
 }
 
-func (r *OrderRepository) DeleteOrder(ctx context.Context, orderID string) error {
-	query := `DELETE FROM orders WHERE order_id = $1`
-	result, err := r.db.ExecContext(ctx, query, orderID)
+// DeleteOrder помечает заказ удалённым с причиной reason. Строка остаётся в таблице, чтобы не нарушать
+// ссылки возвратов, и переносится в архив фоновой задачей
+func (r *OrderRepository) DeleteOrder(ctx context.Context, orderID, reason string) error {
+	query := `
+        UPDATE orders SET
+            deleted_at = $2,
+            removal_reason = $3,
+            updated_at = $2,
+            version = version + 1
+        WHERE order_id = $1 AND deleted_at IS NULL
+    `
+	result, err := r.db.writer(ctx).ExecContext(ctx, query, orderID, time.Now(), reason)
 	if err != nil {
 		return fmt.Errorf("failed to delete order: %w", err)
 	}
@@ -103,31 +165,316 @@ func (r *OrderRepository) DeleteOrder(ctx context.Context, orderID string) error
 		return domain.ErrOrderNotFound
 	}
 
-	r.cache.Delete(ctx, orderID)
+	r.db.markWritten(ctx)
+	afterCommit(ctx, func() {
+		r.cache.Delete(ctx, orderID)
+		r.invalidator.Invalidate(ctx, orderID)
+	})
 	return nil
 }
 
-func (r *OrderRepository) ListOrdersByRecipient(ctx context.Context, recipientID string, limit int) ([]*domain.Order, error) {
+// setAfterCommit кладёт копию заказа в кэш и рассылает инвалидацию после фиксации транзакции из ctx.
+// Копия нужна, чтобы дальнейшие изменения order в транзакции не попали в кэш
+func (r *OrderRepository) setAfterCommit(ctx context.Context, order *domain.Order) {
+	cached := *order
+	afterCommit(ctx, func() {
+		r.cache.Set(ctx, cached.OrderID, &cached)
+		r.invalidator.Invalidate(ctx, cached.OrderID)
+	})
+}
+
+// cacheListed кладёт в кэш заказы из выборки, если она сделана не в транзакции. Выборки с реплик
+// в кэш не попадают: отстающая реплика могла бы затереть в нём более свежий заказ
+func (r *OrderRepository) cacheListed(ctx context.Context, orders []*domain.Order) {
+	if txStateFrom(ctx) != nil {
+		return
+	}
+	for _, order := range orders {
+		r.cache.Set(ctx, order.OrderID, order)
+	}
+}
+
+// AddOrders добавляет заказы одной командой COPY. Если хотя бы один заказ уже есть, не добавляется ни один
+// и возвращается domain.ErrOrderAlreadyExists
+func (r *OrderRepository) AddOrders(ctx context.Context, orders []*domain.Order) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "OrderRepository.AddOrders")
+	defer span.Finish()
+
+	if len(orders) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	columns := []string{"order_id", "recipient_id", "expiry_date", "status", "weight", "cost", "packaging_type", "accepted_at", "version", "updated_at"}
+	rows := make([][]any, 0, len(orders))
+	for _, order := range orders {
+		rows = append(rows, []any{order.OrderID, order.RecipientID, order.ExpiryDate, order.Status,
+			order.Weight, order.Cost, order.PackagingType, order.AcceptedAt, order.Version, now})
+	}
+	err := withTx(ctx, r.db.Primary(), func(ctx context.Context, tx *sqlx.Tx) error {
+		return copyFrom(ctx, tx, "orders", columns, rows)
+	})
+	if err != nil {
+		if code, detail, ok := pgError(err); ok && code == uniqueViolation {
+			return fmt.Errorf("%w: %s", domain.ErrOrderAlreadyExists, detail)
+		}
+		return fmt.Errorf("failed to add orders: %w", err)
+	}
+
+	r.db.markWritten(ctx)
+	for _, order := range orders {
+		order.UpdatedAt = now
+		r.setAfterCommit(ctx, order)
+	}
+	return nil
+}
+
+// GetOrdersByIDs возвращает найденные заказы из orderIDs в том же порядке, отсутствующие пропускаются.
+// Вне транзакции заказы сначала ищутся в кэше, а из базы одним запросом читаются только промахи
+func (r *OrderRepository) GetOrdersByIDs(ctx context.Context, orderIDs []string) ([]*domain.Order, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "OrderRepository.GetOrdersByIDs")
+	defer span.Finish()
+
+	found := make(map[string]*domain.Order, len(orderIDs))
+	missing := orderIDs
+	if txStateFrom(ctx) == nil {
+		found = r.cache.GetMany(ctx, orderIDs)
+		missing = make([]string, 0, len(orderIDs)-len(found))
+		for _, orderID := range orderIDs {
+			if _, ok := found[orderID]; !ok {
+				missing = append(missing, orderID)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		query := `
+        SELECT ` + orderColumns + `
+        FROM orders WHERE order_id = ANY($1) AND deleted_at IS NULL
+    `
+		var loaded []*domain.Order
+		err := sqlx.SelectContext(ctx, r.db.writer(ctx), &loaded, query, pq.Array(missing))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get orders: %w", err)
+		}
+		r.cacheListed(ctx, loaded)
+		for _, order := range loaded {
+			found[order.OrderID] = order
+		}
+	}
+
+	orders := make([]*domain.Order, 0, len(found))
+	for _, orderID := range orderIDs {
+		if order, ok := found[orderID]; ok {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+// updateOrdersChunk — сколько заказов UpdateOrders меняет одним запросом, чтобы не упереться в лимит параметров
+const updateOrdersChunk = 1000
+
+// UpdateOrders делает то же, что UpdateOrder для каждого заказа, но одним запросом на пачку. Если хотя бы
+// один заказ изменили или удалили после чтения, не меняется ни один и возвращается domain.ErrOrderVersionConflict
+func (r *OrderRepository) UpdateOrders(ctx context.Context, orders []*domain.Order) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "OrderRepository.UpdateOrders")
+	defer span.Finish()
+
+	now := time.Now()
+	err := withTx(ctx, r.db.Primary(), func(ctx context.Context, tx *sqlx.Tx) error {
+		for start := 0; start < len(orders); start += updateOrdersChunk {
+			chunk := orders[start:min(start+updateOrdersChunk, len(orders))]
+
+			var values []string
+			args := []any{now}
+			for _, order := range chunk {
+				n := len(args) - 1
+				values = append(values, fmt.Sprintf("($%d, $%d, $%d::timestamp, $%d::timestamp, $%d::bigint)", n+2, n+3, n+4, n+5, n+6))
+				args = append(args, order.OrderID, order.Status, order.DeliveryDate, order.ReturnDate, order.Version)
+			}
+			query := `
+        UPDATE orders AS o SET
+            status = v.status,
+            delivery_date = v.delivery_date,
+            return_date = v.return_date,
+            version = o.version + 1,
+            updated_at = $1
+        FROM (VALUES ` + strings.Join(values, ", ") + `) AS v(order_id, status, delivery_date, return_date, version)
+        WHERE o.order_id = v.order_id AND o.version = v.version AND o.deleted_at IS NULL
+    `
+			result, err := tx.ExecContext(ctx, query, args...)
+			if err != nil {
+				return fmt.Errorf("failed to update orders: %w", err)
+			}
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get affected rows: %w", err)
+			}
+			if rowsAffected != int64(len(chunk)) {
+				return domain.ErrOrderVersionConflict
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderVersionConflict) {
+			// Какой из заказов устарел, неизвестно, поэтому из кэша убираются все
+			ids := make([]string, len(orders))
+			for i, order := range orders {
+				ids[i] = order.OrderID
+			}
+			r.cache.DeleteMany(ctx, ids)
+		}
+		return err
+	}
+
+	r.db.markWritten(ctx)
+	for _, order := range orders {
+		order.Version++
+		order.UpdatedAt = now
+		r.setAfterCommit(ctx, order)
+	}
+	return nil
+}
+
+// ListOrdersByRecipient возвращает заказы получателя от последних принятых к первым,
+// начиная со следующего после after. Если after равен nil, выборка идёт с начала
+func (r *OrderRepository) ListOrdersByRecipient(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int) ([]*domain.Order, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "OrderRepository.ListOrdersByRecipient")
 	defer span.Finish()
 
 	query := `
-        SELECT order_id, recipient_id, expiry_date, status, delivery_date, return_date, weight, cost, packaging_type
+        SELECT ` + orderColumns + `
         FROM orders
-        WHERE recipient_id = $1
-        ORDER BY order_id DESC
+        WHERE recipient_id = $1 AND deleted_at IS NULL
+        ORDER BY accepted_at DESC, order_id DESC
         LIMIT $2
     `
+	args := []any{recipientID, limit}
+	if after != nil {
+		query = `
+        SELECT ` + orderColumns + `
+        FROM orders
+        WHERE recipient_id = $1 AND deleted_at IS NULL AND (accepted_at, order_id) < ($3, $4)
+        ORDER BY accepted_at DESC, order_id DESC
+        LIMIT $2
+    `
+		args = append(args, after.AcceptedAt, after.OrderID)
+	}
 
 	var orders []*domain.Order
-	err := r.db.SelectContext(ctx, &orders, query, recipientID, limit)
+	db, fromReplica := r.db.reader(ctx)
+	err := sqlx.SelectContext(ctx, db, &orders, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list orders: %w", err)
 	}
 
-	for _, order := range orders {
-		r.cache.Set(ctx, order.OrderID, order)
+	if !fromReplica {
+		r.cacheListed(ctx, orders)
+	}
+
+	return orders, nil
+}
+
+// ListOrders возвращает заказы, подходящие под фильтр, в том же порядке, что и ListOrdersByRecipient
+func (r *OrderRepository) ListOrders(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) ([]*domain.Order, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "OrderRepository.ListOrders")
+	defer span.Finish()
+
+	query, args := listOrdersQuery(filter, after, limit)
+
+	var orders []*domain.Order
+	db, fromReplica := r.db.reader(ctx)
+	err := sqlx.SelectContext(ctx, db, &orders, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	if !fromReplica {
+		r.cacheListed(ctx, orders)
 	}
 
 	return orders, nil
 }
+
+// listOrdersQuery строит запрос ListOrders: каждое заданное поле фильтра добавляет условие со своим аргументом
+func listOrdersQuery(filter domain.OrderFilter, after *domain.OrderCursor, limit int) (string, []any) {
+	var (
+		conditions = []string{"deleted_at IS NULL"}
+		args       []any
+	)
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.RecipientID != "" {
+		conditions = append(conditions, "recipient_id = "+arg(filter.RecipientID))
+	}
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, "status = ANY("+arg(pq.Array(filter.Statuses))+")")
+	}
+	if filter.InStorage {
+		conditions = append(conditions, "status = ANY("+arg(pq.Array(domain.InStorageStatuses))+")")
+	}
+	if len(filter.PackagingTypes) > 0 {
+		conditions = append(conditions, "packaging_type = ANY("+arg(pq.Array(filter.PackagingTypes))+")")
+	}
+	if !filter.ExpiryFrom.IsZero() {
+		conditions = append(conditions, "expiry_date >= "+arg(filter.ExpiryFrom))
+	}
+	if !filter.ExpiryTo.IsZero() {
+		conditions = append(conditions, "expiry_date <= "+arg(filter.ExpiryTo))
+	}
+	if filter.OrderIDPrefix != "" {
+		conditions = append(conditions, "order_id LIKE "+arg(escapeLike(filter.OrderIDPrefix)+"%"))
+	}
+	if after != nil {
+		conditions = append(conditions, fmt.Sprintf("(accepted_at, order_id) < (%s, %s)", arg(after.AcceptedAt), arg(after.OrderID)))
+	}
+
+	query := `
+        SELECT ` + orderColumns + `
+        FROM orders
+        WHERE ` + strings.Join(conditions, " AND ")
+	query += " ORDER BY accepted_at DESC, order_id DESC LIMIT " + arg(limit)
+	return query, args
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// warmUpPageSize — сколько заказов WarmUpCache читает за один запрос
+const warmUpPageSize = 500
+
+// WarmUpCache загружает в кэш до limit заказов, которые сейчас хранятся на ПВЗ, начиная с последних
+// принятых. Чтение идёт страницами, чтобы не держать в памяти всю выборку. Страницы читаются с основной
+// базы: отставшая реплика положила бы в кэш устаревшие заказы
+func (r *OrderRepository) WarmUpCache(ctx context.Context, limit int) (int, error) {
+	var after *domain.OrderCursor
+	loaded := 0
+	for loaded < limit {
+		pageSize := min(warmUpPageSize, limit-loaded)
+		query, args := listOrdersQuery(domain.OrderFilter{InStorage: true}, after, pageSize)
+		var page []*domain.Order
+		if err := sqlx.SelectContext(ctx, r.db.writer(ctx), &page, query, args...); err != nil {
+			return loaded, fmt.Errorf("failed to warm up order cache: %w", err)
+		}
+
+		values := make(map[string]*domain.Order, len(page))
+		for _, order := range page {
+			values[order.OrderID] = order
+		}
+		r.cache.SetMany(ctx, values)
+		loaded += len(page)
+
+		if len(page) < pageSize {
+			break
+		}
+		last := page[len(page)-1]
+		after = &domain.OrderCursor{AcceptedAt: last.AcceptedAt, OrderID: last.OrderID}
+	}
+	return loaded, nil
+}
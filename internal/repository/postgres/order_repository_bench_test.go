@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/cache"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Бенчмарки работают с настоящей базой: DSN со схемой после миграций передаётся через BENCH_DATABASE_DSN.
+// Кэш в них пустой, поэтому каждое чтение идёт в базу
+const benchBatchSize = 100
+
+type nopInvalidator struct{}
+
+func (nopInvalidator) Invalidate(ctx context.Context, keys ...string) {}
+
+func newBenchOrderRepository(b *testing.B) *OrderRepository {
+	b.Helper()
+
+	dsn := os.Getenv("BENCH_DATABASE_DSN")
+	if dsn == "" {
+		b.Skip("BENCH_DATABASE_DSN is not set")
+	}
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		b.Fatalf("failed to connect to DB: %v", err)
+	}
+	b.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM orders WHERE order_id LIKE 'bench-%'`)
+		_ = db.Close()
+	})
+	// Без кэша, иначе поштучное чтение не дойдёт до базы
+	return NewOrderRepository(NewCluster(db, nil, ClusterConfig{}), cache.NewLRUCache[string, *domain.Order](1, time.Nanosecond, time.Minute), nopInvalidator{})
+}
+
+func benchOrders(prefix string) []*domain.Order {
+	orders := make([]*domain.Order, benchBatchSize)
+	for i := range orders {
+		orders[i] = &domain.Order{
+			OrderID:       fmt.Sprintf("bench-%s-%d", prefix, i),
+			RecipientID:   "bench-recipient",
+			ExpiryDate:    time.Now().Add(24 * time.Hour).Truncate(24 * time.Hour),
+			Status:        domain.OrderStatusNew,
+			Weight:        5,
+			Cost:          55,
+			PackagingType: domain.PackagingBag,
+			AcceptedAt:    time.Now(),
+			Version:       1,
+		}
+	}
+	return orders
+}
+
+func BenchmarkOrderRepository_AddOrders(b *testing.B) {
+	repo := newBenchOrderRepository(b)
+	ctx := context.Background()
+	prefix := time.Now().Format("150405.000")
+
+	b.Run("per_order", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, order := range benchOrders(fmt.Sprintf("%s-single-%d", prefix, i)) {
+				if err := repo.AddOrder(ctx, order); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(benchBatchSize, "queries/op")
+	})
+
+	b.Run("copy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := repo.AddOrders(ctx, benchOrders(fmt.Sprintf("%s-copy-%d", prefix, i))); err != nil {
+				b.Fatal(err)
+			}
+		}
+		// BEGIN, COPY и COMMIT
+		b.ReportMetric(3, "queries/op")
+	})
+}
+
+func BenchmarkOrderRepository_DeliverOrders(b *testing.B) {
+	repo := newBenchOrderRepository(b)
+	ctx := context.Background()
+
+	orders := benchOrders(time.Now().Format("150405.000"))
+	if err := repo.AddOrders(ctx, orders); err != nil {
+		b.Fatal(err)
+	}
+	orderIDs := make([]string, len(orders))
+	for i, order := range orders {
+		orderIDs[i] = order.OrderID
+	}
+	deliver := func(order *domain.Order) {
+		order.Status = domain.OrderStatusDelivered
+		order.DeliveryDate.Time, order.DeliveryDate.Valid = time.Now(), true
+	}
+
+	b.Run("per_order", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, orderID := range orderIDs {
+				order, err := repo.GetOrder(ctx, orderID)
+				if err != nil {
+					b.Fatal(err)
+				}
+				deliver(order)
+				if err := repo.UpdateOrder(ctx, order); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(2*benchBatchSize, "queries/op")
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			batch, err := repo.GetOrdersByIDs(ctx, orderIDs)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, order := range batch {
+				deliver(order)
+			}
+			if err := repo.UpdateOrders(ctx, batch); err != nil {
+				b.Fatal(err)
+			}
+		}
+		// SELECT, затем BEGIN, UPDATE и COMMIT
+		b.ReportMetric(4, "queries/op")
+	})
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListOrdersQuery(t *testing.T) {
+	expiryFrom := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	expiryTo := time.Date(2024, 10, 31, 0, 0, 0, 0, time.UTC)
+	cursor := &domain.OrderCursor{AcceptedAt: expiryFrom, OrderID: "order5"}
+
+	for _, tc := range []struct {
+		name       string
+		filter     domain.OrderFilter
+		after      *domain.OrderCursor
+		conditions string
+		args       []any
+	}{
+		{
+			name:       "Empty filter",
+			conditions: "deleted_at IS NULL",
+			args:       []any{10},
+		},
+		{
+			name: "All fields",
+			filter: domain.OrderFilter{
+				RecipientID:    "recipient1",
+				Statuses:       []string{domain.OrderStatusNew},
+				PackagingTypes: []string{domain.PackagingBox, domain.PackagingFilm},
+				ExpiryFrom:     expiryFrom,
+				ExpiryTo:       expiryTo,
+				InStorage:      true,
+				OrderIDPrefix:  "A1",
+			},
+			after: cursor,
+			conditions: "deleted_at IS NULL AND recipient_id = $1 AND status = ANY($2) AND status = ANY($3)" +
+				" AND packaging_type = ANY($4) AND expiry_date >= $5 AND expiry_date <= $6 AND order_id LIKE $7" +
+				" AND (accepted_at, order_id) < ($8, $9)",
+			args: []any{
+				"recipient1",
+				pq.Array([]string{domain.OrderStatusNew}),
+				pq.Array(domain.InStorageStatuses),
+				pq.Array([]string{domain.PackagingBox, domain.PackagingFilm}),
+				expiryFrom,
+				expiryTo,
+				"A1%",
+				expiryFrom,
+				"order5",
+				10,
+			},
+		},
+		{
+			name:       "Only upper expiry bound",
+			filter:     domain.OrderFilter{ExpiryTo: expiryTo},
+			conditions: "deleted_at IS NULL AND expiry_date <= $1",
+			args:       []any{expiryTo, 10},
+		},
+		{
+			name:       "Prefix wildcards are escaped",
+			filter:     domain.OrderFilter{OrderIDPrefix: `50%_a\b`},
+			conditions: "deleted_at IS NULL AND order_id LIKE $1",
+			args:       []any{`50\%\_a\\b%`, 10},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			query, args := listOrdersQuery(tc.filter, tc.after, 10)
+
+			where := query[strings.Index(query, "WHERE ")+len("WHERE ") : strings.Index(query, " ORDER BY")]
+			assert.Equal(t, tc.conditions, where)
+			assert.True(t, strings.HasSuffix(query, fmt.Sprintf("LIMIT $%d", len(tc.args))), "limit must be the last argument")
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	for input, expected := range map[string]string{
+		"":        "",
+		"order1":  "order1",
+		"100%":    `100\%`,
+		"a_b":     `a\_b`,
+		`c:\path`: `c:\\path`,
+		`\%_`:     `\\\%\_`,
+	} {
+		assert.Equal(t, expected, escapeLike(input), input)
+	}
+}
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+// pgxDriver — имя драйвера, под которым sqlx.DB работает поверх пула pgx
+const pgxDriver = "pgx"
+
+// PoolConfig задаёт пул подключений pgx. Нулевые значения оставляют настройки pgx по умолчанию
+// или параметры pool_* из DSN
+type PoolConfig struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	// StatementCacheCapacity — сколько подготовленных запросов хранит каждое подключение. 0 отключает кэш,
+	// и запросы выполняются без именованных подготовленных запросов, как нужно за pgbouncer в режиме транзакций
+	StatementCacheCapacity int
+	// QueryTimeout ограничивает каждый запрос, кроме запросов с ctx из WithoutQueryTimeout
+	QueryTimeout time.Duration
+	// DeadlineReserve — насколько раньше дедлайна ctx прерывается запрос, чтобы успеть откатить транзакцию
+	// и вернуть клиенту ошибку
+	DeadlineReserve time.Duration
+}
+
+// Pool — пул подключений pgx и sqlx.DB поверх него, с которым работают репозитории
+type Pool struct {
+	db   *sqlx.DB
+	pool *pgxpool.Pool
+}
+
+// OpenPool создает пул подключений к базе по dsn. Подключения открываются по мере надобности,
+// поэтому недоступная база не мешает созданию пула
+func OpenPool(ctx context.Context, dsn string, config PoolConfig) (*Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database dsn: %w", err)
+	}
+	if config.MaxConns > 0 {
+		poolConfig.MaxConns = config.MaxConns
+	}
+	if config.MinConns > 0 {
+		poolConfig.MinConns = config.MinConns
+	}
+	if config.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = config.MaxConnLifetime
+	}
+	if config.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = config.MaxConnIdleTime
+	}
+	if config.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = config.HealthCheckPeriod
+	}
+
+	poolConfig.ConnConfig.StatementCacheCapacity = config.StatementCacheCapacity
+	if config.StatementCacheCapacity == 0 {
+		poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeExec
+	}
+	if config.QueryTimeout > 0 || config.DeadlineReserve > 0 {
+		poolConfig.ConnConfig.Tracer = &queryTimeoutTracer{timeout: config.QueryTimeout, reserve: config.DeadlineReserve}
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection pool: %w", err)
+	}
+	return &Pool{
+		db:   sqlx.NewDb(stdlib.OpenDBFromPool(pool), pgxDriver),
+		pool: pool,
+	}, nil
+}
+
+func (p *Pool) DB() *sqlx.DB {
+	return p.db
+}
+
+func (p *Pool) Ping(ctx context.Context) error {
+	return p.pool.Ping(ctx)
+}
+
+func (p *Pool) Stats() domain.DBPoolStats {
+	stat := p.pool.Stat()
+	return domain.DBPoolStats{
+		AcquiredConns:           stat.AcquiredConns(),
+		IdleConns:               stat.IdleConns(),
+		TotalConns:              stat.TotalConns(),
+		MaxConns:                stat.MaxConns(),
+		AcquireCount:            stat.AcquireCount(),
+		AcquireDuration:         stat.AcquireDuration(),
+		EmptyAcquireCount:       stat.EmptyAcquireCount(),
+		CanceledAcquireCount:    stat.CanceledAcquireCount(),
+		NewConnsCount:           stat.NewConnsCount(),
+		MaxLifetimeDestroyCount: stat.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyCount:     stat.MaxIdleDestroyCount(),
+	}
+}
+
+// Close закрывает sqlx.DB и ждёт возврата всех подключений в пул
+func (p *Pool) Close() error {
+	err := p.db.Close()
+	p.pool.Close()
+	return err
+}
+
+type (
+	queryCancelKey    struct{}
+	noQueryTimeoutKey struct{}
+)
+
+// WithoutQueryTimeout снимает с запросов в ctx ограничение QueryTimeout. Нужен миграциям и другим долгим
+// служебным операциям, которые ограничиваются только собственным дедлайном ctx
+func WithoutQueryTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noQueryTimeoutKey{}, true)
+}
+
+// queryTimeoutTracer ограничивает запрос и COPY временем, которое осталось до дедлайна ctx. pgx выполняет
+// запрос с контекстом, возвращённым из Trace*Start, и вызывает Trace*End после закрытия строк результата,
+// поэтому таймаут действует, пока читаются строки, и снимается сразу после
+type queryTimeoutTracer struct {
+	timeout time.Duration
+	reserve time.Duration
+}
+
+func (t *queryTimeoutTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return t.start(ctx)
+}
+
+func (t *queryTimeoutTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	t.end(ctx)
+}
+
+func (t *queryTimeoutTracer) TraceCopyFromStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	return t.start(ctx)
+}
+
+func (t *queryTimeoutTracer) TraceCopyFromEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceCopyFromEndData) {
+	t.end(ctx)
+}
+
+func (t *queryTimeoutTracer) start(ctx context.Context) context.Context {
+	deadline, ok := t.deadline(ctx, time.Now())
+	if !ok {
+		return ctx
+	}
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	return context.WithValue(ctx, queryCancelKey{}, cancel)
+}
+
+func (t *queryTimeoutTracer) end(ctx context.Context) {
+	if cancel, ok := ctx.Value(queryCancelKey{}).(context.CancelFunc); ok {
+		cancel()
+	}
+}
+
+// deadline возвращает срок запроса, начатого в now: не позже QueryTimeout и за reserve до дедлайна ctx.
+// Без дедлайна в ctx, например в фоновых задачах, запрос ограничивается только QueryTimeout
+func (t *queryTimeoutTracer) deadline(ctx context.Context, now time.Time) (time.Time, bool) {
+	timeout := t.timeout
+	if ctx.Value(noQueryTimeoutKey{}) != nil {
+		timeout = 0
+	}
+	ctxDeadline, ok := ctx.Deadline()
+	if !ok {
+		if timeout <= 0 {
+			return time.Time{}, false
+		}
+		return now.Add(timeout), true
+	}
+	deadline := ctxDeadline.Add(-t.reserve)
+	if timeout > 0 && now.Add(timeout).Before(deadline) {
+		deadline = now.Add(timeout)
+	}
+	return deadline, true
+}
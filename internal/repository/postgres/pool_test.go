@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTimeoutTracer_Deadline(t *testing.T) {
+	tracer := &queryTimeoutTracer{timeout: time.Second, reserve: 50 * time.Millisecond}
+	now := time.Now()
+
+	deadline, ok := tracer.deadline(context.Background(), now)
+	require.True(t, ok)
+	assert.Equal(t, now.Add(time.Second), deadline, "queries without a deadline in ctx are limited by the query timeout")
+
+	_, ok = tracer.deadline(WithoutQueryTimeout(context.Background()), now)
+	assert.False(t, ok, "queries opted out of the query timeout must not be limited")
+
+	_, ok = (&queryTimeoutTracer{reserve: 50 * time.Millisecond}).deadline(context.Background(), now)
+	assert.False(t, ok, "without a query timeout only the ctx deadline limits queries")
+
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(10*time.Second))
+	defer cancel()
+	deadline, ok = tracer.deadline(ctx, now)
+	require.True(t, ok)
+	assert.Equal(t, now.Add(time.Second), deadline, "a long RPC deadline is capped by the query timeout")
+
+	ctx, cancel = context.WithDeadline(context.Background(), now.Add(300*time.Millisecond))
+	defer cancel()
+	deadline, ok = tracer.deadline(ctx, now)
+	require.True(t, ok)
+	assert.Equal(t, now.Add(250*time.Millisecond), deadline, "the query must end before the RPC deadline")
+
+	deadline, ok = tracer.deadline(WithoutQueryTimeout(ctx), now)
+	require.True(t, ok)
+	assert.Equal(t, now.Add(250*time.Millisecond), deadline, "opting out of the query timeout keeps the ctx deadline")
+}
+
+func TestQueryTimeoutTracer_CancelsOnEnd(t *testing.T) {
+	tracer := &queryTimeoutTracer{timeout: time.Minute}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	queryCtx := tracer.start(ctx)
+	deadline, ok := queryCtx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+
+	tracer.end(queryCtx)
+	assert.ErrorIs(t, queryCtx.Err(), context.Canceled)
+	assert.NoError(t, ctx.Err(), "ending a query must not cancel the RPC context")
+}
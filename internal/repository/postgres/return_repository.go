@@ -10,10 +10,11 @@ import (
 )
 
 type ReturnRepository struct {
-	db *sqlx.DB
+	db *Cluster
 }
 
-func NewReturnRepository(db *sqlx.DB) *ReturnRepository {
+// NewReturnRepository создает репозиторий возвратов. Список возвратов читается с реплик db
+func NewReturnRepository(db *Cluster) *ReturnRepository {
 	return &ReturnRepository{db: db}
 }
 
@@ -22,21 +23,34 @@ func (r *ReturnRepository) AddReturn(ctx context.Context, ret *domain.Return) er
 		`INSERT INTO returns (order_id, recipient_id, return_date)
 	VALUES (:order_id, :recipient_id, :return_date)`
 
-	_, err := r.db.NamedExecContext(ctx, query, ret)
+	_, err := sqlx.NamedExecContext(ctx, r.db.writer(ctx), query, ret)
 	if err != nil {
 		return fmt.Errorf("failed to add return: %w", err)
 	}
+	r.db.markWritten(ctx)
 	return nil
 }
 
-func (r *ReturnRepository) ListReturns(ctx context.Context, offset, limit int) ([]*domain.Return, error) {
+// ListReturns возвращает возвраты от последних к первым, начиная со следующего после after.
+// Если after равен nil, выборка идёт с начала
+func (r *ReturnRepository) ListReturns(ctx context.Context, after *domain.ReturnCursor, limit int) ([]*domain.Return, error) {
 	query :=
 		`SELECT id, order_id, recipient_id, return_date FROM returns
-	ORDER BY return_date DESC
-	OFFSET $1 LIMIT $2`
+	ORDER BY return_date DESC, id DESC
+	LIMIT $1`
+	args := []any{limit}
+	if after != nil {
+		query =
+			`SELECT id, order_id, recipient_id, return_date FROM returns
+	WHERE (return_date, id) < ($2, $3)
+	ORDER BY return_date DESC, id DESC
+	LIMIT $1`
+		args = append(args, after.ReturnDate, after.ID)
+	}
 
 	var returns []*domain.Return
-	err := r.db.SelectContext(ctx, &returns, query, offset, limit)
+	db, _ := r.db.reader(ctx)
+	err := sqlx.SelectContext(ctx, db, &returns, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list returns: %w", err)
 	}
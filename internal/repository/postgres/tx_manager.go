@@ -3,47 +3,270 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type txKey struct{}
 
+// retryableCodes — SQLSTATE ошибок, после которых транзакцию можно безопасно повторить целиком
+var retryableCodes = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+}
+
+// TxConfig задаёт поведение транзакций
+type TxConfig struct {
+	// Isolation — уровень изоляции транзакций, для которых он не задан в opts
+	Isolation sql.IsolationLevel
+	// MaxRetries — сколько раз транзакция повторяется после ошибки сериализации или взаимной блокировки
+	MaxRetries int
+	// RetryBaseDelay и RetryMaxDelay ограничивают экспоненциальную паузу между повторами
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+// ParseIsolationLevel разбирает уровень изоляции в записи SQL, например "repeatable read".
+// Пустая строка означает уровень по умолчанию в postgres
+func ParseIsolationLevel(s string) (sql.IsolationLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return sql.LevelDefault, nil
+	case "read committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	default:
+		return sql.LevelDefault, fmt.Errorf("unknown isolation level %q, expected read committed, repeatable read or serializable", s)
+	}
+}
+
+// txState — транзакция из ctx и то, что нужно сделать после её фиксации
+type txState struct {
+	// conn — подключение транзакции, через которое драйвер pgx выполняет COPY
+	conn       *sqlx.Conn
+	tx         *sqlx.Tx
+	savepoints int
+	// afterCommit выполняются только после успешной фиксации, например обновление кэша
+	afterCommit []func()
+}
+
 type TxManager struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	config  TxConfig
+	metrics interfaces.Metrics
 }
 
-func NewTxManager(db *sqlx.DB) *TxManager {
-	return &TxManager{db: db}
+func NewTxManager(db *sqlx.DB, config TxConfig, metrics interfaces.Metrics) *TxManager {
+	return &TxManager{
+		db:      db,
+		config:  config,
+		metrics: metrics,
+	}
+}
+
+// RunInTransaction выполняет fn в транзакции. Если postgres прерывает транзакцию из-за ошибки сериализации
+// или взаимной блокировки, fn выполняется заново в новой транзакции, поэтому у fn не должно быть
+// побочных эффектов вне базы. Если в ctx уже есть транзакция, fn выполняется в ней под точкой сохранения:
+// ошибка fn откатывает только её изменения, а opts не учитываются
+func (m *TxManager) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error, opts *sql.TxOptions) error {
+	if state := txStateFrom(ctx); state != nil {
+		return m.runNested(ctx, state, fn)
+	}
+
+	txOpts := sql.TxOptions{Isolation: m.config.Isolation}
+	if opts != nil {
+		txOpts.ReadOnly = opts.ReadOnly
+		if opts.Isolation != sql.LevelDefault {
+			txOpts.Isolation = opts.Isolation
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := m.run(ctx, fn, &txOpts)
+		code, retryable := retryableCode(err)
+		if !retryable || attempt >= m.config.MaxRetries {
+			return err
+		}
+		m.metrics.IncTxRetries(code)
+
+		timer := time.NewTimer(m.retryDelay(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		}
+	}
 }
 
-func (m *TxManager) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error, opts *sql.TxOptions) (err error) {
-	tx, err := m.db.BeginTxx(ctx, opts)
+// run выполняет одну попытку транзакции
+func (m *TxManager) run(ctx context.Context, fn func(ctx context.Context) error, opts *sql.TxOptions) (err error) {
+	state, err := beginTx(ctx, m.db, opts)
 	if err != nil {
 		return err
 	}
-	ctx = context.WithValue(ctx, txKey{}, tx)
+	tx := state.tx
+	defer state.conn.Close()
 	defer func() {
 		if p := recover(); p != nil {
 			_ = tx.Rollback()
+			m.metrics.IncTxRollbacks("panic")
 			err = fmt.Errorf("panic occurred during transaction: %v", p)
 			log.Println(err)
-		} else if err != nil {
+			return
+		}
+		if err != nil {
 			_ = tx.Rollback()
-		} else {
-			err = tx.Commit()
+			m.metrics.IncTxRollbacks(rollbackReason(err))
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			m.metrics.IncTxRollbacks(rollbackReason(err))
+			return
+		}
+		for _, hook := range state.afterCommit {
+			hook()
 		}
 	}()
-	err = fn(ctx)
-	return err
+	return fn(context.WithValue(ctx, txKey{}, state))
+}
+
+// runNested выполняет fn под точкой сохранения во внешней транзакции
+func (m *TxManager) runNested(ctx context.Context, state *txState, fn func(ctx context.Context) error) error {
+	state.savepoints++
+	savepoint := fmt.Sprintf("sp_%d", state.savepoints)
+	if _, err := state.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	hooks := len(state.afterCommit)
+	if err := fn(ctx); err != nil {
+		if _, rollbackErr := state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back to savepoint: %w", rollbackErr))
+		}
+		// Действия отменённых изменений не должны выполниться после фиксации внешней транзакции
+		state.afterCommit = state.afterCommit[:hooks]
+		m.metrics.IncTxRollbacks("savepoint")
+		return err
+	}
+
+	if _, err := state.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+// retryDelay возвращает паузу перед повтором: экспоненциальную с ограничением и случайной
+// половиной, чтобы конфликтующие транзакции не повторялись одновременно
+func (m *TxManager) retryDelay(attempt int) time.Duration {
+	// Ограничение сдвига защищает от переполнения при большом числе повторов
+	delay := m.config.RetryBaseDelay << min(attempt, 30)
+	if delay <= 0 || (m.config.RetryMaxDelay > 0 && delay > m.config.RetryMaxDelay) {
+		delay = m.config.RetryMaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func retryableCode(err error) (string, bool) {
+	if code, _, ok := pgError(err); ok && retryableCodes[code] {
+		return code, true
+	}
+	return "", false
+}
+
+func rollbackReason(err error) string {
+	if _, ok := retryableCode(err); ok {
+		return "retryable"
+	}
+	return "error"
 }
 
 func GetTx(ctx context.Context) (*sqlx.Tx, error) {
-	tx, ok := ctx.Value(txKey{}).(*sqlx.Tx)
-	if !ok || tx == nil {
+	state := txStateFrom(ctx)
+	if state == nil {
 		return nil, fmt.Errorf("transaction not found in context")
 	}
-	return tx, nil
+	return state.tx, nil
+}
+
+func txStateFrom(ctx context.Context) *txState {
+	state, _ := ctx.Value(txKey{}).(*txState)
+	return state
+}
+
+// extFrom возвращает транзакцию из ctx, а без неё — само подключение
+func extFrom(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if state := txStateFrom(ctx); state != nil {
+		return state.tx
+	}
+	return db
+}
+
+// afterCommit выполняет fn после фиксации транзакции из ctx, а без транзакции — сразу
+func afterCommit(ctx context.Context, fn func()) {
+	if state := txStateFrom(ctx); state != nil {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+// beginTx начинает транзакцию на отдельном подключении из пула. После завершения транзакции
+// подключение нужно вернуть в пул через state.conn.Close
+func beginTx(ctx context.Context, db *sqlx.DB, opts *sql.TxOptions) (*txState, error) {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := conn.BeginTxx(ctx, opts)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return &txState{conn: conn, tx: tx}, nil
+}
+
+// withTx выполняет fn в транзакции из ctx, а без неё — в отдельной транзакции, чтобы операция
+// из нескольких запросов не оставила частичных изменений. fn получает ctx с этой транзакцией
+func withTx(ctx context.Context, db *sqlx.DB, fn func(ctx context.Context, tx *sqlx.Tx) error) (err error) {
+	if state := txStateFrom(ctx); state != nil {
+		return fn(ctx, state.tx)
+	}
+
+	state, err := beginTx(ctx, db, nil)
+	if err != nil {
+		return err
+	}
+	defer state.conn.Close()
+	defer func() {
+		if p := recover(); p != nil {
+			_ = state.tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			_ = state.tx.Rollback()
+			return
+		}
+		if err = state.tx.Commit(); err != nil {
+			return
+		}
+		for _, hook := range state.afterCommit {
+			hook()
+		}
+	}()
+	return fn(context.WithValue(ctx, txKey{}, state), state.tx)
 }
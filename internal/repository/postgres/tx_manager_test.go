@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIsolationLevel(t *testing.T) {
+	for input, want := range map[string]sql.IsolationLevel{
+		"":                sql.LevelDefault,
+		"read committed":  sql.LevelReadCommitted,
+		"Repeatable Read": sql.LevelRepeatableRead,
+		" serializable ":  sql.LevelSerializable,
+	} {
+		got, err := ParseIsolationLevel(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseIsolationLevel("snapshot")
+	assert.Error(t, err)
+}
+
+func TestRetryableCode(t *testing.T) {
+	code, ok := retryableCode(fmt.Errorf("failed to update order: %w", &pq.Error{Code: "40001"}))
+	assert.True(t, ok)
+	assert.Equal(t, "40001", code)
+
+	_, ok = retryableCode(&pq.Error{Code: "40P01"})
+	assert.True(t, ok)
+	code, ok = retryableCode(fmt.Errorf("failed to update orders: %w", &pgconn.PgError{Code: "40P01"}))
+	assert.True(t, ok)
+	assert.Equal(t, "40P01", code)
+	_, ok = retryableCode(&pq.Error{Code: uniqueViolation})
+	assert.False(t, ok)
+	_, ok = retryableCode(errors.New("connection refused"))
+	assert.False(t, ok)
+}
+
+func TestRetryDelay(t *testing.T) {
+	m := NewTxManager(nil, TxConfig{RetryBaseDelay: 10 * time.Millisecond, RetryMaxDelay: 50 * time.Millisecond}, nil)
+
+	for attempt, ceiling := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond} {
+		for i := 0; i < 100; i++ {
+			delay := m.retryDelay(attempt)
+			assert.GreaterOrEqual(t, delay, ceiling/2, "attempt %d", attempt)
+			assert.LessOrEqual(t, delay, ceiling, "attempt %d", attempt)
+		}
+	}
+	// Сдвиг на большое число попыток не должен переполнить паузу
+	assert.LessOrEqual(t, m.retryDelay(70), 50*time.Millisecond)
+
+	assert.Zero(t, NewTxManager(nil, TxConfig{}, nil).retryDelay(3))
+}
@@ -0,0 +1,401 @@
+// Package repotest — общий набор тестов репозиториев. Хранилища memory и embedded прогоняют его каждое
+// со своим конструктором, чтобы вести себя одинаково
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// AcceptedAt — время приёма тестовых заказов. Наносекунды проверяют, что хранилище не теряет точность
+var AcceptedAt = time.Date(2024, 9, 1, 10, 30, 0, 123456789, time.UTC)
+
+// Backend — репозитории поверх одного хранилища
+type Backend struct {
+	Orders    interfaces.OrderRepository
+	Returns   interfaces.ReturnRepository
+	Archive   interfaces.ArchiveRepository
+	TxManager interfaces.TxManager
+	// ErrReadOnly — ошибка записи в транзакции только для чтения
+	ErrReadOnly error
+}
+
+// NewOrder возвращает заказ в упаковке box со сроком хранения неделя от acceptedAt
+func NewOrder(id, recipientID, status string, acceptedAt time.Time) *domain.Order {
+	return &domain.Order{
+		OrderID:       id,
+		RecipientID:   recipientID,
+		ExpiryDate:    acceptedAt.Add(7 * 24 * time.Hour),
+		Status:        status,
+		Weight:        10,
+		Cost:          110,
+		PackagingType: domain.PackagingBox,
+		AcceptedAt:    acceptedAt,
+	}
+}
+
+// Run прогоняет набор тестов, создавая для каждого пустое хранилище через newBackend
+func Run(t *testing.T, newBackend func(t *testing.T) Backend) {
+	for _, test := range []struct {
+		name string
+		run  func(t *testing.T, b Backend)
+	}{
+		{name: "OrderRepository", run: testOrderRepository},
+		{name: "OrderRepository_ReturnsCopies", run: testOrderRepositoryReturnsCopies},
+		{name: "OrderRepository_ListOrders", run: testOrderRepositoryListOrders},
+		{name: "OrderRepository_Batch", run: testOrderRepositoryBatch},
+		{name: "ReturnRepository", run: testReturnRepository},
+		{name: "ReturnRepository_Paging", run: testReturnRepositoryPaging},
+		{name: "ArchiveRepository", run: testArchiveRepository},
+		{name: "TxManager_RollbackOnError", run: testTxManagerRollbackOnError},
+		{name: "TxManager_RollbackOnPanic", run: testTxManagerRollbackOnPanic},
+		{name: "TxManager_Isolation", run: testTxManagerIsolation},
+		{name: "TxManager_ReadOnly", run: testTxManagerReadOnly},
+		{name: "TxManager_NestedJoinsOuter", run: testTxManagerNestedJoinsOuter},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.run(t, newBackend(t))
+		})
+	}
+}
+
+func testOrderRepository(t *testing.T, b Backend) {
+	ctx := context.Background()
+	order := NewOrder("order1", "recipient1", domain.OrderStatusNew, AcceptedAt)
+
+	err := b.TxManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		require.NoError(t, b.Orders.AddOrder(ctx, order))
+		assert.ErrorIs(t, b.Orders.AddOrder(ctx, order), domain.ErrOrderAlreadyExists)
+
+		gotOrder, err := b.Orders.GetOrder(ctx, "order1")
+		require.NoError(t, err)
+		assert.Equal(t, order, gotOrder)
+
+		order.Status = domain.OrderStatusDelivered
+		require.NoError(t, b.Orders.UpdateOrder(ctx, order))
+
+		gotOrder, err = b.Orders.GetOrder(ctx, "order1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusDelivered, gotOrder.Status)
+
+		require.NoError(t, b.Orders.DeleteOrder(ctx, "order1", "damaged"))
+
+		_, err = b.Orders.GetOrder(ctx, "order1")
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		assert.ErrorIs(t, b.Orders.DeleteOrder(ctx, "order1", "damaged"), domain.ErrOrderNotFound)
+
+		page, err := b.Orders.ListOrdersByRecipient(ctx, "recipient1", nil, 10)
+		require.NoError(t, err)
+		assert.Empty(t, page, "deleted order must be removed from the recipient index")
+		return nil
+	}, nil)
+	require.NoError(t, err)
+}
+
+func testOrderRepositoryReturnsCopies(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	order := NewOrder("order1", "recipient1", domain.OrderStatusNew, AcceptedAt)
+	require.NoError(t, b.Orders.AddOrder(ctx, order))
+	order.Status = domain.OrderStatusReturned
+
+	got, err := b.Orders.GetOrder(ctx, "order1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusNew, got.Status)
+
+	got.Status = domain.OrderStatusDelivered
+	got, err = b.Orders.GetOrder(ctx, "order1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusNew, got.Status)
+}
+
+func testOrderRepositoryListOrders(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		status := domain.OrderStatusNew
+		if i%2 == 1 {
+			status = domain.OrderStatusDelivered
+		}
+		require.NoError(t, b.Orders.AddOrder(ctx, NewOrder(fmt.Sprintf("order%d", i), "recipient1", status, AcceptedAt.Add(time.Duration(i)*time.Hour))))
+	}
+	// Соседние получатели в индексе не должны попадать в выборку
+	require.NoError(t, b.Orders.AddOrder(ctx, NewOrder("other", "recipient", domain.OrderStatusNew, AcceptedAt)))
+	require.NoError(t, b.Orders.AddOrder(ctx, NewOrder("other2", "recipient2", domain.OrderStatusNew, AcceptedAt)))
+
+	page, err := b.Orders.ListOrdersByRecipient(ctx, "recipient1", nil, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "order4", page[0].OrderID)
+	assert.Equal(t, "order3", page[1].OrderID)
+
+	page, err = b.Orders.ListOrdersByRecipient(ctx, "recipient1", &domain.OrderCursor{AcceptedAt: page[1].AcceptedAt, OrderID: page[1].OrderID}, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 3)
+	assert.Equal(t, "order2", page[0].OrderID)
+	assert.Equal(t, "order0", page[2].OrderID)
+
+	inStorage, err := b.Orders.ListOrders(ctx, domain.OrderFilter{RecipientID: "recipient1", InStorage: true}, nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"order4", "order2", "order0"}, orderIDs(inStorage))
+
+	inStorage, err = b.Orders.ListOrders(ctx, domain.OrderFilter{InStorage: true, OrderIDPrefix: "order"}, nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"order4", "order2", "order0"}, orderIDs(inStorage))
+}
+
+func orderIDs(orders []*domain.Order) []string {
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.OrderID)
+	}
+	return ids
+}
+
+func testOrderRepositoryBatch(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	now := AcceptedAt
+	require.NoError(t, b.Orders.AddOrder(ctx, NewOrder("order0", "recipient1", domain.OrderStatusNew, now)))
+
+	batch := []*domain.Order{
+		NewOrder("order1", "recipient1", domain.OrderStatusNew, now),
+		NewOrder("order0", "recipient1", domain.OrderStatusNew, now),
+	}
+	assert.ErrorIs(t, b.Orders.AddOrders(ctx, batch), domain.ErrOrderAlreadyExists)
+	_, err := b.Orders.GetOrder(ctx, "order1")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound, "no order of a conflicting batch must be added")
+
+	require.NoError(t, b.Orders.AddOrders(ctx, []*domain.Order{
+		NewOrder("order1", "recipient1", domain.OrderStatusNew, now),
+		NewOrder("order2", "recipient1", domain.OrderStatusNew, now),
+	}))
+
+	orders, err := b.Orders.GetOrdersByIDs(ctx, []string{"order2", "missing", "order0"})
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	assert.Equal(t, "order2", orders[0].OrderID)
+	assert.Equal(t, "order0", orders[1].OrderID)
+
+	stale := *orders[1]
+	for _, order := range orders {
+		order.Status = domain.OrderStatusDelivered
+	}
+	require.NoError(t, b.Orders.UpdateOrders(ctx, orders))
+	assert.Equal(t, int64(1), orders[0].Version)
+
+	stale.Status = domain.OrderStatusReturned
+	fresh, err := b.Orders.GetOrder(ctx, "order1")
+	require.NoError(t, err)
+	fresh.Status = domain.OrderStatusReturned
+	assert.ErrorIs(t, b.Orders.UpdateOrders(ctx, []*domain.Order{fresh, &stale}), domain.ErrOrderVersionConflict)
+
+	order1, err := b.Orders.GetOrder(ctx, "order1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusNew, order1.Status, "no order of a conflicting batch must be updated")
+}
+
+func testReturnRepository(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	ret := &domain.Return{
+		OrderID:     "order1",
+		RecipientID: "recipient1",
+		ReturnDate:  time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC),
+	}
+
+	err := b.TxManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		require.NoError(t, b.Returns.AddReturn(ctx, ret))
+
+		returns, err := b.Returns.ListReturns(ctx, nil, 10)
+		require.NoError(t, err)
+		require.Len(t, returns, 1)
+		assert.Equal(t, 1, returns[0].ID)
+		assert.Equal(t, ret.OrderID, returns[0].OrderID)
+		assert.Equal(t, ret.ReturnDate, returns[0].ReturnDate)
+		return nil
+	}, nil)
+	require.NoError(t, err)
+}
+
+func testReturnRepositoryPaging(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	day := time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)
+	for _, orderID := range []string{"order1", "order2", "order3"} {
+		require.NoError(t, b.Returns.AddReturn(ctx, &domain.Return{OrderID: orderID, RecipientID: "recipient1", ReturnDate: day}))
+	}
+	require.NoError(t, b.Returns.AddReturn(ctx, &domain.Return{OrderID: "order0", RecipientID: "recipient1", ReturnDate: day.Add(-time.Hour)}))
+
+	page, err := b.Returns.ListReturns(ctx, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "order3", page[0].OrderID)
+	assert.Equal(t, "order2", page[1].OrderID)
+
+	page, err = b.Returns.ListReturns(ctx, &domain.ReturnCursor{ReturnDate: page[1].ReturnDate, ID: page[1].ID}, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "order1", page[0].OrderID)
+	assert.Equal(t, "order0", page[1].OrderID)
+}
+
+func testArchiveRepository(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	now := AcceptedAt
+	for _, order := range []*domain.Order{
+		NewOrder("removed", "recipient1", domain.OrderStatusNew, now),
+		NewOrder("delivered", "recipient1", domain.OrderStatusDelivered, now.Add(time.Minute)),
+		NewOrder("stored", "recipient1", domain.OrderStatusNew, now),
+		NewOrder("returned", "recipient2", domain.OrderStatusReturned, now),
+	} {
+		require.NoError(t, b.Orders.AddOrder(ctx, order))
+	}
+	require.NoError(t, b.Orders.DeleteOrder(ctx, "removed", "damaged"))
+
+	_, err := b.Orders.GetOrder(ctx, "removed")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	assert.ErrorIs(t, b.Orders.AddOrder(ctx, NewOrder("removed", "recipient1", domain.OrderStatusNew, now)), domain.ErrOrderAlreadyExists,
+		"the order ID stays taken until the order is archived")
+
+	archived, err := b.Archive.ArchiveOrders(ctx, time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	assert.Zero(t, archived, "orders changed after closedBefore must stay")
+
+	archived, err = b.Archive.ArchiveOrders(ctx, time.Now().Add(time.Hour), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived)
+	archived, err = b.Archive.ArchiveOrders(ctx, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived)
+
+	_, err = b.Orders.GetOrder(ctx, "delivered")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	_, err = b.Orders.GetOrder(ctx, "stored")
+	require.NoError(t, err)
+
+	removed, err := b.Archive.GetArchivedOrder(ctx, "removed")
+	require.NoError(t, err)
+	assert.True(t, removed.DeletedAt.Valid)
+	assert.Equal(t, "damaged", removed.RemovalReason)
+	assert.False(t, removed.ArchivedAt.IsZero())
+	_, err = b.Archive.GetArchivedOrder(ctx, "stored")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+
+	orders, err := b.Archive.ListArchivedOrders(ctx, "recipient1", nil, 1)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "delivered", orders[0].OrderID)
+	orders, err = b.Archive.ListArchivedOrders(ctx, "", &domain.OrderCursor{AcceptedAt: orders[0].AcceptedAt, OrderID: orders[0].OrderID}, 10)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "removed", orders[0].OrderID)
+
+	require.NoError(t, b.Orders.AddOrder(ctx, NewOrder("removed", "recipient1", domain.OrderStatusNew, now)))
+}
+
+func testTxManagerRollbackOnError(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	errFailed := errors.New("failed")
+	err := b.TxManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		require.NoError(t, b.Orders.AddOrder(ctx, NewOrder("order1", "recipient1", domain.OrderStatusNew, AcceptedAt)))
+		require.NoError(t, b.Returns.AddReturn(ctx, &domain.Return{OrderID: "order1", ReturnDate: AcceptedAt}))
+		return errFailed
+	}, nil)
+	assert.ErrorIs(t, err, errFailed)
+
+	_, err = b.Orders.GetOrder(ctx, "order1")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	orders, err := b.Orders.ListOrdersByRecipient(ctx, "recipient1", nil, 10)
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+
+	// Идентификатор возврата из отменённой транзакции не расходуется
+	require.NoError(t, b.Returns.AddReturn(ctx, &domain.Return{OrderID: "order2", ReturnDate: AcceptedAt}))
+	returns, err := b.Returns.ListReturns(ctx, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, returns, 1)
+	assert.Equal(t, 1, returns[0].ID)
+}
+
+func testTxManagerRollbackOnPanic(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	err := b.TxManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		require.NoError(t, b.Orders.AddOrder(ctx, NewOrder("order1", "recipient1", domain.OrderStatusNew, AcceptedAt)))
+		panic("boom")
+	}, nil)
+	assert.Error(t, err)
+
+	_, err = b.Orders.GetOrder(ctx, "order1")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+
+	// После паники хранилище не остаётся заблокированным
+	require.NoError(t, b.Orders.AddOrder(ctx, NewOrder("order1", "recipient1", domain.OrderStatusNew, AcceptedAt)))
+}
+
+func testTxManagerIsolation(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	inTx := make(chan struct{})
+	commit := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- b.TxManager.RunInTransaction(ctx, func(ctx context.Context) error {
+			if err := b.Orders.AddOrder(ctx, NewOrder("order1", "recipient1", domain.OrderStatusNew, AcceptedAt)); err != nil {
+				return err
+			}
+			close(inTx)
+			<-commit
+			return nil
+		}, nil)
+	}()
+
+	<-inTx
+	_, err := b.Orders.GetOrder(ctx, "order1")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound, "uncommitted writes must not be visible")
+
+	close(commit)
+	require.NoError(t, <-done)
+
+	_, err = b.Orders.GetOrder(ctx, "order1")
+	assert.NoError(t, err)
+}
+
+func testTxManagerReadOnly(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	err := b.TxManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		return b.Orders.AddOrder(ctx, NewOrder("order1", "recipient1", domain.OrderStatusNew, AcceptedAt))
+	}, &sql.TxOptions{ReadOnly: true})
+	assert.ErrorIs(t, err, b.ErrReadOnly)
+}
+
+func testTxManagerNestedJoinsOuter(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	errFailed := errors.New("failed")
+	err := b.TxManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := b.TxManager.RunInTransaction(ctx, func(ctx context.Context) error {
+			return b.Orders.AddOrder(ctx, NewOrder("order1", "recipient1", domain.OrderStatusNew, AcceptedAt))
+		}, nil)
+		require.NoError(t, err)
+		return errFailed
+	}, nil)
+	assert.ErrorIs(t, err, errFailed)
+
+	_, err = b.Orders.GetOrder(ctx, "order1")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+}
@@ -31,21 +31,28 @@ func AddReturn(returnInfo Return) {
 }
 
 func ListReturns() []Return {
-	file, err := os.ReadFile(returnsFile)
+	returns, err := ReadReturns(returnsFile)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []Return{}
-		}
 		log.Fatal(err)
 	}
+	return returns
+}
 
-	var returns []Return
-	err = json.Unmarshal(file, &returns)
+// ReadReturns читает возвраты из файла в формате этого пакета. Отсутствующий файл считается пустым
+func ReadReturns(path string) ([]Return, error) {
+	file, err := os.ReadFile(path)
 	if err != nil {
-		log.Fatal(err)
+		if os.IsNotExist(err) {
+			return []Return{}, nil
+		}
+		return nil, err
 	}
 
-	return returns
+	var returns []Return
+	if err := json.Unmarshal(file, &returns); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return returns, nil
 }
 
 func saveReturns(returns []Return) {
@@ -4,15 +4,20 @@ import (
 	"context"
 
 	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 func (s *OrderServiceServer) AcceptReturn(ctx context.Context, req *order_service.AcceptReturnRequest) (*emptypb.Empty, error) {
-	err := s.ctrl.AcceptReturn(ctx, req.RecipientId, req.OrderId)
+	version, err := expectedVersion(ctx, req.Etag)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to accept return: %v", err)
+		return nil, statusFromError(err, "Failed to accept return")
 	}
+	version, err = s.ctrl.AcceptReturn(ctx, req.RecipientId, req.OrderId, version)
+	if err != nil {
+		return nil, statusFromError(err, "Failed to accept return")
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(etagHeader, formatETag(version)))
 	return &emptypb.Empty{}, nil
 }
@@ -5,8 +5,6 @@ import (
 
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/dto"
 	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -20,7 +18,7 @@ func (s *OrderServiceServer) AddOrder(ctx context.Context, req *order_service.Ad
 	}
 	err := s.ctrl.AddOrder(ctx, dtoReq)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to add order: %v", err)
+		return nil, statusFromError(err, "Failed to add order")
 	}
 	return &emptypb.Empty{}, nil
 }
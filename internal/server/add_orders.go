@@ -0,0 +1,27 @@
+package server
+
+import (
+	"context"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/dto"
+	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (s *OrderServiceServer) AddOrders(ctx context.Context, req *order_service.AddOrdersRequest) (*emptypb.Empty, error) {
+	dtoReqs := make([]*dto.AddOrderDTO, 0, len(req.Orders))
+	for _, order := range req.Orders {
+		dtoReqs = append(dtoReqs, &dto.AddOrderDTO{
+			OrderID:       order.OrderId,
+			RecipientID:   order.RecipientId,
+			ExpiryDate:    order.ExpiryDate,
+			Weight:        order.Weight,
+			PackagingType: order.PackagingType,
+		})
+	}
+	err := s.ctrl.AddOrders(ctx, dtoReqs)
+	if err != nil {
+		return nil, statusFromError(err, "Failed to add orders")
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/dto"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/usecase"
+	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ArchiveAdminServer отдаёт заказы из архива
+type ArchiveAdminServer struct {
+	order_service.UnimplementedArchiveAdminServiceServer
+	archive *usecase.ArchiveUseCase
+}
+
+func NewArchiveAdminServer(archive *usecase.ArchiveUseCase) *ArchiveAdminServer {
+	return &ArchiveAdminServer{archive: archive}
+}
+
+func (s *ArchiveAdminServer) GetArchivedOrder(ctx context.Context, req *order_service.GetArchivedOrderRequest) (*order_service.ArchivedOrder, error) {
+	order, err := s.archive.GetArchivedOrder(ctx, req.OrderId)
+	if err != nil {
+		return nil, statusFromError(err, "Failed to get archived order")
+	}
+	return archivedOrderToProto(order), nil
+}
+
+func (s *ArchiveAdminServer) ListArchivedOrders(ctx context.Context, req *order_service.ListArchivedOrdersRequest) (*order_service.ListArchivedOrdersResponse, error) {
+	orders, nextPageToken, err := s.archive.ListArchivedOrders(ctx, req.RecipientId, int(req.PageSize), req.PageToken)
+	if err != nil {
+		return nil, statusFromError(err, "Failed to list archived orders")
+	}
+
+	orderProtos := make([]*order_service.ArchivedOrder, 0, len(orders))
+	for _, order := range orders {
+		orderProtos = append(orderProtos, archivedOrderToProto(order))
+	}
+
+	return &order_service.ListArchivedOrdersResponse{
+		Orders:        orderProtos,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func archivedOrderToProto(order *dto.ArchivedOrderDTO) *order_service.ArchivedOrder {
+	return &order_service.ArchivedOrder{
+		Order:         orderToProto(&order.OrderDTO),
+		RemovedAt:     timestampToProto(order.RemovedAt),
+		RemovalReason: order.RemovalReason,
+		ArchivedAt:    timestamppb.New(order.ArchivedAt),
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/dto"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CacheAdminServer отдаёт статистику кэша заказов и позволяет посмотреть или сбросить отдельные ключи
+type CacheAdminServer struct {
+	order_service.UnimplementedCacheAdminServiceServer
+	cache interfaces.Cache[string, *domain.Order]
+}
+
+func NewCacheAdminServer(cache interfaces.Cache[string, *domain.Order]) *CacheAdminServer {
+	return &CacheAdminServer{cache: cache}
+}
+
+func (s *CacheAdminServer) GetCacheStats(ctx context.Context, _ *emptypb.Empty) (*order_service.CacheStats, error) {
+	stats := s.cache.Stats()
+
+	return &order_service.CacheStats{
+		Hits:              stats.Hits,
+		Misses:            stats.Misses,
+		HitRate:           stats.HitRate(),
+		CapacityEvictions: stats.CapacityEvictions,
+		ExpiredEvictions:  stats.ExpiredEvictions,
+		Size:              int64(stats.Size),
+		Loads:             stats.Loads,
+		AverageLoadTime:   durationpb.New(stats.AverageLoadTime()),
+		Cost:              stats.Cost,
+	}, nil
+}
+
+func (s *CacheAdminServer) InspectCacheKey(ctx context.Context, req *order_service.InspectCacheKeyRequest) (*order_service.InspectCacheKeyResponse, error) {
+	entry, found := s.cache.Inspect(ctx, req.OrderId)
+	if !found {
+		return &order_service.InspectCacheKeyResponse{}, nil
+	}
+
+	order := entry.Value
+	return &order_service.InspectCacheKeyResponse{
+		Found: true,
+		Order: orderToProto(&dto.OrderDTO{
+			OrderID:       order.OrderID,
+			RecipientID:   order.RecipientID,
+			ExpiryDate:    order.ExpiryDate,
+			Status:        order.Status,
+			DeliveryDate:  order.DeliveryDate,
+			ReturnDate:    order.ReturnDate,
+			Weight:        order.Weight,
+			Cost:          order.Cost,
+			PackagingType: order.PackagingType,
+			AcceptedAt:    order.AcceptedAt,
+			Version:       order.Version,
+			UpdatedAt:     order.UpdatedAt,
+		}),
+		ExpiresAt: timestamppb.New(entry.ExpiresAt),
+	}, nil
+}
+
+func (s *CacheAdminServer) FlushCache(ctx context.Context, req *order_service.FlushCacheRequest) (*emptypb.Empty, error) {
+	if len(req.OrderIds) == 0 {
+		s.cache.Flush(ctx)
+		return &emptypb.Empty{}, nil
+	}
+
+	for _, orderID := range req.OrderIds {
+		s.cache.Delete(ctx, orderID)
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"errors"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func statusFromError(err error, msg string) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, domain.ErrOrderNotFound):
+		code = codes.NotFound
+	case errors.Is(err, domain.ErrOrderAlreadyExists):
+		code = codes.AlreadyExists
+	case errors.Is(err, domain.ErrInvalidInput):
+		code = codes.InvalidArgument
+	case errors.Is(err, domain.ErrPermissionDenied):
+		code = codes.PermissionDenied
+	case errors.Is(err, domain.ErrOrderAlreadyDelivered),
+		errors.Is(err, domain.ErrOrderCannotBeRemoved),
+		errors.Is(err, domain.ErrOrderExpired),
+		errors.Is(err, domain.ErrReturnPeriodExpired),
+		errors.Is(err, domain.ErrOrderNotDelivered),
+		errors.Is(err, domain.ErrWeakETag):
+		code = codes.FailedPrecondition
+	case errors.Is(err, domain.ErrOrderVersionConflict):
+		code = codes.Aborted
+	}
+	return status.Errorf(code, "%s: %v", msg, err)
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// ifMatchHeader — метаданные, в которые HTTP-шлюз кладёт заголовок If-Match
+	ifMatchHeader = "if-match"
+	// etagHeader — метаданные ответа, которые HTTP-шлюз отдаёт заголовком ETag
+	etagHeader = "etag"
+)
+
+func formatETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// expectedVersion возвращает версию заказа из etag запроса, а если он пуст — из заголовка If-Match.
+// If-Match требует строгого сравнения (RFC 7232), поэтому слабый etag с префиксом W/ отклоняется.
+// Список etag через запятую не поддерживается: заказ проверяется по одной ожидаемой версии.
+// 0 означает, что версию проверять не нужно
+func expectedVersion(ctx context.Context, etag string) (int64, error) {
+	if etag == "" {
+		if values := metadata.ValueFromIncomingContext(ctx, ifMatchHeader); len(values) > 0 {
+			etag = values[0]
+		}
+	}
+	etag = strings.TrimSpace(etag)
+	if etag == "" || etag == "*" {
+		return 0, nil
+	}
+
+	if strings.Contains(etag, ",") {
+		return 0, fmt.Errorf("%w: etag lists are not supported, pass a single etag instead of %s", domain.ErrInvalidInput, etag)
+	}
+	if strings.HasPrefix(etag, "W/") {
+		return 0, fmt.Errorf("%w: %s", domain.ErrWeakETag, etag)
+	}
+
+	value := etag
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return 0, fmt.Errorf("%w: malformed etag %s", domain.ErrInvalidInput, etag)
+		}
+		value = unquoted
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("%w: malformed etag %s", domain.ErrInvalidInput, etag)
+	}
+	return version, nil
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestExpectedVersion(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		etag    string
+		ifMatch string
+		version int64
+		wantErr error
+	}{
+		{name: "Empty", etag: "", version: 0},
+		{name: "Whitespace", etag: "  ", version: 0},
+		{name: "Any version", etag: "*", version: 0},
+		{name: "Quoted", etag: `"5"`, version: 5},
+		{name: "Bare", etag: "7", version: 7},
+		{name: "Surrounding whitespace", etag: ` "5" `, version: 5},
+		{name: "If-Match header", ifMatch: `"3"`, version: 3},
+		{name: "Field wins over header", etag: `"4"`, ifMatch: `"3"`, version: 4},
+		{name: "Any version in header", ifMatch: "*", version: 0},
+		{name: "Weak", etag: `W/"5"`, wantErr: domain.ErrWeakETag},
+		{name: "Weak in header", ifMatch: `W/"3"`, wantErr: domain.ErrWeakETag},
+		{name: "List", etag: `"5", "6"`, wantErr: domain.ErrInvalidInput},
+		{name: "List in header", ifMatch: `"3","4"`, wantErr: domain.ErrInvalidInput},
+		{name: "Garbage", etag: "abc", wantErr: domain.ErrInvalidInput},
+		{name: "Unterminated quote", etag: `"5`, wantErr: domain.ErrInvalidInput},
+		{name: "Quoted garbage", etag: `"v5"`, wantErr: domain.ErrInvalidInput},
+		{name: "Lowercase weak prefix", etag: `w/"5"`, wantErr: domain.ErrInvalidInput},
+		{name: "Zero", etag: `"0"`, wantErr: domain.ErrInvalidInput},
+		{name: "Negative", etag: `"-1"`, wantErr: domain.ErrInvalidInput},
+		{name: "Garbage in header", ifMatch: "abc", wantErr: domain.ErrInvalidInput},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.ifMatch != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ifMatchHeader, tc.ifMatch))
+			}
+
+			version, err := expectedVersion(ctx, tc.etag)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.version, version)
+		})
+	}
+}
+
+func TestFormatETagRoundTrip(t *testing.T) {
+	assert.Equal(t, `"42"`, formatETag(42))
+
+	version, err := expectedVersion(context.Background(), formatETag(42))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), version)
+}
+
+func TestGatewayHeaderMatchers(t *testing.T) {
+	for _, tc := range []struct {
+		header string
+		key    string
+	}{
+		{header: "If-Match", key: ifMatchHeader},
+		{header: "Idempotency-Key", key: middleware.IdempotencyKeyHeader},
+		{header: "X-Read-Primary-Until", key: middleware.ReadPrimaryUntilHeader},
+	} {
+		key, ok := gatewayHeaderMatcher(tc.header)
+		assert.True(t, ok, tc.header)
+		assert.Equal(t, tc.key, key, tc.header)
+	}
+
+	for _, tc := range []struct {
+		key    string
+		header string
+	}{
+		{key: etagHeader, header: "ETag"},
+		{key: middleware.ReadPrimaryUntilHeader, header: "X-Read-Primary-Until"},
+		{key: "custom", header: "Grpc-Metadata-custom"},
+	} {
+		header, ok := gatewayOutgoingHeaderMatcher(tc.key)
+		assert.True(t, ok, tc.key)
+		assert.Equal(t, tc.header, header, tc.key)
+	}
+}
+
+func TestStatusFromError(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		code codes.Code
+	}{
+		{err: domain.ErrOrderVersionConflict, code: codes.Aborted},
+		{err: fmt.Errorf("wrapped: %w", domain.ErrOrderVersionConflict), code: codes.Aborted},
+		{err: domain.ErrOrderNotFound, code: codes.NotFound},
+		{err: domain.ErrOrderAlreadyExists, code: codes.AlreadyExists},
+		{err: domain.ErrInvalidInput, code: codes.InvalidArgument},
+		{err: domain.ErrPermissionDenied, code: codes.PermissionDenied},
+		{err: domain.ErrOrderAlreadyDelivered, code: codes.FailedPrecondition},
+		{err: fmt.Errorf("%w: W/\"5\"", domain.ErrWeakETag), code: codes.FailedPrecondition},
+		{err: fmt.Errorf("db is down"), code: codes.Internal},
+	} {
+		err := statusFromError(tc.err, "Failed")
+		assert.Equal(t, tc.code, status.Code(err), tc.err.Error())
+	}
+}
@@ -4,24 +4,21 @@ import (
 	"context"
 
 	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 func (s *OrderServiceServer) GetOrders(ctx context.Context, req *order_service.GetOrdersRequest) (*order_service.GetOrdersResponse, error) {
-	orders, err := s.ctrl.GetOrders(ctx, req.RecipientId, int(req.LastN))
+	orders, nextPageToken, err := s.ctrl.GetOrders(ctx, req.RecipientId, int(req.PageSize), req.PageToken)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to get orders: %v", err)
+		return nil, statusFromError(err, "Failed to get orders")
 	}
 
 	var orderProtos []*order_service.Order
 	for _, order := range orders {
-		orderProtos = append(orderProtos, &order_service.Order{
-			OrderId:      order.OrderID,
-			Status:       order.Status,
-			DeliveryDate: order.DeliveryDate,
-		})
+		orderProtos = append(orderProtos, orderToProto(order))
 	}
 
-	return &order_service.GetOrdersResponse{Orders: orderProtos}, nil
+	return &order_service.GetOrdersResponse{
+		Orders:        orderProtos,
+		NextPageToken: nextPageToken,
+	}, nil
 }
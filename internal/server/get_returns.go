@@ -4,14 +4,12 @@ import (
 	"context"
 
 	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 func (s *OrderServiceServer) GetReturns(ctx context.Context, req *order_service.GetReturnsRequest) (*order_service.GetReturnsResponse, error) {
-	returns, err := s.ctrl.GetReturns(ctx, int(req.Page))
+	returns, nextPageToken, err := s.ctrl.GetReturns(ctx, int(req.PageSize), req.PageToken)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to get returns: %v", err)
+		return nil, statusFromError(err, "Failed to get returns")
 	}
 
 	var returnProtos []*order_service.Return
@@ -22,5 +20,8 @@ func (s *OrderServiceServer) GetReturns(ctx context.Context, req *order_service.
 		})
 	}
 
-	return &order_service.GetReturnsResponse{Returns: returnProtos}, nil
+	return &order_service.GetReturnsResponse{
+		Returns:       returnProtos,
+		NextPageToken: nextPageToken,
+	}, nil
 }
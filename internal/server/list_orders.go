@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
+)
+
+func (s *OrderServiceServer) ListOrders(ctx context.Context, req *order_service.ListOrdersRequest) (*order_service.ListOrdersResponse, error) {
+	orders, nextPageToken, err := s.ctrl.ListOrders(ctx, orderFilterFromProto(req.GetFilter()), int(req.PageSize), req.PageToken)
+	if err != nil {
+		return nil, statusFromError(err, "Failed to list orders")
+	}
+
+	var orderProtos []*order_service.Order
+	for _, order := range orders {
+		orderProtos = append(orderProtos, orderToProto(order))
+	}
+
+	return &order_service.ListOrdersResponse{
+		Orders:        orderProtos,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func orderFilterFromProto(filter *order_service.OrderFilter) domain.OrderFilter {
+	if filter == nil {
+		return domain.OrderFilter{}
+	}
+
+	result := domain.OrderFilter{
+		RecipientID:   filter.RecipientId,
+		InStorage:     filter.InStorage,
+		OrderIDPrefix: filter.OrderIdPrefix,
+		ExpiryFrom:    dateFromProto(filter.ExpiryFrom),
+		ExpiryTo:      dateFromProto(filter.ExpiryTo),
+	}
+	for _, status := range filter.Statuses {
+		result.Statuses = append(result.Statuses, orderStatusFromProto(status))
+	}
+	for _, packagingType := range filter.PackagingTypes {
+		result.PackagingTypes = append(result.PackagingTypes, packagingTypeFromProto(packagingType))
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/type/date"
+)
+
+func TestOrderFilterFromProto(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		filter   *order_service.OrderFilter
+		expected domain.OrderFilter
+	}{
+		{
+			name:     "Nil filter",
+			expected: domain.OrderFilter{},
+		},
+		{
+			name:     "Empty filter",
+			filter:   &order_service.OrderFilter{},
+			expected: domain.OrderFilter{},
+		},
+		{
+			name: "All fields",
+			filter: &order_service.OrderFilter{
+				RecipientId: "recipient1",
+				Statuses: []order_service.OrderStatus{
+					order_service.OrderStatus_ORDER_STATUS_NEW,
+					order_service.OrderStatus_ORDER_STATUS_RETURNED,
+				},
+				PackagingTypes: []order_service.PackagingType{order_service.PackagingType_PACKAGING_TYPE_FILM},
+				ExpiryFrom:     &date.Date{Year: 2024, Month: 10, Day: 1},
+				ExpiryTo:       &date.Date{Year: 2024, Month: 10, Day: 31},
+				InStorage:      true,
+				OrderIdPrefix:  "A1",
+			},
+			expected: domain.OrderFilter{
+				RecipientID:    "recipient1",
+				Statuses:       []string{domain.OrderStatusNew, domain.OrderStatusReturned},
+				PackagingTypes: []string{domain.PackagingFilm},
+				ExpiryFrom:     time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+				ExpiryTo:       time.Date(2024, 10, 31, 0, 0, 0, 0, time.UTC),
+				InStorage:      true,
+				OrderIDPrefix:  "A1",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, orderFilterFromProto(tc.filter))
+		})
+	}
+}
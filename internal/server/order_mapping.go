@@ -0,0 +1,137 @@
+package server
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/dto"
+	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
+	"google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const currencyCode = "RUB"
+
+func orderToProto(order *dto.OrderDTO) *order_service.Order {
+	return &order_service.Order{
+		OrderId:       order.OrderID,
+		RecipientId:   order.RecipientID,
+		Status:        orderStatusToProto(order.Status),
+		PackagingType: packagingTypeToProto(order.PackagingType),
+		Weight:        order.Weight,
+		Cost:          moneyToProto(order.Cost),
+		ExpiryDate: &date.Date{
+			Year:  int32(order.ExpiryDate.Year()),
+			Month: int32(order.ExpiryDate.Month()),
+			Day:   int32(order.ExpiryDate.Day()),
+		},
+		DeliveredAt: timestampToProto(order.DeliveryDate),
+		ReturnedAt:  timestampToProto(order.ReturnDate),
+		AcceptedAt:  timestamppb.New(order.AcceptedAt),
+		Etag:        formatETag(order.Version),
+		UpdatedAt:   timestamppb.New(order.UpdatedAt),
+	}
+}
+
+func orderStatusToProto(status string) order_service.OrderStatus {
+	switch status {
+	case domain.OrderStatusNew:
+		return order_service.OrderStatus_ORDER_STATUS_NEW
+	case domain.OrderStatusDelivered:
+		return order_service.OrderStatus_ORDER_STATUS_DELIVERED
+	case domain.OrderStatusReturned:
+		return order_service.OrderStatus_ORDER_STATUS_RETURNED
+	default:
+		return order_service.OrderStatus_ORDER_STATUS_UNSPECIFIED
+	}
+}
+
+func packagingTypeToProto(packagingType string) order_service.PackagingType {
+	switch packagingType {
+	case domain.PackagingBag:
+		return order_service.PackagingType_PACKAGING_TYPE_BAG
+	case domain.PackagingBox:
+		return order_service.PackagingType_PACKAGING_TYPE_BOX
+	case domain.PackagingFilm:
+		return order_service.PackagingType_PACKAGING_TYPE_FILM
+	default:
+		return order_service.PackagingType_PACKAGING_TYPE_UNSPECIFIED
+	}
+}
+
+func orderStatusFromProto(status order_service.OrderStatus) string {
+	switch status {
+	case order_service.OrderStatus_ORDER_STATUS_NEW:
+		return domain.OrderStatusNew
+	case order_service.OrderStatus_ORDER_STATUS_DELIVERED:
+		return domain.OrderStatusDelivered
+	case order_service.OrderStatus_ORDER_STATUS_RETURNED:
+		return domain.OrderStatusReturned
+	default:
+		return ""
+	}
+}
+
+func packagingTypeFromProto(packagingType order_service.PackagingType) string {
+	switch packagingType {
+	case order_service.PackagingType_PACKAGING_TYPE_BAG:
+		return domain.PackagingBag
+	case order_service.PackagingType_PACKAGING_TYPE_BOX:
+		return domain.PackagingBox
+	case order_service.PackagingType_PACKAGING_TYPE_FILM:
+		return domain.PackagingFilm
+	default:
+		return ""
+	}
+}
+
+// moneyToProto округляет стоимость до копеек, чтобы не переносить погрешность float32 в nanos
+func moneyToProto(amount float32) *money.Money {
+	kopecks := toKopecks(amount)
+	return &money.Money{
+		CurrencyCode: currencyCode,
+		Units:        kopecks / 100,
+		Nanos:        int32(kopecks%100) * 10_000_000,
+	}
+}
+
+// toKopecks округляет сумму до копеек, половину — от нуля. Округляется кратчайшая десятичная запись float32,
+// то есть введённая стоимость: двоичное значение 0.005 чуть меньше 0.005 и округлилось бы вниз
+func toKopecks(amount float32) int64 {
+	decimal := strconv.FormatFloat(float64(amount), 'f', -1, 32)
+	negative := strings.HasPrefix(decimal, "-")
+	units, fraction, _ := strings.Cut(strings.TrimPrefix(decimal, "-"), ".")
+	fraction += "000"
+
+	whole, err := strconv.ParseInt(units, 10, 64)
+	if err != nil {
+		return 0
+	}
+	kopecks := whole*100 + int64(fraction[0]-'0')*10 + int64(fraction[1]-'0')
+	if fraction[2] >= '5' {
+		kopecks++
+	}
+	if negative {
+		kopecks = -kopecks
+	}
+	return kopecks
+}
+
+// dateFromProto возвращает нулевое время для пустой даты, как и для отсутствующей
+func dateFromProto(d *date.Date) time.Time {
+	if d == nil || (d.Year == 0 && d.Month == 0 && d.Day == 0) {
+		return time.Time{}
+	}
+	return time.Date(int(d.Year), time.Month(d.Month), int(d.Day), 0, 0, 0, 0, time.UTC)
+}
+
+func timestampToProto(t sql.NullTime) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
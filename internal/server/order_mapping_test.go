@@ -0,0 +1,151 @@
+package server
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/dto"
+	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestOrderToProto(t *testing.T) {
+	acceptedAt := time.Date(2024, 9, 1, 10, 0, 0, 0, time.UTC)
+	deliveredAt := time.Date(2024, 9, 5, 12, 30, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name     string
+		order    *dto.OrderDTO
+		expected *order_service.Order
+	}{
+		{
+			name: "Delivered order",
+			order: &dto.OrderDTO{
+				OrderID:       "order1",
+				RecipientID:   "recipient1",
+				ExpiryDate:    time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+				Status:        domain.OrderStatusDelivered,
+				DeliveryDate:  sql.NullTime{Time: deliveredAt, Valid: true},
+				Weight:        2.5,
+				Cost:          120.45,
+				PackagingType: domain.PackagingBox,
+				AcceptedAt:    acceptedAt,
+				Version:       3,
+				UpdatedAt:     deliveredAt,
+			},
+			expected: &order_service.Order{
+				OrderId:       "order1",
+				RecipientId:   "recipient1",
+				Status:        order_service.OrderStatus_ORDER_STATUS_DELIVERED,
+				PackagingType: order_service.PackagingType_PACKAGING_TYPE_BOX,
+				Weight:        2.5,
+				Cost:          &money.Money{CurrencyCode: "RUB", Units: 120, Nanos: 450_000_000},
+				ExpiryDate:    &date.Date{Year: 2024, Month: 10, Day: 1},
+				DeliveredAt:   timestamppb.New(deliveredAt),
+				AcceptedAt:    timestamppb.New(acceptedAt),
+				Etag:          `"3"`,
+				UpdatedAt:     timestamppb.New(deliveredAt),
+			},
+		},
+		{
+			name: "Unknown status and packaging",
+			order: &dto.OrderDTO{
+				OrderID:       "order2",
+				RecipientID:   "recipient1",
+				ExpiryDate:    time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+				Status:        "lost",
+				PackagingType: "crate",
+				AcceptedAt:    acceptedAt,
+				Version:       1,
+				UpdatedAt:     acceptedAt,
+			},
+			expected: &order_service.Order{
+				OrderId:       "order2",
+				RecipientId:   "recipient1",
+				Status:        order_service.OrderStatus_ORDER_STATUS_UNSPECIFIED,
+				PackagingType: order_service.PackagingType_PACKAGING_TYPE_UNSPECIFIED,
+				Cost:          &money.Money{CurrencyCode: "RUB"},
+				ExpiryDate:    &date.Date{Year: 2024, Month: 10, Day: 1},
+				AcceptedAt:    timestamppb.New(acceptedAt),
+				Etag:          `"1"`,
+				UpdatedAt:     timestamppb.New(acceptedAt),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := orderToProto(tc.order)
+			assert.True(t, proto.Equal(tc.expected, got), "expected %v, got %v", tc.expected, got)
+		})
+	}
+}
+
+func TestMoneyToProto(t *testing.T) {
+	for _, tc := range []struct {
+		amount float32
+		units  int64
+		nanos  int32
+	}{
+		{amount: 0, units: 0, nanos: 0},
+		{amount: 100, units: 100, nanos: 0},
+		{amount: 120.45, units: 120, nanos: 450_000_000},
+		{amount: 0.1, units: 0, nanos: 100_000_000},
+		{amount: 0.005, units: 0, nanos: 10_000_000},
+		{amount: 0.004, units: 0, nanos: 0},
+		{amount: 1.005, units: 1, nanos: 10_000_000},
+		{amount: 0.995, units: 1, nanos: 0},
+		{amount: 19.999, units: 20, nanos: 0},
+		{amount: -0.005, units: 0, nanos: -10_000_000},
+		{amount: -1.5, units: -1, nanos: -500_000_000},
+		{amount: -120.45, units: -120, nanos: -450_000_000},
+	} {
+		got := moneyToProto(tc.amount)
+		assert.Equal(t, "RUB", got.CurrencyCode)
+		assert.Equal(t, tc.units, got.Units, "units of %v", tc.amount)
+		assert.Equal(t, tc.nanos, got.Nanos, "nanos of %v", tc.amount)
+	}
+}
+
+func TestDateFromProto(t *testing.T) {
+	assert.True(t, dateFromProto(nil).IsZero())
+	assert.True(t, dateFromProto(&date.Date{}).IsZero())
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), dateFromProto(&date.Date{Year: 2024, Month: 2, Day: 29}))
+}
+
+func TestOrderStatusMapping(t *testing.T) {
+	for status, protoStatus := range map[string]order_service.OrderStatus{
+		domain.OrderStatusNew:       order_service.OrderStatus_ORDER_STATUS_NEW,
+		domain.OrderStatusDelivered: order_service.OrderStatus_ORDER_STATUS_DELIVERED,
+		domain.OrderStatusReturned:  order_service.OrderStatus_ORDER_STATUS_RETURNED,
+	} {
+		assert.Equal(t, protoStatus, orderStatusToProto(status))
+		assert.Equal(t, status, orderStatusFromProto(protoStatus))
+	}
+
+	assert.Equal(t, order_service.OrderStatus_ORDER_STATUS_UNSPECIFIED, orderStatusToProto(""))
+	assert.Equal(t, order_service.OrderStatus_ORDER_STATUS_UNSPECIFIED, orderStatusToProto("lost"))
+	assert.Empty(t, orderStatusFromProto(order_service.OrderStatus_ORDER_STATUS_UNSPECIFIED))
+	assert.Empty(t, orderStatusFromProto(order_service.OrderStatus(99)))
+}
+
+func TestPackagingTypeMapping(t *testing.T) {
+	for packagingType, protoType := range map[string]order_service.PackagingType{
+		domain.PackagingBag:  order_service.PackagingType_PACKAGING_TYPE_BAG,
+		domain.PackagingBox:  order_service.PackagingType_PACKAGING_TYPE_BOX,
+		domain.PackagingFilm: order_service.PackagingType_PACKAGING_TYPE_FILM,
+	} {
+		assert.Equal(t, protoType, packagingTypeToProto(packagingType))
+		assert.Equal(t, packagingType, packagingTypeFromProto(protoType))
+	}
+
+	assert.Equal(t, order_service.PackagingType_PACKAGING_TYPE_UNSPECIFIED, packagingTypeToProto(""))
+	assert.Equal(t, order_service.PackagingType_PACKAGING_TYPE_UNSPECIFIED, packagingTypeToProto("crate"))
+	assert.Empty(t, packagingTypeFromProto(order_service.PackagingType_PACKAGING_TYPE_UNSPECIFIED))
+	assert.Empty(t, packagingTypeFromProto(order_service.PackagingType(99)))
+}
@@ -2,16 +2,22 @@ package server
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/bufbuild/protovalidate-go"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/controller"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
 	"gitlab.ozon.dev/ashadkhamov/homework/internal/middleware"
 	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 type OrderServiceServer struct {
@@ -23,11 +29,33 @@ func NewOrderServiceServer(ctrl *controller.OrderController) *OrderServiceServer
 	return &OrderServiceServer{ctrl: ctrl}
 }
 
-func RunGRPCServer(ctx context.Context, address string, server *OrderServiceServer, metrics interfaces.Metrics) error {
+func RunGRPCServer(ctx context.Context, address string, server *OrderServiceServer, cacheAdmin *CacheAdminServer, archiveAdmin *ArchiveAdminServer, metrics interfaces.Metrics, idempotency interfaces.IdempotencyRepository, stickyWindow time.Duration) error {
+	validator, err := protovalidate.New()
+	if err != nil {
+		return err
+	}
+
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(middleware.LoggingInterceptor(server.ctrl.Producer, "order_service")), // Используем Producer
+		grpc.ChainUnaryInterceptor(
+			middleware.LoggingInterceptor(server.ctrl.Producer, "order_service"), // Используем Producer
+			middleware.ValidationInterceptor(validator),
+			// Идемпотентность оборачивает сессию чтения, чтобы сохранить и при повторе вернуть её заголовок
+			middleware.IdempotencyInterceptor(idempotency,
+				order_service.OrderService_AddOrder_FullMethodName,
+				order_service.OrderService_AddOrders_FullMethodName,
+				order_service.OrderService_DeliverOrders_FullMethodName,
+				order_service.OrderService_AcceptReturn_FullMethodName,
+				order_service.OrderService_RemoveOrder_FullMethodName,
+			),
+			middleware.ReadSessionInterceptor(stickyWindow),
+		),
 	)
 	order_service.RegisterOrderServiceServer(grpcServer, server)
+	// Без кэша заказов, например в режимах memory и embedded, служебный сервис кэша не регистрируется
+	if cacheAdmin != nil {
+		order_service.RegisterCacheAdminServiceServer(grpcServer, cacheAdmin)
+	}
+	order_service.RegisterArchiveAdminServiceServer(grpcServer, archiveAdmin)
 
 	lis, err := net.Listen("tcp", address)
 	if err != nil {
@@ -43,3 +71,53 @@ func RunGRPCServer(ctx context.Context, address string, server *OrderServiceServ
 
 	return grpcServer.Serve(lis)
 }
+
+// RunHTTPGateway поднимает REST-прокси, который транслирует HTTP-запросы в gRPC-сервер по адресу grpcAddress
+func RunHTTPGateway(ctx context.Context, address, grpcAddress string) error {
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(gatewayHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(gatewayOutgoingHeaderMatcher),
+	)
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if err := order_service.RegisterOrderServiceHandlerFromEndpoint(ctx, mux, grpcAddress, opts); err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{
+		Addr:    address,
+		Handler: mux,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = httpServer.Shutdown(context.Background())
+	}()
+
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func gatewayHeaderMatcher(key string) (string, bool) {
+	switch {
+	case strings.EqualFold(key, middleware.IdempotencyKeyHeader):
+		return middleware.IdempotencyKeyHeader, true
+	case strings.EqualFold(key, ifMatchHeader):
+		return ifMatchHeader, true
+	case strings.EqualFold(key, middleware.ReadPrimaryUntilHeader):
+		return middleware.ReadPrimaryUntilHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// gatewayOutgoingHeaderMatcher отдаёт etag заказа стандартным заголовком ETag, а срок чтения с основной базы —
+// под тем же именем, под которым шлюз принимает его обратно. Остальные метаданные отдаются как по умолчанию
+func gatewayOutgoingHeaderMatcher(key string) (string, bool) {
+	switch key {
+	case etagHeader:
+		return "ETag", true
+	case middleware.ReadPrimaryUntilHeader:
+		return "X-Read-Primary-Until", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
@@ -4,15 +4,16 @@ import (
 	"context"
 
 	order_service "gitlab.ozon.dev/ashadkhamov/homework/pkg/order_service/v1"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 func (s *OrderServiceServer) RemoveOrder(ctx context.Context, req *order_service.RemoveOrderRequest) (*emptypb.Empty, error) {
-	err := s.ctrl.RemoveOrder(ctx, req.OrderId)
+	version, err := expectedVersion(ctx, req.Etag)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to remove order: %v", err)
+		return nil, statusFromError(err, "Failed to remove order")
+	}
+	if err := s.ctrl.RemoveOrder(ctx, req.OrderId, version, req.Reason); err != nil {
+		return nil, statusFromError(err, "Failed to remove order")
 	}
 	return &emptypb.Empty{}, nil
 }
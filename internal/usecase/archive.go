@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/dto"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+)
+
+// ArchiveUseCase переносит закрытые заказы в архив и отдаёт архив администраторам
+type ArchiveUseCase struct {
+	archiveRepo interfaces.ArchiveRepository
+	// retention — сколько закрытый заказ хранится среди действующих после последнего изменения
+	retention time.Duration
+	batchSize int
+}
+
+// NewArchiveUseCase проверяет настройки переноса: retention должен быть больше domain.ReturnWindow,
+// иначе выданный заказ уйдёт в архив раньше, чем его смогут вернуть, а batchSize — положительным
+func NewArchiveUseCase(archiveRepo interfaces.ArchiveRepository, retention time.Duration, batchSize int) (*ArchiveUseCase, error) {
+	if retention <= domain.ReturnWindow {
+		return nil, fmt.Errorf("archive retention %s must be longer than the return window %s", retention, domain.ReturnWindow)
+	}
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("archive batch size must be positive, got %d", batchSize)
+	}
+	return &ArchiveUseCase{
+		archiveRepo: archiveRepo,
+		retention:   retention,
+		batchSize:   batchSize,
+	}, nil
+}
+
+// ArchiveClosedOrders переносит в архив все закрытые заказы старше срока хранения пачками по batchSize,
+// каждая в своей транзакции, и возвращает число перенесённых заказов
+func (uc *ArchiveUseCase) ArchiveClosedOrders(ctx context.Context) (int, error) {
+	closedBefore := time.Now().Add(-uc.retention)
+	total := 0
+	for {
+		archived, err := uc.archiveRepo.ArchiveOrders(ctx, closedBefore, uc.batchSize)
+		total += archived
+		if err != nil {
+			return total, err
+		}
+		if archived < uc.batchSize {
+			return total, nil
+		}
+	}
+}
+
+// Run запускает перенос сразу и затем каждые interval, пока не отменён ctx
+func (uc *ArchiveUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		archived, err := uc.ArchiveClosedOrders(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to archive closed orders: %v", err)
+		}
+		if archived > 0 {
+			log.Printf("Archived %d closed orders", archived)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *ArchiveUseCase) GetArchivedOrder(ctx context.Context, orderID string) (*dto.ArchivedOrderDTO, error) {
+	order, err := uc.archiveRepo.GetArchivedOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return archivedOrderToDTO(order), nil
+}
+
+// ListArchivedOrders возвращает страницу архива от последних принятых заказов к первым.
+// Пустой recipientID означает заказы всех получателей
+func (uc *ArchiveUseCase) ListArchivedOrders(ctx context.Context, recipientID string, pageSize int, pageToken string) ([]*dto.ArchivedOrderDTO, string, error) {
+	pageSize = normalizePageSize(pageSize)
+
+	var after *domain.OrderCursor
+	if pageToken != "" {
+		after = &domain.OrderCursor{}
+		if err := decodePageToken(pageToken, after); err != nil {
+			return nil, "", err
+		}
+	}
+
+	orders, err := uc.archiveRepo.ListArchivedOrders(ctx, recipientID, after, pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextPageToken string
+	if len(orders) > pageSize {
+		orders = orders[:pageSize]
+		last := orders[len(orders)-1]
+		nextPageToken, err = encodePageToken(domain.OrderCursor{AcceptedAt: last.AcceptedAt, OrderID: last.OrderID})
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	orderDTOs := make([]*dto.ArchivedOrderDTO, 0, len(orders))
+	for _, order := range orders {
+		orderDTOs = append(orderDTOs, archivedOrderToDTO(order))
+	}
+	return orderDTOs, nextPageToken, nil
+}
+
+func archivedOrderToDTO(order *domain.ArchivedOrder) *dto.ArchivedOrderDTO {
+	return &dto.ArchivedOrderDTO{
+		OrderDTO:      *orderToDTO(&order.Order),
+		RemovedAt:     order.DeletedAt,
+		RemovalReason: order.RemovalReason,
+		ArchivedAt:    order.ArchivedAt,
+	}
+}
@@ -0,0 +1,101 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/memory"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shiftedArchiveRepository переносит заказы так, будто с их изменения прошло shift
+type shiftedArchiveRepository struct {
+	interfaces.ArchiveRepository
+	shift time.Duration
+}
+
+func (r *shiftedArchiveRepository) ArchiveOrders(ctx context.Context, closedBefore time.Time, limit int) (int, error) {
+	return r.ArchiveRepository.ArchiveOrders(ctx, closedBefore.Add(r.shift), limit)
+}
+
+func TestNewArchiveUseCase_RejectsUnsafeSettings(t *testing.T) {
+	repo := memory.NewArchiveRepository(memory.NewStore())
+
+	for _, tc := range []struct {
+		name      string
+		retention time.Duration
+		batchSize int
+	}{
+		{name: "Negative retention", retention: -time.Hour, batchSize: 10},
+		{name: "Retention within return window", retention: domain.ReturnWindow, batchSize: 10},
+		{name: "Zero batch size", retention: domain.ReturnWindow + time.Hour, batchSize: 0},
+		{name: "Negative batch size", retention: domain.ReturnWindow + time.Hour, batchSize: -1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := usecase.NewArchiveUseCase(repo, tc.retention, tc.batchSize)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestArchiveUseCase(t *testing.T) {
+	store := memory.NewStore()
+	uc := usecase.NewOrderUseCase(memory.NewOrderRepository(store), memory.NewReturnRepository(store), memory.NewTxManager(store), &stubMetrics{})
+	ctx := context.Background()
+
+	for _, orderID := range []string{"order1", "order2", "order3", "order4"} {
+		require.NoError(t, uc.AddOrder(ctx, addOrderRequest(orderID, "recipient1")))
+	}
+	require.NoError(t, uc.DeliverOrders(ctx, "recipient1", []string{"order1", "order2"}))
+	require.NoError(t, uc.RemoveOrder(ctx, "order3", 0, "expired"))
+
+	retention := domain.ReturnWindow + time.Hour
+
+	// Свежие заказы остаются среди действующих
+	archive, err := usecase.NewArchiveUseCase(memory.NewArchiveRepository(store), retention, 1)
+	require.NoError(t, err)
+	archived, err := archive.ArchiveClosedOrders(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, archived)
+
+	// Сдвиг на срок хранения делает закрытыми уже изменённые заказы, а пачка из одного заказа
+	// проверяет, что перенос идёт, пока пачки заполнены
+	archive, err = usecase.NewArchiveUseCase(&shiftedArchiveRepository{ArchiveRepository: memory.NewArchiveRepository(store), shift: retention}, retention, 1)
+	require.NoError(t, err)
+	archived, err = archive.ArchiveClosedOrders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, archived)
+
+	orders, _, err := uc.GetOrders(ctx, "recipient1", 10, "")
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "order4", orders[0].OrderID)
+
+	removed, err := archive.GetArchivedOrder(ctx, "order3")
+	require.NoError(t, err)
+	assert.Equal(t, "expired", removed.RemovalReason)
+	assert.True(t, removed.RemovedAt.Valid)
+	_, err = archive.GetArchivedOrder(ctx, "order4")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+
+	var seen []string
+	pageToken := ""
+	for {
+		page, next, err := archive.ListArchivedOrders(ctx, "recipient1", 2, pageToken)
+		require.NoError(t, err)
+		for _, order := range page {
+			seen = append(seen, order.OrderID)
+		}
+		if next == "" {
+			break
+		}
+		pageToken = next
+	}
+	assert.ElementsMatch(t, []string{"order1", "order2", "order3"}, seen)
+}
@@ -4,15 +4,15 @@ package mocks
 
 import (
 	"context"
-	"homework-1/internal/domain"
 	"sync"
 	mm_atomic "sync/atomic"
 	mm_time "time"
 
 	"github.com/gojuno/minimock/v3"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
 )
 
-// OrderRepositoryMock implements mm_repository.OrderRepository
+// OrderRepositoryMock implements mm_interfaces.OrderRepository
 type OrderRepositoryMock struct {
 	t          minimock.Tester
 	finishOnce sync.Once
@@ -24,9 +24,16 @@ type OrderRepositoryMock struct {
 	beforeAddOrderCounter uint64
 	AddOrderMock          mOrderRepositoryMockAddOrder
 
-	funcDeleteOrder          func(ctx context.Context, orderID string) (err error)
+	funcAddOrders          func(ctx context.Context, orders []*domain.Order) (err error)
+	funcAddOrdersOrigin    string
+	inspectFuncAddOrders   func(ctx context.Context, orders []*domain.Order)
+	afterAddOrdersCounter  uint64
+	beforeAddOrdersCounter uint64
+	AddOrdersMock          mOrderRepositoryMockAddOrders
+
+	funcDeleteOrder          func(ctx context.Context, orderID string, reason string) (err error)
 	funcDeleteOrderOrigin    string
-	inspectFuncDeleteOrder   func(ctx context.Context, orderID string)
+	inspectFuncDeleteOrder   func(ctx context.Context, orderID string, reason string)
 	afterDeleteOrderCounter  uint64
 	beforeDeleteOrderCounter uint64
 	DeleteOrderMock          mOrderRepositoryMockDeleteOrder
@@ -38,22 +45,43 @@ type OrderRepositoryMock struct {
 	beforeGetOrderCounter uint64
 	GetOrderMock          mOrderRepositoryMockGetOrder
 
-	funcListOrders          func(ctx context.Context) (opa1 []*domain.Order, err error)
+	funcGetOrdersByIDs          func(ctx context.Context, orderIDs []string) (opa1 []*domain.Order, err error)
+	funcGetOrdersByIDsOrigin    string
+	inspectFuncGetOrdersByIDs   func(ctx context.Context, orderIDs []string)
+	afterGetOrdersByIDsCounter  uint64
+	beforeGetOrdersByIDsCounter uint64
+	GetOrdersByIDsMock          mOrderRepositoryMockGetOrdersByIDs
+
+	funcListOrders          func(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) (opa1 []*domain.Order, err error)
 	funcListOrdersOrigin    string
-	inspectFuncListOrders   func(ctx context.Context)
+	inspectFuncListOrders   func(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int)
 	afterListOrdersCounter  uint64
 	beforeListOrdersCounter uint64
 	ListOrdersMock          mOrderRepositoryMockListOrders
 
+	funcListOrdersByRecipient          func(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int) (opa1 []*domain.Order, err error)
+	funcListOrdersByRecipientOrigin    string
+	inspectFuncListOrdersByRecipient   func(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int)
+	afterListOrdersByRecipientCounter  uint64
+	beforeListOrdersByRecipientCounter uint64
+	ListOrdersByRecipientMock          mOrderRepositoryMockListOrdersByRecipient
+
 	funcUpdateOrder          func(ctx context.Context, order *domain.Order) (err error)
 	funcUpdateOrderOrigin    string
 	inspectFuncUpdateOrder   func(ctx context.Context, order *domain.Order)
 	afterUpdateOrderCounter  uint64
 	beforeUpdateOrderCounter uint64
 	UpdateOrderMock          mOrderRepositoryMockUpdateOrder
+
+	funcUpdateOrders          func(ctx context.Context, orders []*domain.Order) (err error)
+	funcUpdateOrdersOrigin    string
+	inspectFuncUpdateOrders   func(ctx context.Context, orders []*domain.Order)
+	afterUpdateOrdersCounter  uint64
+	beforeUpdateOrdersCounter uint64
+	UpdateOrdersMock          mOrderRepositoryMockUpdateOrders
 }
 
-// NewOrderRepositoryMock returns a mock for mm_repository.OrderRepository
+// NewOrderRepositoryMock returns a mock for mm_interfaces.OrderRepository
 func NewOrderRepositoryMock(t minimock.Tester) *OrderRepositoryMock {
 	m := &OrderRepositoryMock{t: t}
 
@@ -64,18 +92,30 @@ func NewOrderRepositoryMock(t minimock.Tester) *OrderRepositoryMock {
 	m.AddOrderMock = mOrderRepositoryMockAddOrder{mock: m}
 	m.AddOrderMock.callArgs = []*OrderRepositoryMockAddOrderParams{}
 
+	m.AddOrdersMock = mOrderRepositoryMockAddOrders{mock: m}
+	m.AddOrdersMock.callArgs = []*OrderRepositoryMockAddOrdersParams{}
+
 	m.DeleteOrderMock = mOrderRepositoryMockDeleteOrder{mock: m}
 	m.DeleteOrderMock.callArgs = []*OrderRepositoryMockDeleteOrderParams{}
 
 	m.GetOrderMock = mOrderRepositoryMockGetOrder{mock: m}
 	m.GetOrderMock.callArgs = []*OrderRepositoryMockGetOrderParams{}
 
+	m.GetOrdersByIDsMock = mOrderRepositoryMockGetOrdersByIDs{mock: m}
+	m.GetOrdersByIDsMock.callArgs = []*OrderRepositoryMockGetOrdersByIDsParams{}
+
 	m.ListOrdersMock = mOrderRepositoryMockListOrders{mock: m}
 	m.ListOrdersMock.callArgs = []*OrderRepositoryMockListOrdersParams{}
 
+	m.ListOrdersByRecipientMock = mOrderRepositoryMockListOrdersByRecipient{mock: m}
+	m.ListOrdersByRecipientMock.callArgs = []*OrderRepositoryMockListOrdersByRecipientParams{}
+
 	m.UpdateOrderMock = mOrderRepositoryMockUpdateOrder{mock: m}
 	m.UpdateOrderMock.callArgs = []*OrderRepositoryMockUpdateOrderParams{}
 
+	m.UpdateOrdersMock = mOrderRepositoryMockUpdateOrders{mock: m}
+	m.UpdateOrdersMock.callArgs = []*OrderRepositoryMockUpdateOrdersParams{}
+
 	t.Cleanup(m.MinimockFinish)
 
 	return m
@@ -293,7 +333,7 @@ func (mmAddOrder *mOrderRepositoryMockAddOrder) invocationsDone() bool {
 	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
 }
 
-// AddOrder implements mm_repository.OrderRepository
+// AddOrder implements mm_interfaces.OrderRepository
 func (mmAddOrder *OrderRepositoryMock) AddOrder(ctx context.Context, order *domain.Order) (err error) {
 	mm_atomic.AddUint64(&mmAddOrder.beforeAddOrderCounter, 1)
 	defer mm_atomic.AddUint64(&mmAddOrder.afterAddOrderCounter, 1)
@@ -423,6 +463,348 @@ func (m *OrderRepositoryMock) MinimockAddOrderInspect() {
 	}
 }
 
+type mOrderRepositoryMockAddOrders struct {
+	optional           bool
+	mock               *OrderRepositoryMock
+	defaultExpectation *OrderRepositoryMockAddOrdersExpectation
+	expectations       []*OrderRepositoryMockAddOrdersExpectation
+
+	callArgs []*OrderRepositoryMockAddOrdersParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// OrderRepositoryMockAddOrdersExpectation specifies expectation struct of the OrderRepository.AddOrders
+type OrderRepositoryMockAddOrdersExpectation struct {
+	mock               *OrderRepositoryMock
+	params             *OrderRepositoryMockAddOrdersParams
+	paramPtrs          *OrderRepositoryMockAddOrdersParamPtrs
+	expectationOrigins OrderRepositoryMockAddOrdersExpectationOrigins
+	results            *OrderRepositoryMockAddOrdersResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// OrderRepositoryMockAddOrdersParams contains parameters of the OrderRepository.AddOrders
+type OrderRepositoryMockAddOrdersParams struct {
+	ctx    context.Context
+	orders []*domain.Order
+}
+
+// OrderRepositoryMockAddOrdersParamPtrs contains pointers to parameters of the OrderRepository.AddOrders
+type OrderRepositoryMockAddOrdersParamPtrs struct {
+	ctx    *context.Context
+	orders *[]*domain.Order
+}
+
+// OrderRepositoryMockAddOrdersResults contains results of the OrderRepository.AddOrders
+type OrderRepositoryMockAddOrdersResults struct {
+	err error
+}
+
+// OrderRepositoryMockAddOrdersOrigins contains origins of expectations of the OrderRepository.AddOrders
+type OrderRepositoryMockAddOrdersExpectationOrigins struct {
+	origin       string
+	originCtx    string
+	originOrders string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmAddOrders *mOrderRepositoryMockAddOrders) Optional() *mOrderRepositoryMockAddOrders {
+	mmAddOrders.optional = true
+	return mmAddOrders
+}
+
+// Expect sets up expected params for OrderRepository.AddOrders
+func (mmAddOrders *mOrderRepositoryMockAddOrders) Expect(ctx context.Context, orders []*domain.Order) *mOrderRepositoryMockAddOrders {
+	if mmAddOrders.mock.funcAddOrders != nil {
+		mmAddOrders.mock.t.Fatalf("OrderRepositoryMock.AddOrders mock is already set by Set")
+	}
+
+	if mmAddOrders.defaultExpectation == nil {
+		mmAddOrders.defaultExpectation = &OrderRepositoryMockAddOrdersExpectation{}
+	}
+
+	if mmAddOrders.defaultExpectation.paramPtrs != nil {
+		mmAddOrders.mock.t.Fatalf("OrderRepositoryMock.AddOrders mock is already set by ExpectParams functions")
+	}
+
+	mmAddOrders.defaultExpectation.params = &OrderRepositoryMockAddOrdersParams{ctx, orders}
+	mmAddOrders.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmAddOrders.expectations {
+		if minimock.Equal(e.params, mmAddOrders.defaultExpectation.params) {
+			mmAddOrders.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmAddOrders.defaultExpectation.params)
+		}
+	}
+
+	return mmAddOrders
+}
+
+// ExpectCtxParam1 sets up expected param ctx for OrderRepository.AddOrders
+func (mmAddOrders *mOrderRepositoryMockAddOrders) ExpectCtxParam1(ctx context.Context) *mOrderRepositoryMockAddOrders {
+	if mmAddOrders.mock.funcAddOrders != nil {
+		mmAddOrders.mock.t.Fatalf("OrderRepositoryMock.AddOrders mock is already set by Set")
+	}
+
+	if mmAddOrders.defaultExpectation == nil {
+		mmAddOrders.defaultExpectation = &OrderRepositoryMockAddOrdersExpectation{}
+	}
+
+	if mmAddOrders.defaultExpectation.params != nil {
+		mmAddOrders.mock.t.Fatalf("OrderRepositoryMock.AddOrders mock is already set by Expect")
+	}
+
+	if mmAddOrders.defaultExpectation.paramPtrs == nil {
+		mmAddOrders.defaultExpectation.paramPtrs = &OrderRepositoryMockAddOrdersParamPtrs{}
+	}
+	mmAddOrders.defaultExpectation.paramPtrs.ctx = &ctx
+	mmAddOrders.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmAddOrders
+}
+
+// ExpectOrdersParam2 sets up expected param orders for OrderRepository.AddOrders
+func (mmAddOrders *mOrderRepositoryMockAddOrders) ExpectOrdersParam2(orders []*domain.Order) *mOrderRepositoryMockAddOrders {
+	if mmAddOrders.mock.funcAddOrders != nil {
+		mmAddOrders.mock.t.Fatalf("OrderRepositoryMock.AddOrders mock is already set by Set")
+	}
+
+	if mmAddOrders.defaultExpectation == nil {
+		mmAddOrders.defaultExpectation = &OrderRepositoryMockAddOrdersExpectation{}
+	}
+
+	if mmAddOrders.defaultExpectation.params != nil {
+		mmAddOrders.mock.t.Fatalf("OrderRepositoryMock.AddOrders mock is already set by Expect")
+	}
+
+	if mmAddOrders.defaultExpectation.paramPtrs == nil {
+		mmAddOrders.defaultExpectation.paramPtrs = &OrderRepositoryMockAddOrdersParamPtrs{}
+	}
+	mmAddOrders.defaultExpectation.paramPtrs.orders = &orders
+	mmAddOrders.defaultExpectation.expectationOrigins.originOrders = minimock.CallerInfo(1)
+
+	return mmAddOrders
+}
+
+// Inspect accepts an inspector function that has same arguments as the OrderRepository.AddOrders
+func (mmAddOrders *mOrderRepositoryMockAddOrders) Inspect(f func(ctx context.Context, orders []*domain.Order)) *mOrderRepositoryMockAddOrders {
+	if mmAddOrders.mock.inspectFuncAddOrders != nil {
+		mmAddOrders.mock.t.Fatalf("Inspect function is already set for OrderRepositoryMock.AddOrders")
+	}
+
+	mmAddOrders.mock.inspectFuncAddOrders = f
+
+	return mmAddOrders
+}
+
+// Return sets up results that will be returned by OrderRepository.AddOrders
+func (mmAddOrders *mOrderRepositoryMockAddOrders) Return(err error) *OrderRepositoryMock {
+	if mmAddOrders.mock.funcAddOrders != nil {
+		mmAddOrders.mock.t.Fatalf("OrderRepositoryMock.AddOrders mock is already set by Set")
+	}
+
+	if mmAddOrders.defaultExpectation == nil {
+		mmAddOrders.defaultExpectation = &OrderRepositoryMockAddOrdersExpectation{mock: mmAddOrders.mock}
+	}
+	mmAddOrders.defaultExpectation.results = &OrderRepositoryMockAddOrdersResults{err}
+	mmAddOrders.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmAddOrders.mock
+}
+
+// Set uses given function f to mock the OrderRepository.AddOrders method
+func (mmAddOrders *mOrderRepositoryMockAddOrders) Set(f func(ctx context.Context, orders []*domain.Order) (err error)) *OrderRepositoryMock {
+	if mmAddOrders.defaultExpectation != nil {
+		mmAddOrders.mock.t.Fatalf("Default expectation is already set for the OrderRepository.AddOrders method")
+	}
+
+	if len(mmAddOrders.expectations) > 0 {
+		mmAddOrders.mock.t.Fatalf("Some expectations are already set for the OrderRepository.AddOrders method")
+	}
+
+	mmAddOrders.mock.funcAddOrders = f
+	mmAddOrders.mock.funcAddOrdersOrigin = minimock.CallerInfo(1)
+	return mmAddOrders.mock
+}
+
+// When sets expectation for the OrderRepository.AddOrders which will trigger the result defined by the following
+// Then helper
+func (mmAddOrders *mOrderRepositoryMockAddOrders) When(ctx context.Context, orders []*domain.Order) *OrderRepositoryMockAddOrdersExpectation {
+	if mmAddOrders.mock.funcAddOrders != nil {
+		mmAddOrders.mock.t.Fatalf("OrderRepositoryMock.AddOrders mock is already set by Set")
+	}
+
+	expectation := &OrderRepositoryMockAddOrdersExpectation{
+		mock:               mmAddOrders.mock,
+		params:             &OrderRepositoryMockAddOrdersParams{ctx, orders},
+		expectationOrigins: OrderRepositoryMockAddOrdersExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmAddOrders.expectations = append(mmAddOrders.expectations, expectation)
+	return expectation
+}
+
+// Then sets up OrderRepository.AddOrders return parameters for the expectation previously defined by the When method
+func (e *OrderRepositoryMockAddOrdersExpectation) Then(err error) *OrderRepositoryMock {
+	e.results = &OrderRepositoryMockAddOrdersResults{err}
+	return e.mock
+}
+
+// Times sets number of times OrderRepository.AddOrders should be invoked
+func (mmAddOrders *mOrderRepositoryMockAddOrders) Times(n uint64) *mOrderRepositoryMockAddOrders {
+	if n == 0 {
+		mmAddOrders.mock.t.Fatalf("Times of OrderRepositoryMock.AddOrders mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmAddOrders.expectedInvocations, n)
+	mmAddOrders.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmAddOrders
+}
+
+func (mmAddOrders *mOrderRepositoryMockAddOrders) invocationsDone() bool {
+	if len(mmAddOrders.expectations) == 0 && mmAddOrders.defaultExpectation == nil && mmAddOrders.mock.funcAddOrders == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmAddOrders.mock.afterAddOrdersCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmAddOrders.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// AddOrders implements mm_interfaces.OrderRepository
+func (mmAddOrders *OrderRepositoryMock) AddOrders(ctx context.Context, orders []*domain.Order) (err error) {
+	mm_atomic.AddUint64(&mmAddOrders.beforeAddOrdersCounter, 1)
+	defer mm_atomic.AddUint64(&mmAddOrders.afterAddOrdersCounter, 1)
+
+	mmAddOrders.t.Helper()
+
+	if mmAddOrders.inspectFuncAddOrders != nil {
+		mmAddOrders.inspectFuncAddOrders(ctx, orders)
+	}
+
+	mm_params := OrderRepositoryMockAddOrdersParams{ctx, orders}
+
+	// Record call args
+	mmAddOrders.AddOrdersMock.mutex.Lock()
+	mmAddOrders.AddOrdersMock.callArgs = append(mmAddOrders.AddOrdersMock.callArgs, &mm_params)
+	mmAddOrders.AddOrdersMock.mutex.Unlock()
+
+	for _, e := range mmAddOrders.AddOrdersMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmAddOrders.AddOrdersMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmAddOrders.AddOrdersMock.defaultExpectation.Counter, 1)
+		mm_want := mmAddOrders.AddOrdersMock.defaultExpectation.params
+		mm_want_ptrs := mmAddOrders.AddOrdersMock.defaultExpectation.paramPtrs
+
+		mm_got := OrderRepositoryMockAddOrdersParams{ctx, orders}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmAddOrders.t.Errorf("OrderRepositoryMock.AddOrders got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmAddOrders.AddOrdersMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.orders != nil && !minimock.Equal(*mm_want_ptrs.orders, mm_got.orders) {
+				mmAddOrders.t.Errorf("OrderRepositoryMock.AddOrders got unexpected parameter orders, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmAddOrders.AddOrdersMock.defaultExpectation.expectationOrigins.originOrders, *mm_want_ptrs.orders, mm_got.orders, minimock.Diff(*mm_want_ptrs.orders, mm_got.orders))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmAddOrders.t.Errorf("OrderRepositoryMock.AddOrders got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmAddOrders.AddOrdersMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmAddOrders.AddOrdersMock.defaultExpectation.results
+		if mm_results == nil {
+			mmAddOrders.t.Fatal("No results are set for the OrderRepositoryMock.AddOrders")
+		}
+		return (*mm_results).err
+	}
+	if mmAddOrders.funcAddOrders != nil {
+		return mmAddOrders.funcAddOrders(ctx, orders)
+	}
+	mmAddOrders.t.Fatalf("Unexpected call to OrderRepositoryMock.AddOrders. %v %v", ctx, orders)
+	return
+}
+
+// AddOrdersAfterCounter returns a count of finished OrderRepositoryMock.AddOrders invocations
+func (mmAddOrders *OrderRepositoryMock) AddOrdersAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmAddOrders.afterAddOrdersCounter)
+}
+
+// AddOrdersBeforeCounter returns a count of OrderRepositoryMock.AddOrders invocations
+func (mmAddOrders *OrderRepositoryMock) AddOrdersBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmAddOrders.beforeAddOrdersCounter)
+}
+
+// Calls returns a list of arguments used in each call to OrderRepositoryMock.AddOrders.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmAddOrders *mOrderRepositoryMockAddOrders) Calls() []*OrderRepositoryMockAddOrdersParams {
+	mmAddOrders.mutex.RLock()
+
+	argCopy := make([]*OrderRepositoryMockAddOrdersParams, len(mmAddOrders.callArgs))
+	copy(argCopy, mmAddOrders.callArgs)
+
+	mmAddOrders.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockAddOrdersDone returns true if the count of the AddOrders invocations corresponds
+// the number of defined expectations
+func (m *OrderRepositoryMock) MinimockAddOrdersDone() bool {
+	if m.AddOrdersMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.AddOrdersMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.AddOrdersMock.invocationsDone()
+}
+
+// MinimockAddOrdersInspect logs each unmet expectation
+func (m *OrderRepositoryMock) MinimockAddOrdersInspect() {
+	for _, e := range m.AddOrdersMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to OrderRepositoryMock.AddOrders at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterAddOrdersCounter := mm_atomic.LoadUint64(&m.afterAddOrdersCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.AddOrdersMock.defaultExpectation != nil && afterAddOrdersCounter < 1 {
+		if m.AddOrdersMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to OrderRepositoryMock.AddOrders at\n%s", m.AddOrdersMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to OrderRepositoryMock.AddOrders at\n%s with params: %#v", m.AddOrdersMock.defaultExpectation.expectationOrigins.origin, *m.AddOrdersMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcAddOrders != nil && afterAddOrdersCounter < 1 {
+		m.t.Errorf("Expected call to OrderRepositoryMock.AddOrders at\n%s", m.funcAddOrdersOrigin)
+	}
+
+	if !m.AddOrdersMock.invocationsDone() && afterAddOrdersCounter > 0 {
+		m.t.Errorf("Expected %d calls to OrderRepositoryMock.AddOrders at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.AddOrdersMock.expectedInvocations), m.AddOrdersMock.expectedInvocationsOrigin, afterAddOrdersCounter)
+	}
+}
+
 type mOrderRepositoryMockDeleteOrder struct {
 	optional           bool
 	mock               *OrderRepositoryMock
@@ -451,12 +833,14 @@ type OrderRepositoryMockDeleteOrderExpectation struct {
 type OrderRepositoryMockDeleteOrderParams struct {
 	ctx     context.Context
 	orderID string
+	reason  string
 }
 
 // OrderRepositoryMockDeleteOrderParamPtrs contains pointers to parameters of the OrderRepository.DeleteOrder
 type OrderRepositoryMockDeleteOrderParamPtrs struct {
 	ctx     *context.Context
 	orderID *string
+	reason  *string
 }
 
 // OrderRepositoryMockDeleteOrderResults contains results of the OrderRepository.DeleteOrder
@@ -469,6 +853,7 @@ type OrderRepositoryMockDeleteOrderExpectationOrigins struct {
 	origin        string
 	originCtx     string
 	originOrderID string
+	originReason  string
 }
 
 // Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
@@ -482,7 +867,7 @@ func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) Optional() *mOrderReposito
 }
 
 // Expect sets up expected params for OrderRepository.DeleteOrder
-func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) Expect(ctx context.Context, orderID string) *mOrderRepositoryMockDeleteOrder {
+func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) Expect(ctx context.Context, orderID string, reason string) *mOrderRepositoryMockDeleteOrder {
 	if mmDeleteOrder.mock.funcDeleteOrder != nil {
 		mmDeleteOrder.mock.t.Fatalf("OrderRepositoryMock.DeleteOrder mock is already set by Set")
 	}
@@ -495,7 +880,7 @@ func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) Expect(ctx context.Context
 		mmDeleteOrder.mock.t.Fatalf("OrderRepositoryMock.DeleteOrder mock is already set by ExpectParams functions")
 	}
 
-	mmDeleteOrder.defaultExpectation.params = &OrderRepositoryMockDeleteOrderParams{ctx, orderID}
+	mmDeleteOrder.defaultExpectation.params = &OrderRepositoryMockDeleteOrderParams{ctx, orderID, reason}
 	mmDeleteOrder.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
 	for _, e := range mmDeleteOrder.expectations {
 		if minimock.Equal(e.params, mmDeleteOrder.defaultExpectation.params) {
@@ -552,8 +937,31 @@ func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) ExpectOrderIDParam2(orderI
 	return mmDeleteOrder
 }
 
+// ExpectReasonParam3 sets up expected param reason for OrderRepository.DeleteOrder
+func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) ExpectReasonParam3(reason string) *mOrderRepositoryMockDeleteOrder {
+	if mmDeleteOrder.mock.funcDeleteOrder != nil {
+		mmDeleteOrder.mock.t.Fatalf("OrderRepositoryMock.DeleteOrder mock is already set by Set")
+	}
+
+	if mmDeleteOrder.defaultExpectation == nil {
+		mmDeleteOrder.defaultExpectation = &OrderRepositoryMockDeleteOrderExpectation{}
+	}
+
+	if mmDeleteOrder.defaultExpectation.params != nil {
+		mmDeleteOrder.mock.t.Fatalf("OrderRepositoryMock.DeleteOrder mock is already set by Expect")
+	}
+
+	if mmDeleteOrder.defaultExpectation.paramPtrs == nil {
+		mmDeleteOrder.defaultExpectation.paramPtrs = &OrderRepositoryMockDeleteOrderParamPtrs{}
+	}
+	mmDeleteOrder.defaultExpectation.paramPtrs.reason = &reason
+	mmDeleteOrder.defaultExpectation.expectationOrigins.originReason = minimock.CallerInfo(1)
+
+	return mmDeleteOrder
+}
+
 // Inspect accepts an inspector function that has same arguments as the OrderRepository.DeleteOrder
-func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) Inspect(f func(ctx context.Context, orderID string)) *mOrderRepositoryMockDeleteOrder {
+func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) Inspect(f func(ctx context.Context, orderID string, reason string)) *mOrderRepositoryMockDeleteOrder {
 	if mmDeleteOrder.mock.inspectFuncDeleteOrder != nil {
 		mmDeleteOrder.mock.t.Fatalf("Inspect function is already set for OrderRepositoryMock.DeleteOrder")
 	}
@@ -578,7 +986,7 @@ func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) Return(err error) *OrderRe
 }
 
 // Set uses given function f to mock the OrderRepository.DeleteOrder method
-func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) Set(f func(ctx context.Context, orderID string) (err error)) *OrderRepositoryMock {
+func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) Set(f func(ctx context.Context, orderID string, reason string) (err error)) *OrderRepositoryMock {
 	if mmDeleteOrder.defaultExpectation != nil {
 		mmDeleteOrder.mock.t.Fatalf("Default expectation is already set for the OrderRepository.DeleteOrder method")
 	}
@@ -594,14 +1002,14 @@ func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) Set(f func(ctx context.Con
 
 // When sets expectation for the OrderRepository.DeleteOrder which will trigger the result defined by the following
 // Then helper
-func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) When(ctx context.Context, orderID string) *OrderRepositoryMockDeleteOrderExpectation {
+func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) When(ctx context.Context, orderID string, reason string) *OrderRepositoryMockDeleteOrderExpectation {
 	if mmDeleteOrder.mock.funcDeleteOrder != nil {
 		mmDeleteOrder.mock.t.Fatalf("OrderRepositoryMock.DeleteOrder mock is already set by Set")
 	}
 
 	expectation := &OrderRepositoryMockDeleteOrderExpectation{
 		mock:               mmDeleteOrder.mock,
-		params:             &OrderRepositoryMockDeleteOrderParams{ctx, orderID},
+		params:             &OrderRepositoryMockDeleteOrderParams{ctx, orderID, reason},
 		expectationOrigins: OrderRepositoryMockDeleteOrderExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
 	mmDeleteOrder.expectations = append(mmDeleteOrder.expectations, expectation)
@@ -635,18 +1043,18 @@ func (mmDeleteOrder *mOrderRepositoryMockDeleteOrder) invocationsDone() bool {
 	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
 }
 
-// DeleteOrder implements mm_repository.OrderRepository
-func (mmDeleteOrder *OrderRepositoryMock) DeleteOrder(ctx context.Context, orderID string) (err error) {
+// DeleteOrder implements mm_interfaces.OrderRepository
+func (mmDeleteOrder *OrderRepositoryMock) DeleteOrder(ctx context.Context, orderID string, reason string) (err error) {
 	mm_atomic.AddUint64(&mmDeleteOrder.beforeDeleteOrderCounter, 1)
 	defer mm_atomic.AddUint64(&mmDeleteOrder.afterDeleteOrderCounter, 1)
 
 	mmDeleteOrder.t.Helper()
 
 	if mmDeleteOrder.inspectFuncDeleteOrder != nil {
-		mmDeleteOrder.inspectFuncDeleteOrder(ctx, orderID)
+		mmDeleteOrder.inspectFuncDeleteOrder(ctx, orderID, reason)
 	}
 
-	mm_params := OrderRepositoryMockDeleteOrderParams{ctx, orderID}
+	mm_params := OrderRepositoryMockDeleteOrderParams{ctx, orderID, reason}
 
 	// Record call args
 	mmDeleteOrder.DeleteOrderMock.mutex.Lock()
@@ -665,7 +1073,7 @@ func (mmDeleteOrder *OrderRepositoryMock) DeleteOrder(ctx context.Context, order
 		mm_want := mmDeleteOrder.DeleteOrderMock.defaultExpectation.params
 		mm_want_ptrs := mmDeleteOrder.DeleteOrderMock.defaultExpectation.paramPtrs
 
-		mm_got := OrderRepositoryMockDeleteOrderParams{ctx, orderID}
+		mm_got := OrderRepositoryMockDeleteOrderParams{ctx, orderID, reason}
 
 		if mm_want_ptrs != nil {
 
@@ -679,6 +1087,11 @@ func (mmDeleteOrder *OrderRepositoryMock) DeleteOrder(ctx context.Context, order
 					mmDeleteOrder.DeleteOrderMock.defaultExpectation.expectationOrigins.originOrderID, *mm_want_ptrs.orderID, mm_got.orderID, minimock.Diff(*mm_want_ptrs.orderID, mm_got.orderID))
 			}
 
+			if mm_want_ptrs.reason != nil && !minimock.Equal(*mm_want_ptrs.reason, mm_got.reason) {
+				mmDeleteOrder.t.Errorf("OrderRepositoryMock.DeleteOrder got unexpected parameter reason, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDeleteOrder.DeleteOrderMock.defaultExpectation.expectationOrigins.originReason, *mm_want_ptrs.reason, mm_got.reason, minimock.Diff(*mm_want_ptrs.reason, mm_got.reason))
+			}
+
 		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
 			mmDeleteOrder.t.Errorf("OrderRepositoryMock.DeleteOrder got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 				mmDeleteOrder.DeleteOrderMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
@@ -691,9 +1104,9 @@ func (mmDeleteOrder *OrderRepositoryMock) DeleteOrder(ctx context.Context, order
 		return (*mm_results).err
 	}
 	if mmDeleteOrder.funcDeleteOrder != nil {
-		return mmDeleteOrder.funcDeleteOrder(ctx, orderID)
+		return mmDeleteOrder.funcDeleteOrder(ctx, orderID, reason)
 	}
-	mmDeleteOrder.t.Fatalf("Unexpected call to OrderRepositoryMock.DeleteOrder. %v %v", ctx, orderID)
+	mmDeleteOrder.t.Fatalf("Unexpected call to OrderRepositoryMock.DeleteOrder. %v %v %v", ctx, orderID, reason)
 	return
 }
 
@@ -978,7 +1391,7 @@ func (mmGetOrder *mOrderRepositoryMockGetOrder) invocationsDone() bool {
 	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
 }
 
-// GetOrder implements mm_repository.OrderRepository
+// GetOrder implements mm_interfaces.OrderRepository
 func (mmGetOrder *OrderRepositoryMock) GetOrder(ctx context.Context, orderID string) (op1 *domain.Order, err error) {
 	mm_atomic.AddUint64(&mmGetOrder.beforeGetOrderCounter, 1)
 	defer mm_atomic.AddUint64(&mmGetOrder.afterGetOrderCounter, 1)
@@ -1108,50 +1521,53 @@ func (m *OrderRepositoryMock) MinimockGetOrderInspect() {
 	}
 }
 
-type mOrderRepositoryMockListOrders struct {
+type mOrderRepositoryMockGetOrdersByIDs struct {
 	optional           bool
 	mock               *OrderRepositoryMock
-	defaultExpectation *OrderRepositoryMockListOrdersExpectation
-	expectations       []*OrderRepositoryMockListOrdersExpectation
+	defaultExpectation *OrderRepositoryMockGetOrdersByIDsExpectation
+	expectations       []*OrderRepositoryMockGetOrdersByIDsExpectation
 
-	callArgs []*OrderRepositoryMockListOrdersParams
+	callArgs []*OrderRepositoryMockGetOrdersByIDsParams
 	mutex    sync.RWMutex
 
 	expectedInvocations       uint64
 	expectedInvocationsOrigin string
 }
 
-// OrderRepositoryMockListOrdersExpectation specifies expectation struct of the OrderRepository.ListOrders
-type OrderRepositoryMockListOrdersExpectation struct {
+// OrderRepositoryMockGetOrdersByIDsExpectation specifies expectation struct of the OrderRepository.GetOrdersByIDs
+type OrderRepositoryMockGetOrdersByIDsExpectation struct {
 	mock               *OrderRepositoryMock
-	params             *OrderRepositoryMockListOrdersParams
-	paramPtrs          *OrderRepositoryMockListOrdersParamPtrs
-	expectationOrigins OrderRepositoryMockListOrdersExpectationOrigins
-	results            *OrderRepositoryMockListOrdersResults
+	params             *OrderRepositoryMockGetOrdersByIDsParams
+	paramPtrs          *OrderRepositoryMockGetOrdersByIDsParamPtrs
+	expectationOrigins OrderRepositoryMockGetOrdersByIDsExpectationOrigins
+	results            *OrderRepositoryMockGetOrdersByIDsResults
 	returnOrigin       string
 	Counter            uint64
 }
 
-// OrderRepositoryMockListOrdersParams contains parameters of the OrderRepository.ListOrders
-type OrderRepositoryMockListOrdersParams struct {
-	ctx context.Context
+// OrderRepositoryMockGetOrdersByIDsParams contains parameters of the OrderRepository.GetOrdersByIDs
+type OrderRepositoryMockGetOrdersByIDsParams struct {
+	ctx      context.Context
+	orderIDs []string
 }
 
-// OrderRepositoryMockListOrdersParamPtrs contains pointers to parameters of the OrderRepository.ListOrders
-type OrderRepositoryMockListOrdersParamPtrs struct {
-	ctx *context.Context
+// OrderRepositoryMockGetOrdersByIDsParamPtrs contains pointers to parameters of the OrderRepository.GetOrdersByIDs
+type OrderRepositoryMockGetOrdersByIDsParamPtrs struct {
+	ctx      *context.Context
+	orderIDs *[]string
 }
 
-// OrderRepositoryMockListOrdersResults contains results of the OrderRepository.ListOrders
-type OrderRepositoryMockListOrdersResults struct {
+// OrderRepositoryMockGetOrdersByIDsResults contains results of the OrderRepository.GetOrdersByIDs
+type OrderRepositoryMockGetOrdersByIDsResults struct {
 	opa1 []*domain.Order
 	err  error
 }
 
-// OrderRepositoryMockListOrdersOrigins contains origins of expectations of the OrderRepository.ListOrders
-type OrderRepositoryMockListOrdersExpectationOrigins struct {
-	origin    string
-	originCtx string
+// OrderRepositoryMockGetOrdersByIDsOrigins contains origins of expectations of the OrderRepository.GetOrdersByIDs
+type OrderRepositoryMockGetOrdersByIDsExpectationOrigins struct {
+	origin         string
+	originCtx      string
+	originOrderIDs string
 }
 
 // Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
@@ -1159,264 +1575,1102 @@ type OrderRepositoryMockListOrdersExpectationOrigins struct {
 // Optional() makes method check to work in '0 or more' mode.
 // It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
 // catch the problems when the expected method call is totally skipped during test run.
-func (mmListOrders *mOrderRepositoryMockListOrders) Optional() *mOrderRepositoryMockListOrders {
-	mmListOrders.optional = true
-	return mmListOrders
+func (mmGetOrdersByIDs *mOrderRepositoryMockGetOrdersByIDs) Optional() *mOrderRepositoryMockGetOrdersByIDs {
+	mmGetOrdersByIDs.optional = true
+	return mmGetOrdersByIDs
 }
 
-// Expect sets up expected params for OrderRepository.ListOrders
-func (mmListOrders *mOrderRepositoryMockListOrders) Expect(ctx context.Context) *mOrderRepositoryMockListOrders {
-	if mmListOrders.mock.funcListOrders != nil {
-		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Set")
+// Expect sets up expected params for OrderRepository.GetOrdersByIDs
+func (mmGetOrdersByIDs *mOrderRepositoryMockGetOrdersByIDs) Expect(ctx context.Context, orderIDs []string) *mOrderRepositoryMockGetOrdersByIDs {
+	if mmGetOrdersByIDs.mock.funcGetOrdersByIDs != nil {
+		mmGetOrdersByIDs.mock.t.Fatalf("OrderRepositoryMock.GetOrdersByIDs mock is already set by Set")
 	}
 
-	if mmListOrders.defaultExpectation == nil {
-		mmListOrders.defaultExpectation = &OrderRepositoryMockListOrdersExpectation{}
+	if mmGetOrdersByIDs.defaultExpectation == nil {
+		mmGetOrdersByIDs.defaultExpectation = &OrderRepositoryMockGetOrdersByIDsExpectation{}
 	}
 
-	if mmListOrders.defaultExpectation.paramPtrs != nil {
-		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by ExpectParams functions")
+	if mmGetOrdersByIDs.defaultExpectation.paramPtrs != nil {
+		mmGetOrdersByIDs.mock.t.Fatalf("OrderRepositoryMock.GetOrdersByIDs mock is already set by ExpectParams functions")
 	}
 
-	mmListOrders.defaultExpectation.params = &OrderRepositoryMockListOrdersParams{ctx}
-	mmListOrders.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
-	for _, e := range mmListOrders.expectations {
-		if minimock.Equal(e.params, mmListOrders.defaultExpectation.params) {
-			mmListOrders.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmListOrders.defaultExpectation.params)
+	mmGetOrdersByIDs.defaultExpectation.params = &OrderRepositoryMockGetOrdersByIDsParams{ctx, orderIDs}
+	mmGetOrdersByIDs.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmGetOrdersByIDs.expectations {
+		if minimock.Equal(e.params, mmGetOrdersByIDs.defaultExpectation.params) {
+			mmGetOrdersByIDs.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmGetOrdersByIDs.defaultExpectation.params)
 		}
 	}
 
-	return mmListOrders
+	return mmGetOrdersByIDs
 }
 
-// ExpectCtxParam1 sets up expected param ctx for OrderRepository.ListOrders
-func (mmListOrders *mOrderRepositoryMockListOrders) ExpectCtxParam1(ctx context.Context) *mOrderRepositoryMockListOrders {
-	if mmListOrders.mock.funcListOrders != nil {
-		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Set")
+// ExpectCtxParam1 sets up expected param ctx for OrderRepository.GetOrdersByIDs
+func (mmGetOrdersByIDs *mOrderRepositoryMockGetOrdersByIDs) ExpectCtxParam1(ctx context.Context) *mOrderRepositoryMockGetOrdersByIDs {
+	if mmGetOrdersByIDs.mock.funcGetOrdersByIDs != nil {
+		mmGetOrdersByIDs.mock.t.Fatalf("OrderRepositoryMock.GetOrdersByIDs mock is already set by Set")
 	}
 
-	if mmListOrders.defaultExpectation == nil {
-		mmListOrders.defaultExpectation = &OrderRepositoryMockListOrdersExpectation{}
+	if mmGetOrdersByIDs.defaultExpectation == nil {
+		mmGetOrdersByIDs.defaultExpectation = &OrderRepositoryMockGetOrdersByIDsExpectation{}
 	}
 
-	if mmListOrders.defaultExpectation.params != nil {
-		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Expect")
+	if mmGetOrdersByIDs.defaultExpectation.params != nil {
+		mmGetOrdersByIDs.mock.t.Fatalf("OrderRepositoryMock.GetOrdersByIDs mock is already set by Expect")
 	}
 
-	if mmListOrders.defaultExpectation.paramPtrs == nil {
-		mmListOrders.defaultExpectation.paramPtrs = &OrderRepositoryMockListOrdersParamPtrs{}
+	if mmGetOrdersByIDs.defaultExpectation.paramPtrs == nil {
+		mmGetOrdersByIDs.defaultExpectation.paramPtrs = &OrderRepositoryMockGetOrdersByIDsParamPtrs{}
 	}
-	mmListOrders.defaultExpectation.paramPtrs.ctx = &ctx
-	mmListOrders.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+	mmGetOrdersByIDs.defaultExpectation.paramPtrs.ctx = &ctx
+	mmGetOrdersByIDs.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
 
-	return mmListOrders
+	return mmGetOrdersByIDs
 }
 
-// Inspect accepts an inspector function that has same arguments as the OrderRepository.ListOrders
-func (mmListOrders *mOrderRepositoryMockListOrders) Inspect(f func(ctx context.Context)) *mOrderRepositoryMockListOrders {
-	if mmListOrders.mock.inspectFuncListOrders != nil {
-		mmListOrders.mock.t.Fatalf("Inspect function is already set for OrderRepositoryMock.ListOrders")
+// ExpectOrderIDsParam2 sets up expected param orderIDs for OrderRepository.GetOrdersByIDs
+func (mmGetOrdersByIDs *mOrderRepositoryMockGetOrdersByIDs) ExpectOrderIDsParam2(orderIDs []string) *mOrderRepositoryMockGetOrdersByIDs {
+	if mmGetOrdersByIDs.mock.funcGetOrdersByIDs != nil {
+		mmGetOrdersByIDs.mock.t.Fatalf("OrderRepositoryMock.GetOrdersByIDs mock is already set by Set")
 	}
 
-	mmListOrders.mock.inspectFuncListOrders = f
+	if mmGetOrdersByIDs.defaultExpectation == nil {
+		mmGetOrdersByIDs.defaultExpectation = &OrderRepositoryMockGetOrdersByIDsExpectation{}
+	}
 
-	return mmListOrders
-}
+	if mmGetOrdersByIDs.defaultExpectation.params != nil {
+		mmGetOrdersByIDs.mock.t.Fatalf("OrderRepositoryMock.GetOrdersByIDs mock is already set by Expect")
+	}
 
-// Return sets up results that will be returned by OrderRepository.ListOrders
-func (mmListOrders *mOrderRepositoryMockListOrders) Return(opa1 []*domain.Order, err error) *OrderRepositoryMock {
-	if mmListOrders.mock.funcListOrders != nil {
-		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Set")
+	if mmGetOrdersByIDs.defaultExpectation.paramPtrs == nil {
+		mmGetOrdersByIDs.defaultExpectation.paramPtrs = &OrderRepositoryMockGetOrdersByIDsParamPtrs{}
 	}
+	mmGetOrdersByIDs.defaultExpectation.paramPtrs.orderIDs = &orderIDs
+	mmGetOrdersByIDs.defaultExpectation.expectationOrigins.originOrderIDs = minimock.CallerInfo(1)
 
-	if mmListOrders.defaultExpectation == nil {
+	return mmGetOrdersByIDs
+}
+
+// Inspect accepts an inspector function that has same arguments as the OrderRepository.GetOrdersByIDs
+func (mmGetOrdersByIDs *mOrderRepositoryMockGetOrdersByIDs) Inspect(f func(ctx context.Context, orderIDs []string)) *mOrderRepositoryMockGetOrdersByIDs {
+	if mmGetOrdersByIDs.mock.inspectFuncGetOrdersByIDs != nil {
+		mmGetOrdersByIDs.mock.t.Fatalf("Inspect function is already set for OrderRepositoryMock.GetOrdersByIDs")
+	}
+
+	mmGetOrdersByIDs.mock.inspectFuncGetOrdersByIDs = f
+
+	return mmGetOrdersByIDs
+}
+
+// Return sets up results that will be returned by OrderRepository.GetOrdersByIDs
+func (mmGetOrdersByIDs *mOrderRepositoryMockGetOrdersByIDs) Return(opa1 []*domain.Order, err error) *OrderRepositoryMock {
+	if mmGetOrdersByIDs.mock.funcGetOrdersByIDs != nil {
+		mmGetOrdersByIDs.mock.t.Fatalf("OrderRepositoryMock.GetOrdersByIDs mock is already set by Set")
+	}
+
+	if mmGetOrdersByIDs.defaultExpectation == nil {
+		mmGetOrdersByIDs.defaultExpectation = &OrderRepositoryMockGetOrdersByIDsExpectation{mock: mmGetOrdersByIDs.mock}
+	}
+	mmGetOrdersByIDs.defaultExpectation.results = &OrderRepositoryMockGetOrdersByIDsResults{opa1, err}
+	mmGetOrdersByIDs.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmGetOrdersByIDs.mock
+}
+
+// Set uses given function f to mock the OrderRepository.GetOrdersByIDs method
+func (mmGetOrdersByIDs *mOrderRepositoryMockGetOrdersByIDs) Set(f func(ctx context.Context, orderIDs []string) (opa1 []*domain.Order, err error)) *OrderRepositoryMock {
+	if mmGetOrdersByIDs.defaultExpectation != nil {
+		mmGetOrdersByIDs.mock.t.Fatalf("Default expectation is already set for the OrderRepository.GetOrdersByIDs method")
+	}
+
+	if len(mmGetOrdersByIDs.expectations) > 0 {
+		mmGetOrdersByIDs.mock.t.Fatalf("Some expectations are already set for the OrderRepository.GetOrdersByIDs method")
+	}
+
+	mmGetOrdersByIDs.mock.funcGetOrdersByIDs = f
+	mmGetOrdersByIDs.mock.funcGetOrdersByIDsOrigin = minimock.CallerInfo(1)
+	return mmGetOrdersByIDs.mock
+}
+
+// When sets expectation for the OrderRepository.GetOrdersByIDs which will trigger the result defined by the following
+// Then helper
+func (mmGetOrdersByIDs *mOrderRepositoryMockGetOrdersByIDs) When(ctx context.Context, orderIDs []string) *OrderRepositoryMockGetOrdersByIDsExpectation {
+	if mmGetOrdersByIDs.mock.funcGetOrdersByIDs != nil {
+		mmGetOrdersByIDs.mock.t.Fatalf("OrderRepositoryMock.GetOrdersByIDs mock is already set by Set")
+	}
+
+	expectation := &OrderRepositoryMockGetOrdersByIDsExpectation{
+		mock:               mmGetOrdersByIDs.mock,
+		params:             &OrderRepositoryMockGetOrdersByIDsParams{ctx, orderIDs},
+		expectationOrigins: OrderRepositoryMockGetOrdersByIDsExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmGetOrdersByIDs.expectations = append(mmGetOrdersByIDs.expectations, expectation)
+	return expectation
+}
+
+// Then sets up OrderRepository.GetOrdersByIDs return parameters for the expectation previously defined by the When method
+func (e *OrderRepositoryMockGetOrdersByIDsExpectation) Then(opa1 []*domain.Order, err error) *OrderRepositoryMock {
+	e.results = &OrderRepositoryMockGetOrdersByIDsResults{opa1, err}
+	return e.mock
+}
+
+// Times sets number of times OrderRepository.GetOrdersByIDs should be invoked
+func (mmGetOrdersByIDs *mOrderRepositoryMockGetOrdersByIDs) Times(n uint64) *mOrderRepositoryMockGetOrdersByIDs {
+	if n == 0 {
+		mmGetOrdersByIDs.mock.t.Fatalf("Times of OrderRepositoryMock.GetOrdersByIDs mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmGetOrdersByIDs.expectedInvocations, n)
+	mmGetOrdersByIDs.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmGetOrdersByIDs
+}
+
+func (mmGetOrdersByIDs *mOrderRepositoryMockGetOrdersByIDs) invocationsDone() bool {
+	if len(mmGetOrdersByIDs.expectations) == 0 && mmGetOrdersByIDs.defaultExpectation == nil && mmGetOrdersByIDs.mock.funcGetOrdersByIDs == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmGetOrdersByIDs.mock.afterGetOrdersByIDsCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmGetOrdersByIDs.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// GetOrdersByIDs implements mm_interfaces.OrderRepository
+func (mmGetOrdersByIDs *OrderRepositoryMock) GetOrdersByIDs(ctx context.Context, orderIDs []string) (opa1 []*domain.Order, err error) {
+	mm_atomic.AddUint64(&mmGetOrdersByIDs.beforeGetOrdersByIDsCounter, 1)
+	defer mm_atomic.AddUint64(&mmGetOrdersByIDs.afterGetOrdersByIDsCounter, 1)
+
+	mmGetOrdersByIDs.t.Helper()
+
+	if mmGetOrdersByIDs.inspectFuncGetOrdersByIDs != nil {
+		mmGetOrdersByIDs.inspectFuncGetOrdersByIDs(ctx, orderIDs)
+	}
+
+	mm_params := OrderRepositoryMockGetOrdersByIDsParams{ctx, orderIDs}
+
+	// Record call args
+	mmGetOrdersByIDs.GetOrdersByIDsMock.mutex.Lock()
+	mmGetOrdersByIDs.GetOrdersByIDsMock.callArgs = append(mmGetOrdersByIDs.GetOrdersByIDsMock.callArgs, &mm_params)
+	mmGetOrdersByIDs.GetOrdersByIDsMock.mutex.Unlock()
+
+	for _, e := range mmGetOrdersByIDs.GetOrdersByIDsMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.opa1, e.results.err
+		}
+	}
+
+	if mmGetOrdersByIDs.GetOrdersByIDsMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmGetOrdersByIDs.GetOrdersByIDsMock.defaultExpectation.Counter, 1)
+		mm_want := mmGetOrdersByIDs.GetOrdersByIDsMock.defaultExpectation.params
+		mm_want_ptrs := mmGetOrdersByIDs.GetOrdersByIDsMock.defaultExpectation.paramPtrs
+
+		mm_got := OrderRepositoryMockGetOrdersByIDsParams{ctx, orderIDs}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmGetOrdersByIDs.t.Errorf("OrderRepositoryMock.GetOrdersByIDs got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetOrdersByIDs.GetOrdersByIDsMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.orderIDs != nil && !minimock.Equal(*mm_want_ptrs.orderIDs, mm_got.orderIDs) {
+				mmGetOrdersByIDs.t.Errorf("OrderRepositoryMock.GetOrdersByIDs got unexpected parameter orderIDs, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetOrdersByIDs.GetOrdersByIDsMock.defaultExpectation.expectationOrigins.originOrderIDs, *mm_want_ptrs.orderIDs, mm_got.orderIDs, minimock.Diff(*mm_want_ptrs.orderIDs, mm_got.orderIDs))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmGetOrdersByIDs.t.Errorf("OrderRepositoryMock.GetOrdersByIDs got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmGetOrdersByIDs.GetOrdersByIDsMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmGetOrdersByIDs.GetOrdersByIDsMock.defaultExpectation.results
+		if mm_results == nil {
+			mmGetOrdersByIDs.t.Fatal("No results are set for the OrderRepositoryMock.GetOrdersByIDs")
+		}
+		return (*mm_results).opa1, (*mm_results).err
+	}
+	if mmGetOrdersByIDs.funcGetOrdersByIDs != nil {
+		return mmGetOrdersByIDs.funcGetOrdersByIDs(ctx, orderIDs)
+	}
+	mmGetOrdersByIDs.t.Fatalf("Unexpected call to OrderRepositoryMock.GetOrdersByIDs. %v %v", ctx, orderIDs)
+	return
+}
+
+// GetOrdersByIDsAfterCounter returns a count of finished OrderRepositoryMock.GetOrdersByIDs invocations
+func (mmGetOrdersByIDs *OrderRepositoryMock) GetOrdersByIDsAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetOrdersByIDs.afterGetOrdersByIDsCounter)
+}
+
+// GetOrdersByIDsBeforeCounter returns a count of OrderRepositoryMock.GetOrdersByIDs invocations
+func (mmGetOrdersByIDs *OrderRepositoryMock) GetOrdersByIDsBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmGetOrdersByIDs.beforeGetOrdersByIDsCounter)
+}
+
+// Calls returns a list of arguments used in each call to OrderRepositoryMock.GetOrdersByIDs.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmGetOrdersByIDs *mOrderRepositoryMockGetOrdersByIDs) Calls() []*OrderRepositoryMockGetOrdersByIDsParams {
+	mmGetOrdersByIDs.mutex.RLock()
+
+	argCopy := make([]*OrderRepositoryMockGetOrdersByIDsParams, len(mmGetOrdersByIDs.callArgs))
+	copy(argCopy, mmGetOrdersByIDs.callArgs)
+
+	mmGetOrdersByIDs.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockGetOrdersByIDsDone returns true if the count of the GetOrdersByIDs invocations corresponds
+// the number of defined expectations
+func (m *OrderRepositoryMock) MinimockGetOrdersByIDsDone() bool {
+	if m.GetOrdersByIDsMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.GetOrdersByIDsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.GetOrdersByIDsMock.invocationsDone()
+}
+
+// MinimockGetOrdersByIDsInspect logs each unmet expectation
+func (m *OrderRepositoryMock) MinimockGetOrdersByIDsInspect() {
+	for _, e := range m.GetOrdersByIDsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to OrderRepositoryMock.GetOrdersByIDs at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterGetOrdersByIDsCounter := mm_atomic.LoadUint64(&m.afterGetOrdersByIDsCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.GetOrdersByIDsMock.defaultExpectation != nil && afterGetOrdersByIDsCounter < 1 {
+		if m.GetOrdersByIDsMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to OrderRepositoryMock.GetOrdersByIDs at\n%s", m.GetOrdersByIDsMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to OrderRepositoryMock.GetOrdersByIDs at\n%s with params: %#v", m.GetOrdersByIDsMock.defaultExpectation.expectationOrigins.origin, *m.GetOrdersByIDsMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcGetOrdersByIDs != nil && afterGetOrdersByIDsCounter < 1 {
+		m.t.Errorf("Expected call to OrderRepositoryMock.GetOrdersByIDs at\n%s", m.funcGetOrdersByIDsOrigin)
+	}
+
+	if !m.GetOrdersByIDsMock.invocationsDone() && afterGetOrdersByIDsCounter > 0 {
+		m.t.Errorf("Expected %d calls to OrderRepositoryMock.GetOrdersByIDs at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.GetOrdersByIDsMock.expectedInvocations), m.GetOrdersByIDsMock.expectedInvocationsOrigin, afterGetOrdersByIDsCounter)
+	}
+}
+
+type mOrderRepositoryMockListOrders struct {
+	optional           bool
+	mock               *OrderRepositoryMock
+	defaultExpectation *OrderRepositoryMockListOrdersExpectation
+	expectations       []*OrderRepositoryMockListOrdersExpectation
+
+	callArgs []*OrderRepositoryMockListOrdersParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// OrderRepositoryMockListOrdersExpectation specifies expectation struct of the OrderRepository.ListOrders
+type OrderRepositoryMockListOrdersExpectation struct {
+	mock               *OrderRepositoryMock
+	params             *OrderRepositoryMockListOrdersParams
+	paramPtrs          *OrderRepositoryMockListOrdersParamPtrs
+	expectationOrigins OrderRepositoryMockListOrdersExpectationOrigins
+	results            *OrderRepositoryMockListOrdersResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// OrderRepositoryMockListOrdersParams contains parameters of the OrderRepository.ListOrders
+type OrderRepositoryMockListOrdersParams struct {
+	ctx    context.Context
+	filter domain.OrderFilter
+	after  *domain.OrderCursor
+	limit  int
+}
+
+// OrderRepositoryMockListOrdersParamPtrs contains pointers to parameters of the OrderRepository.ListOrders
+type OrderRepositoryMockListOrdersParamPtrs struct {
+	ctx    *context.Context
+	filter *domain.OrderFilter
+	after  **domain.OrderCursor
+	limit  *int
+}
+
+// OrderRepositoryMockListOrdersResults contains results of the OrderRepository.ListOrders
+type OrderRepositoryMockListOrdersResults struct {
+	opa1 []*domain.Order
+	err  error
+}
+
+// OrderRepositoryMockListOrdersOrigins contains origins of expectations of the OrderRepository.ListOrders
+type OrderRepositoryMockListOrdersExpectationOrigins struct {
+	origin       string
+	originCtx    string
+	originFilter string
+	originAfter  string
+	originLimit  string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmListOrders *mOrderRepositoryMockListOrders) Optional() *mOrderRepositoryMockListOrders {
+	mmListOrders.optional = true
+	return mmListOrders
+}
+
+// Expect sets up expected params for OrderRepository.ListOrders
+func (mmListOrders *mOrderRepositoryMockListOrders) Expect(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) *mOrderRepositoryMockListOrders {
+	if mmListOrders.mock.funcListOrders != nil {
+		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Set")
+	}
+
+	if mmListOrders.defaultExpectation == nil {
+		mmListOrders.defaultExpectation = &OrderRepositoryMockListOrdersExpectation{}
+	}
+
+	if mmListOrders.defaultExpectation.paramPtrs != nil {
+		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by ExpectParams functions")
+	}
+
+	mmListOrders.defaultExpectation.params = &OrderRepositoryMockListOrdersParams{ctx, filter, after, limit}
+	mmListOrders.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmListOrders.expectations {
+		if minimock.Equal(e.params, mmListOrders.defaultExpectation.params) {
+			mmListOrders.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmListOrders.defaultExpectation.params)
+		}
+	}
+
+	return mmListOrders
+}
+
+// ExpectCtxParam1 sets up expected param ctx for OrderRepository.ListOrders
+func (mmListOrders *mOrderRepositoryMockListOrders) ExpectCtxParam1(ctx context.Context) *mOrderRepositoryMockListOrders {
+	if mmListOrders.mock.funcListOrders != nil {
+		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Set")
+	}
+
+	if mmListOrders.defaultExpectation == nil {
+		mmListOrders.defaultExpectation = &OrderRepositoryMockListOrdersExpectation{}
+	}
+
+	if mmListOrders.defaultExpectation.params != nil {
+		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Expect")
+	}
+
+	if mmListOrders.defaultExpectation.paramPtrs == nil {
+		mmListOrders.defaultExpectation.paramPtrs = &OrderRepositoryMockListOrdersParamPtrs{}
+	}
+	mmListOrders.defaultExpectation.paramPtrs.ctx = &ctx
+	mmListOrders.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmListOrders
+}
+
+// ExpectFilterParam2 sets up expected param filter for OrderRepository.ListOrders
+func (mmListOrders *mOrderRepositoryMockListOrders) ExpectFilterParam2(filter domain.OrderFilter) *mOrderRepositoryMockListOrders {
+	if mmListOrders.mock.funcListOrders != nil {
+		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Set")
+	}
+
+	if mmListOrders.defaultExpectation == nil {
+		mmListOrders.defaultExpectation = &OrderRepositoryMockListOrdersExpectation{}
+	}
+
+	if mmListOrders.defaultExpectation.params != nil {
+		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Expect")
+	}
+
+	if mmListOrders.defaultExpectation.paramPtrs == nil {
+		mmListOrders.defaultExpectation.paramPtrs = &OrderRepositoryMockListOrdersParamPtrs{}
+	}
+	mmListOrders.defaultExpectation.paramPtrs.filter = &filter
+	mmListOrders.defaultExpectation.expectationOrigins.originFilter = minimock.CallerInfo(1)
+
+	return mmListOrders
+}
+
+// ExpectAfterParam3 sets up expected param after for OrderRepository.ListOrders
+func (mmListOrders *mOrderRepositoryMockListOrders) ExpectAfterParam3(after *domain.OrderCursor) *mOrderRepositoryMockListOrders {
+	if mmListOrders.mock.funcListOrders != nil {
+		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Set")
+	}
+
+	if mmListOrders.defaultExpectation == nil {
+		mmListOrders.defaultExpectation = &OrderRepositoryMockListOrdersExpectation{}
+	}
+
+	if mmListOrders.defaultExpectation.params != nil {
+		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Expect")
+	}
+
+	if mmListOrders.defaultExpectation.paramPtrs == nil {
+		mmListOrders.defaultExpectation.paramPtrs = &OrderRepositoryMockListOrdersParamPtrs{}
+	}
+	mmListOrders.defaultExpectation.paramPtrs.after = &after
+	mmListOrders.defaultExpectation.expectationOrigins.originAfter = minimock.CallerInfo(1)
+
+	return mmListOrders
+}
+
+// ExpectLimitParam4 sets up expected param limit for OrderRepository.ListOrders
+func (mmListOrders *mOrderRepositoryMockListOrders) ExpectLimitParam4(limit int) *mOrderRepositoryMockListOrders {
+	if mmListOrders.mock.funcListOrders != nil {
+		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Set")
+	}
+
+	if mmListOrders.defaultExpectation == nil {
+		mmListOrders.defaultExpectation = &OrderRepositoryMockListOrdersExpectation{}
+	}
+
+	if mmListOrders.defaultExpectation.params != nil {
+		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Expect")
+	}
+
+	if mmListOrders.defaultExpectation.paramPtrs == nil {
+		mmListOrders.defaultExpectation.paramPtrs = &OrderRepositoryMockListOrdersParamPtrs{}
+	}
+	mmListOrders.defaultExpectation.paramPtrs.limit = &limit
+	mmListOrders.defaultExpectation.expectationOrigins.originLimit = minimock.CallerInfo(1)
+
+	return mmListOrders
+}
+
+// Inspect accepts an inspector function that has same arguments as the OrderRepository.ListOrders
+func (mmListOrders *mOrderRepositoryMockListOrders) Inspect(f func(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int)) *mOrderRepositoryMockListOrders {
+	if mmListOrders.mock.inspectFuncListOrders != nil {
+		mmListOrders.mock.t.Fatalf("Inspect function is already set for OrderRepositoryMock.ListOrders")
+	}
+
+	mmListOrders.mock.inspectFuncListOrders = f
+
+	return mmListOrders
+}
+
+// Return sets up results that will be returned by OrderRepository.ListOrders
+func (mmListOrders *mOrderRepositoryMockListOrders) Return(opa1 []*domain.Order, err error) *OrderRepositoryMock {
+	if mmListOrders.mock.funcListOrders != nil {
+		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Set")
+	}
+
+	if mmListOrders.defaultExpectation == nil {
 		mmListOrders.defaultExpectation = &OrderRepositoryMockListOrdersExpectation{mock: mmListOrders.mock}
 	}
-	mmListOrders.defaultExpectation.results = &OrderRepositoryMockListOrdersResults{opa1, err}
-	mmListOrders.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
-	return mmListOrders.mock
+	mmListOrders.defaultExpectation.results = &OrderRepositoryMockListOrdersResults{opa1, err}
+	mmListOrders.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmListOrders.mock
+}
+
+// Set uses given function f to mock the OrderRepository.ListOrders method
+func (mmListOrders *mOrderRepositoryMockListOrders) Set(f func(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) (opa1 []*domain.Order, err error)) *OrderRepositoryMock {
+	if mmListOrders.defaultExpectation != nil {
+		mmListOrders.mock.t.Fatalf("Default expectation is already set for the OrderRepository.ListOrders method")
+	}
+
+	if len(mmListOrders.expectations) > 0 {
+		mmListOrders.mock.t.Fatalf("Some expectations are already set for the OrderRepository.ListOrders method")
+	}
+
+	mmListOrders.mock.funcListOrders = f
+	mmListOrders.mock.funcListOrdersOrigin = minimock.CallerInfo(1)
+	return mmListOrders.mock
+}
+
+// When sets expectation for the OrderRepository.ListOrders which will trigger the result defined by the following
+// Then helper
+func (mmListOrders *mOrderRepositoryMockListOrders) When(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) *OrderRepositoryMockListOrdersExpectation {
+	if mmListOrders.mock.funcListOrders != nil {
+		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Set")
+	}
+
+	expectation := &OrderRepositoryMockListOrdersExpectation{
+		mock:               mmListOrders.mock,
+		params:             &OrderRepositoryMockListOrdersParams{ctx, filter, after, limit},
+		expectationOrigins: OrderRepositoryMockListOrdersExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmListOrders.expectations = append(mmListOrders.expectations, expectation)
+	return expectation
+}
+
+// Then sets up OrderRepository.ListOrders return parameters for the expectation previously defined by the When method
+func (e *OrderRepositoryMockListOrdersExpectation) Then(opa1 []*domain.Order, err error) *OrderRepositoryMock {
+	e.results = &OrderRepositoryMockListOrdersResults{opa1, err}
+	return e.mock
+}
+
+// Times sets number of times OrderRepository.ListOrders should be invoked
+func (mmListOrders *mOrderRepositoryMockListOrders) Times(n uint64) *mOrderRepositoryMockListOrders {
+	if n == 0 {
+		mmListOrders.mock.t.Fatalf("Times of OrderRepositoryMock.ListOrders mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmListOrders.expectedInvocations, n)
+	mmListOrders.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmListOrders
+}
+
+func (mmListOrders *mOrderRepositoryMockListOrders) invocationsDone() bool {
+	if len(mmListOrders.expectations) == 0 && mmListOrders.defaultExpectation == nil && mmListOrders.mock.funcListOrders == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmListOrders.mock.afterListOrdersCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmListOrders.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// ListOrders implements mm_interfaces.OrderRepository
+func (mmListOrders *OrderRepositoryMock) ListOrders(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) (opa1 []*domain.Order, err error) {
+	mm_atomic.AddUint64(&mmListOrders.beforeListOrdersCounter, 1)
+	defer mm_atomic.AddUint64(&mmListOrders.afterListOrdersCounter, 1)
+
+	mmListOrders.t.Helper()
+
+	if mmListOrders.inspectFuncListOrders != nil {
+		mmListOrders.inspectFuncListOrders(ctx, filter, after, limit)
+	}
+
+	mm_params := OrderRepositoryMockListOrdersParams{ctx, filter, after, limit}
+
+	// Record call args
+	mmListOrders.ListOrdersMock.mutex.Lock()
+	mmListOrders.ListOrdersMock.callArgs = append(mmListOrders.ListOrdersMock.callArgs, &mm_params)
+	mmListOrders.ListOrdersMock.mutex.Unlock()
+
+	for _, e := range mmListOrders.ListOrdersMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.opa1, e.results.err
+		}
+	}
+
+	if mmListOrders.ListOrdersMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmListOrders.ListOrdersMock.defaultExpectation.Counter, 1)
+		mm_want := mmListOrders.ListOrdersMock.defaultExpectation.params
+		mm_want_ptrs := mmListOrders.ListOrdersMock.defaultExpectation.paramPtrs
+
+		mm_got := OrderRepositoryMockListOrdersParams{ctx, filter, after, limit}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmListOrders.t.Errorf("OrderRepositoryMock.ListOrders got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListOrders.ListOrdersMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.filter != nil && !minimock.Equal(*mm_want_ptrs.filter, mm_got.filter) {
+				mmListOrders.t.Errorf("OrderRepositoryMock.ListOrders got unexpected parameter filter, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListOrders.ListOrdersMock.defaultExpectation.expectationOrigins.originFilter, *mm_want_ptrs.filter, mm_got.filter, minimock.Diff(*mm_want_ptrs.filter, mm_got.filter))
+			}
+
+			if mm_want_ptrs.after != nil && !minimock.Equal(*mm_want_ptrs.after, mm_got.after) {
+				mmListOrders.t.Errorf("OrderRepositoryMock.ListOrders got unexpected parameter after, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListOrders.ListOrdersMock.defaultExpectation.expectationOrigins.originAfter, *mm_want_ptrs.after, mm_got.after, minimock.Diff(*mm_want_ptrs.after, mm_got.after))
+			}
+
+			if mm_want_ptrs.limit != nil && !minimock.Equal(*mm_want_ptrs.limit, mm_got.limit) {
+				mmListOrders.t.Errorf("OrderRepositoryMock.ListOrders got unexpected parameter limit, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListOrders.ListOrdersMock.defaultExpectation.expectationOrigins.originLimit, *mm_want_ptrs.limit, mm_got.limit, minimock.Diff(*mm_want_ptrs.limit, mm_got.limit))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmListOrders.t.Errorf("OrderRepositoryMock.ListOrders got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmListOrders.ListOrdersMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmListOrders.ListOrdersMock.defaultExpectation.results
+		if mm_results == nil {
+			mmListOrders.t.Fatal("No results are set for the OrderRepositoryMock.ListOrders")
+		}
+		return (*mm_results).opa1, (*mm_results).err
+	}
+	if mmListOrders.funcListOrders != nil {
+		return mmListOrders.funcListOrders(ctx, filter, after, limit)
+	}
+	mmListOrders.t.Fatalf("Unexpected call to OrderRepositoryMock.ListOrders. %v %v %v %v", ctx, filter, after, limit)
+	return
+}
+
+// ListOrdersAfterCounter returns a count of finished OrderRepositoryMock.ListOrders invocations
+func (mmListOrders *OrderRepositoryMock) ListOrdersAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmListOrders.afterListOrdersCounter)
+}
+
+// ListOrdersBeforeCounter returns a count of OrderRepositoryMock.ListOrders invocations
+func (mmListOrders *OrderRepositoryMock) ListOrdersBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmListOrders.beforeListOrdersCounter)
+}
+
+// Calls returns a list of arguments used in each call to OrderRepositoryMock.ListOrders.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmListOrders *mOrderRepositoryMockListOrders) Calls() []*OrderRepositoryMockListOrdersParams {
+	mmListOrders.mutex.RLock()
+
+	argCopy := make([]*OrderRepositoryMockListOrdersParams, len(mmListOrders.callArgs))
+	copy(argCopy, mmListOrders.callArgs)
+
+	mmListOrders.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockListOrdersDone returns true if the count of the ListOrders invocations corresponds
+// the number of defined expectations
+func (m *OrderRepositoryMock) MinimockListOrdersDone() bool {
+	if m.ListOrdersMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.ListOrdersMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.ListOrdersMock.invocationsDone()
+}
+
+// MinimockListOrdersInspect logs each unmet expectation
+func (m *OrderRepositoryMock) MinimockListOrdersInspect() {
+	for _, e := range m.ListOrdersMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to OrderRepositoryMock.ListOrders at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterListOrdersCounter := mm_atomic.LoadUint64(&m.afterListOrdersCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ListOrdersMock.defaultExpectation != nil && afterListOrdersCounter < 1 {
+		if m.ListOrdersMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to OrderRepositoryMock.ListOrders at\n%s", m.ListOrdersMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to OrderRepositoryMock.ListOrders at\n%s with params: %#v", m.ListOrdersMock.defaultExpectation.expectationOrigins.origin, *m.ListOrdersMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcListOrders != nil && afterListOrdersCounter < 1 {
+		m.t.Errorf("Expected call to OrderRepositoryMock.ListOrders at\n%s", m.funcListOrdersOrigin)
+	}
+
+	if !m.ListOrdersMock.invocationsDone() && afterListOrdersCounter > 0 {
+		m.t.Errorf("Expected %d calls to OrderRepositoryMock.ListOrders at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.ListOrdersMock.expectedInvocations), m.ListOrdersMock.expectedInvocationsOrigin, afterListOrdersCounter)
+	}
+}
+
+type mOrderRepositoryMockListOrdersByRecipient struct {
+	optional           bool
+	mock               *OrderRepositoryMock
+	defaultExpectation *OrderRepositoryMockListOrdersByRecipientExpectation
+	expectations       []*OrderRepositoryMockListOrdersByRecipientExpectation
+
+	callArgs []*OrderRepositoryMockListOrdersByRecipientParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// OrderRepositoryMockListOrdersByRecipientExpectation specifies expectation struct of the OrderRepository.ListOrdersByRecipient
+type OrderRepositoryMockListOrdersByRecipientExpectation struct {
+	mock               *OrderRepositoryMock
+	params             *OrderRepositoryMockListOrdersByRecipientParams
+	paramPtrs          *OrderRepositoryMockListOrdersByRecipientParamPtrs
+	expectationOrigins OrderRepositoryMockListOrdersByRecipientExpectationOrigins
+	results            *OrderRepositoryMockListOrdersByRecipientResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// OrderRepositoryMockListOrdersByRecipientParams contains parameters of the OrderRepository.ListOrdersByRecipient
+type OrderRepositoryMockListOrdersByRecipientParams struct {
+	ctx         context.Context
+	recipientID string
+	after       *domain.OrderCursor
+	limit       int
+}
+
+// OrderRepositoryMockListOrdersByRecipientParamPtrs contains pointers to parameters of the OrderRepository.ListOrdersByRecipient
+type OrderRepositoryMockListOrdersByRecipientParamPtrs struct {
+	ctx         *context.Context
+	recipientID *string
+	after       **domain.OrderCursor
+	limit       *int
+}
+
+// OrderRepositoryMockListOrdersByRecipientResults contains results of the OrderRepository.ListOrdersByRecipient
+type OrderRepositoryMockListOrdersByRecipientResults struct {
+	opa1 []*domain.Order
+	err  error
+}
+
+// OrderRepositoryMockListOrdersByRecipientOrigins contains origins of expectations of the OrderRepository.ListOrdersByRecipient
+type OrderRepositoryMockListOrdersByRecipientExpectationOrigins struct {
+	origin            string
+	originCtx         string
+	originRecipientID string
+	originAfter       string
+	originLimit       string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmListOrdersByRecipient *mOrderRepositoryMockListOrdersByRecipient) Optional() *mOrderRepositoryMockListOrdersByRecipient {
+	mmListOrdersByRecipient.optional = true
+	return mmListOrdersByRecipient
+}
+
+// Expect sets up expected params for OrderRepository.ListOrdersByRecipient
+func (mmListOrdersByRecipient *mOrderRepositoryMockListOrdersByRecipient) Expect(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int) *mOrderRepositoryMockListOrdersByRecipient {
+	if mmListOrdersByRecipient.mock.funcListOrdersByRecipient != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("OrderRepositoryMock.ListOrdersByRecipient mock is already set by Set")
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation == nil {
+		mmListOrdersByRecipient.defaultExpectation = &OrderRepositoryMockListOrdersByRecipientExpectation{}
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation.paramPtrs != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("OrderRepositoryMock.ListOrdersByRecipient mock is already set by ExpectParams functions")
+	}
+
+	mmListOrdersByRecipient.defaultExpectation.params = &OrderRepositoryMockListOrdersByRecipientParams{ctx, recipientID, after, limit}
+	mmListOrdersByRecipient.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmListOrdersByRecipient.expectations {
+		if minimock.Equal(e.params, mmListOrdersByRecipient.defaultExpectation.params) {
+			mmListOrdersByRecipient.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmListOrdersByRecipient.defaultExpectation.params)
+		}
+	}
+
+	return mmListOrdersByRecipient
+}
+
+// ExpectCtxParam1 sets up expected param ctx for OrderRepository.ListOrdersByRecipient
+func (mmListOrdersByRecipient *mOrderRepositoryMockListOrdersByRecipient) ExpectCtxParam1(ctx context.Context) *mOrderRepositoryMockListOrdersByRecipient {
+	if mmListOrdersByRecipient.mock.funcListOrdersByRecipient != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("OrderRepositoryMock.ListOrdersByRecipient mock is already set by Set")
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation == nil {
+		mmListOrdersByRecipient.defaultExpectation = &OrderRepositoryMockListOrdersByRecipientExpectation{}
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation.params != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("OrderRepositoryMock.ListOrdersByRecipient mock is already set by Expect")
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation.paramPtrs == nil {
+		mmListOrdersByRecipient.defaultExpectation.paramPtrs = &OrderRepositoryMockListOrdersByRecipientParamPtrs{}
+	}
+	mmListOrdersByRecipient.defaultExpectation.paramPtrs.ctx = &ctx
+	mmListOrdersByRecipient.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmListOrdersByRecipient
+}
+
+// ExpectRecipientIDParam2 sets up expected param recipientID for OrderRepository.ListOrdersByRecipient
+func (mmListOrdersByRecipient *mOrderRepositoryMockListOrdersByRecipient) ExpectRecipientIDParam2(recipientID string) *mOrderRepositoryMockListOrdersByRecipient {
+	if mmListOrdersByRecipient.mock.funcListOrdersByRecipient != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("OrderRepositoryMock.ListOrdersByRecipient mock is already set by Set")
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation == nil {
+		mmListOrdersByRecipient.defaultExpectation = &OrderRepositoryMockListOrdersByRecipientExpectation{}
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation.params != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("OrderRepositoryMock.ListOrdersByRecipient mock is already set by Expect")
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation.paramPtrs == nil {
+		mmListOrdersByRecipient.defaultExpectation.paramPtrs = &OrderRepositoryMockListOrdersByRecipientParamPtrs{}
+	}
+	mmListOrdersByRecipient.defaultExpectation.paramPtrs.recipientID = &recipientID
+	mmListOrdersByRecipient.defaultExpectation.expectationOrigins.originRecipientID = minimock.CallerInfo(1)
+
+	return mmListOrdersByRecipient
+}
+
+// ExpectAfterParam3 sets up expected param after for OrderRepository.ListOrdersByRecipient
+func (mmListOrdersByRecipient *mOrderRepositoryMockListOrdersByRecipient) ExpectAfterParam3(after *domain.OrderCursor) *mOrderRepositoryMockListOrdersByRecipient {
+	if mmListOrdersByRecipient.mock.funcListOrdersByRecipient != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("OrderRepositoryMock.ListOrdersByRecipient mock is already set by Set")
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation == nil {
+		mmListOrdersByRecipient.defaultExpectation = &OrderRepositoryMockListOrdersByRecipientExpectation{}
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation.params != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("OrderRepositoryMock.ListOrdersByRecipient mock is already set by Expect")
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation.paramPtrs == nil {
+		mmListOrdersByRecipient.defaultExpectation.paramPtrs = &OrderRepositoryMockListOrdersByRecipientParamPtrs{}
+	}
+	mmListOrdersByRecipient.defaultExpectation.paramPtrs.after = &after
+	mmListOrdersByRecipient.defaultExpectation.expectationOrigins.originAfter = minimock.CallerInfo(1)
+
+	return mmListOrdersByRecipient
+}
+
+// ExpectLimitParam4 sets up expected param limit for OrderRepository.ListOrdersByRecipient
+func (mmListOrdersByRecipient *mOrderRepositoryMockListOrdersByRecipient) ExpectLimitParam4(limit int) *mOrderRepositoryMockListOrdersByRecipient {
+	if mmListOrdersByRecipient.mock.funcListOrdersByRecipient != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("OrderRepositoryMock.ListOrdersByRecipient mock is already set by Set")
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation == nil {
+		mmListOrdersByRecipient.defaultExpectation = &OrderRepositoryMockListOrdersByRecipientExpectation{}
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation.params != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("OrderRepositoryMock.ListOrdersByRecipient mock is already set by Expect")
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation.paramPtrs == nil {
+		mmListOrdersByRecipient.defaultExpectation.paramPtrs = &OrderRepositoryMockListOrdersByRecipientParamPtrs{}
+	}
+	mmListOrdersByRecipient.defaultExpectation.paramPtrs.limit = &limit
+	mmListOrdersByRecipient.defaultExpectation.expectationOrigins.originLimit = minimock.CallerInfo(1)
+
+	return mmListOrdersByRecipient
+}
+
+// Inspect accepts an inspector function that has same arguments as the OrderRepository.ListOrdersByRecipient
+func (mmListOrdersByRecipient *mOrderRepositoryMockListOrdersByRecipient) Inspect(f func(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int)) *mOrderRepositoryMockListOrdersByRecipient {
+	if mmListOrdersByRecipient.mock.inspectFuncListOrdersByRecipient != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("Inspect function is already set for OrderRepositoryMock.ListOrdersByRecipient")
+	}
+
+	mmListOrdersByRecipient.mock.inspectFuncListOrdersByRecipient = f
+
+	return mmListOrdersByRecipient
+}
+
+// Return sets up results that will be returned by OrderRepository.ListOrdersByRecipient
+func (mmListOrdersByRecipient *mOrderRepositoryMockListOrdersByRecipient) Return(opa1 []*domain.Order, err error) *OrderRepositoryMock {
+	if mmListOrdersByRecipient.mock.funcListOrdersByRecipient != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("OrderRepositoryMock.ListOrdersByRecipient mock is already set by Set")
+	}
+
+	if mmListOrdersByRecipient.defaultExpectation == nil {
+		mmListOrdersByRecipient.defaultExpectation = &OrderRepositoryMockListOrdersByRecipientExpectation{mock: mmListOrdersByRecipient.mock}
+	}
+	mmListOrdersByRecipient.defaultExpectation.results = &OrderRepositoryMockListOrdersByRecipientResults{opa1, err}
+	mmListOrdersByRecipient.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmListOrdersByRecipient.mock
 }
 
-// Set uses given function f to mock the OrderRepository.ListOrders method
-func (mmListOrders *mOrderRepositoryMockListOrders) Set(f func(ctx context.Context) (opa1 []*domain.Order, err error)) *OrderRepositoryMock {
-	if mmListOrders.defaultExpectation != nil {
-		mmListOrders.mock.t.Fatalf("Default expectation is already set for the OrderRepository.ListOrders method")
+// Set uses given function f to mock the OrderRepository.ListOrdersByRecipient method
+func (mmListOrdersByRecipient *mOrderRepositoryMockListOrdersByRecipient) Set(f func(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int) (opa1 []*domain.Order, err error)) *OrderRepositoryMock {
+	if mmListOrdersByRecipient.defaultExpectation != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("Default expectation is already set for the OrderRepository.ListOrdersByRecipient method")
 	}
 
-	if len(mmListOrders.expectations) > 0 {
-		mmListOrders.mock.t.Fatalf("Some expectations are already set for the OrderRepository.ListOrders method")
+	if len(mmListOrdersByRecipient.expectations) > 0 {
+		mmListOrdersByRecipient.mock.t.Fatalf("Some expectations are already set for the OrderRepository.ListOrdersByRecipient method")
 	}
 
-	mmListOrders.mock.funcListOrders = f
-	mmListOrders.mock.funcListOrdersOrigin = minimock.CallerInfo(1)
-	return mmListOrders.mock
+	mmListOrdersByRecipient.mock.funcListOrdersByRecipient = f
+	mmListOrdersByRecipient.mock.funcListOrdersByRecipientOrigin = minimock.CallerInfo(1)
+	return mmListOrdersByRecipient.mock
 }
 
-// When sets expectation for the OrderRepository.ListOrders which will trigger the result defined by the following
+// When sets expectation for the OrderRepository.ListOrdersByRecipient which will trigger the result defined by the following
 // Then helper
-func (mmListOrders *mOrderRepositoryMockListOrders) When(ctx context.Context) *OrderRepositoryMockListOrdersExpectation {
-	if mmListOrders.mock.funcListOrders != nil {
-		mmListOrders.mock.t.Fatalf("OrderRepositoryMock.ListOrders mock is already set by Set")
+func (mmListOrdersByRecipient *mOrderRepositoryMockListOrdersByRecipient) When(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int) *OrderRepositoryMockListOrdersByRecipientExpectation {
+	if mmListOrdersByRecipient.mock.funcListOrdersByRecipient != nil {
+		mmListOrdersByRecipient.mock.t.Fatalf("OrderRepositoryMock.ListOrdersByRecipient mock is already set by Set")
 	}
 
-	expectation := &OrderRepositoryMockListOrdersExpectation{
-		mock:               mmListOrders.mock,
-		params:             &OrderRepositoryMockListOrdersParams{ctx},
-		expectationOrigins: OrderRepositoryMockListOrdersExpectationOrigins{origin: minimock.CallerInfo(1)},
+	expectation := &OrderRepositoryMockListOrdersByRecipientExpectation{
+		mock:               mmListOrdersByRecipient.mock,
+		params:             &OrderRepositoryMockListOrdersByRecipientParams{ctx, recipientID, after, limit},
+		expectationOrigins: OrderRepositoryMockListOrdersByRecipientExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
-	mmListOrders.expectations = append(mmListOrders.expectations, expectation)
+	mmListOrdersByRecipient.expectations = append(mmListOrdersByRecipient.expectations, expectation)
 	return expectation
 }
 
-// Then sets up OrderRepository.ListOrders return parameters for the expectation previously defined by the When method
-func (e *OrderRepositoryMockListOrdersExpectation) Then(opa1 []*domain.Order, err error) *OrderRepositoryMock {
-	e.results = &OrderRepositoryMockListOrdersResults{opa1, err}
+// Then sets up OrderRepository.ListOrdersByRecipient return parameters for the expectation previously defined by the When method
+func (e *OrderRepositoryMockListOrdersByRecipientExpectation) Then(opa1 []*domain.Order, err error) *OrderRepositoryMock {
+	e.results = &OrderRepositoryMockListOrdersByRecipientResults{opa1, err}
 	return e.mock
 }
 
-// Times sets number of times OrderRepository.ListOrders should be invoked
-func (mmListOrders *mOrderRepositoryMockListOrders) Times(n uint64) *mOrderRepositoryMockListOrders {
+// Times sets number of times OrderRepository.ListOrdersByRecipient should be invoked
+func (mmListOrdersByRecipient *mOrderRepositoryMockListOrdersByRecipient) Times(n uint64) *mOrderRepositoryMockListOrdersByRecipient {
 	if n == 0 {
-		mmListOrders.mock.t.Fatalf("Times of OrderRepositoryMock.ListOrders mock can not be zero")
+		mmListOrdersByRecipient.mock.t.Fatalf("Times of OrderRepositoryMock.ListOrdersByRecipient mock can not be zero")
 	}
-	mm_atomic.StoreUint64(&mmListOrders.expectedInvocations, n)
-	mmListOrders.expectedInvocationsOrigin = minimock.CallerInfo(1)
-	return mmListOrders
+	mm_atomic.StoreUint64(&mmListOrdersByRecipient.expectedInvocations, n)
+	mmListOrdersByRecipient.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmListOrdersByRecipient
 }
 
-func (mmListOrders *mOrderRepositoryMockListOrders) invocationsDone() bool {
-	if len(mmListOrders.expectations) == 0 && mmListOrders.defaultExpectation == nil && mmListOrders.mock.funcListOrders == nil {
+func (mmListOrdersByRecipient *mOrderRepositoryMockListOrdersByRecipient) invocationsDone() bool {
+	if len(mmListOrdersByRecipient.expectations) == 0 && mmListOrdersByRecipient.defaultExpectation == nil && mmListOrdersByRecipient.mock.funcListOrdersByRecipient == nil {
 		return true
 	}
 
-	totalInvocations := mm_atomic.LoadUint64(&mmListOrders.mock.afterListOrdersCounter)
-	expectedInvocations := mm_atomic.LoadUint64(&mmListOrders.expectedInvocations)
+	totalInvocations := mm_atomic.LoadUint64(&mmListOrdersByRecipient.mock.afterListOrdersByRecipientCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmListOrdersByRecipient.expectedInvocations)
 
 	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
 }
 
-// ListOrders implements mm_repository.OrderRepository
-func (mmListOrders *OrderRepositoryMock) ListOrders(ctx context.Context) (opa1 []*domain.Order, err error) {
-	mm_atomic.AddUint64(&mmListOrders.beforeListOrdersCounter, 1)
-	defer mm_atomic.AddUint64(&mmListOrders.afterListOrdersCounter, 1)
+// ListOrdersByRecipient implements mm_interfaces.OrderRepository
+func (mmListOrdersByRecipient *OrderRepositoryMock) ListOrdersByRecipient(ctx context.Context, recipientID string, after *domain.OrderCursor, limit int) (opa1 []*domain.Order, err error) {
+	mm_atomic.AddUint64(&mmListOrdersByRecipient.beforeListOrdersByRecipientCounter, 1)
+	defer mm_atomic.AddUint64(&mmListOrdersByRecipient.afterListOrdersByRecipientCounter, 1)
 
-	mmListOrders.t.Helper()
+	mmListOrdersByRecipient.t.Helper()
 
-	if mmListOrders.inspectFuncListOrders != nil {
-		mmListOrders.inspectFuncListOrders(ctx)
+	if mmListOrdersByRecipient.inspectFuncListOrdersByRecipient != nil {
+		mmListOrdersByRecipient.inspectFuncListOrdersByRecipient(ctx, recipientID, after, limit)
 	}
 
-	mm_params := OrderRepositoryMockListOrdersParams{ctx}
+	mm_params := OrderRepositoryMockListOrdersByRecipientParams{ctx, recipientID, after, limit}
 
 	// Record call args
-	mmListOrders.ListOrdersMock.mutex.Lock()
-	mmListOrders.ListOrdersMock.callArgs = append(mmListOrders.ListOrdersMock.callArgs, &mm_params)
-	mmListOrders.ListOrdersMock.mutex.Unlock()
+	mmListOrdersByRecipient.ListOrdersByRecipientMock.mutex.Lock()
+	mmListOrdersByRecipient.ListOrdersByRecipientMock.callArgs = append(mmListOrdersByRecipient.ListOrdersByRecipientMock.callArgs, &mm_params)
+	mmListOrdersByRecipient.ListOrdersByRecipientMock.mutex.Unlock()
 
-	for _, e := range mmListOrders.ListOrdersMock.expectations {
+	for _, e := range mmListOrdersByRecipient.ListOrdersByRecipientMock.expectations {
 		if minimock.Equal(*e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
 			return e.results.opa1, e.results.err
 		}
 	}
 
-	if mmListOrders.ListOrdersMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmListOrders.ListOrdersMock.defaultExpectation.Counter, 1)
-		mm_want := mmListOrders.ListOrdersMock.defaultExpectation.params
-		mm_want_ptrs := mmListOrders.ListOrdersMock.defaultExpectation.paramPtrs
+	if mmListOrdersByRecipient.ListOrdersByRecipientMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmListOrdersByRecipient.ListOrdersByRecipientMock.defaultExpectation.Counter, 1)
+		mm_want := mmListOrdersByRecipient.ListOrdersByRecipientMock.defaultExpectation.params
+		mm_want_ptrs := mmListOrdersByRecipient.ListOrdersByRecipientMock.defaultExpectation.paramPtrs
 
-		mm_got := OrderRepositoryMockListOrdersParams{ctx}
+		mm_got := OrderRepositoryMockListOrdersByRecipientParams{ctx, recipientID, after, limit}
 
 		if mm_want_ptrs != nil {
 
 			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
-				mmListOrders.t.Errorf("OrderRepositoryMock.ListOrders got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
-					mmListOrders.ListOrdersMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+				mmListOrdersByRecipient.t.Errorf("OrderRepositoryMock.ListOrdersByRecipient got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListOrdersByRecipient.ListOrdersByRecipientMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.recipientID != nil && !minimock.Equal(*mm_want_ptrs.recipientID, mm_got.recipientID) {
+				mmListOrdersByRecipient.t.Errorf("OrderRepositoryMock.ListOrdersByRecipient got unexpected parameter recipientID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListOrdersByRecipient.ListOrdersByRecipientMock.defaultExpectation.expectationOrigins.originRecipientID, *mm_want_ptrs.recipientID, mm_got.recipientID, minimock.Diff(*mm_want_ptrs.recipientID, mm_got.recipientID))
+			}
+
+			if mm_want_ptrs.after != nil && !minimock.Equal(*mm_want_ptrs.after, mm_got.after) {
+				mmListOrdersByRecipient.t.Errorf("OrderRepositoryMock.ListOrdersByRecipient got unexpected parameter after, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListOrdersByRecipient.ListOrdersByRecipientMock.defaultExpectation.expectationOrigins.originAfter, *mm_want_ptrs.after, mm_got.after, minimock.Diff(*mm_want_ptrs.after, mm_got.after))
+			}
+
+			if mm_want_ptrs.limit != nil && !minimock.Equal(*mm_want_ptrs.limit, mm_got.limit) {
+				mmListOrdersByRecipient.t.Errorf("OrderRepositoryMock.ListOrdersByRecipient got unexpected parameter limit, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListOrdersByRecipient.ListOrdersByRecipientMock.defaultExpectation.expectationOrigins.originLimit, *mm_want_ptrs.limit, mm_got.limit, minimock.Diff(*mm_want_ptrs.limit, mm_got.limit))
 			}
 
 		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmListOrders.t.Errorf("OrderRepositoryMock.ListOrders got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
-				mmListOrders.ListOrdersMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmListOrdersByRecipient.t.Errorf("OrderRepositoryMock.ListOrdersByRecipient got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmListOrdersByRecipient.ListOrdersByRecipientMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmListOrders.ListOrdersMock.defaultExpectation.results
+		mm_results := mmListOrdersByRecipient.ListOrdersByRecipientMock.defaultExpectation.results
 		if mm_results == nil {
-			mmListOrders.t.Fatal("No results are set for the OrderRepositoryMock.ListOrders")
+			mmListOrdersByRecipient.t.Fatal("No results are set for the OrderRepositoryMock.ListOrdersByRecipient")
 		}
 		return (*mm_results).opa1, (*mm_results).err
 	}
-	if mmListOrders.funcListOrders != nil {
-		return mmListOrders.funcListOrders(ctx)
+	if mmListOrdersByRecipient.funcListOrdersByRecipient != nil {
+		return mmListOrdersByRecipient.funcListOrdersByRecipient(ctx, recipientID, after, limit)
 	}
-	mmListOrders.t.Fatalf("Unexpected call to OrderRepositoryMock.ListOrders. %v", ctx)
+	mmListOrdersByRecipient.t.Fatalf("Unexpected call to OrderRepositoryMock.ListOrdersByRecipient. %v %v %v %v", ctx, recipientID, after, limit)
 	return
 }
 
-// ListOrdersAfterCounter returns a count of finished OrderRepositoryMock.ListOrders invocations
-func (mmListOrders *OrderRepositoryMock) ListOrdersAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmListOrders.afterListOrdersCounter)
+// ListOrdersByRecipientAfterCounter returns a count of finished OrderRepositoryMock.ListOrdersByRecipient invocations
+func (mmListOrdersByRecipient *OrderRepositoryMock) ListOrdersByRecipientAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmListOrdersByRecipient.afterListOrdersByRecipientCounter)
 }
 
-// ListOrdersBeforeCounter returns a count of OrderRepositoryMock.ListOrders invocations
-func (mmListOrders *OrderRepositoryMock) ListOrdersBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmListOrders.beforeListOrdersCounter)
+// ListOrdersByRecipientBeforeCounter returns a count of OrderRepositoryMock.ListOrdersByRecipient invocations
+func (mmListOrdersByRecipient *OrderRepositoryMock) ListOrdersByRecipientBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmListOrdersByRecipient.beforeListOrdersByRecipientCounter)
 }
 
-// Calls returns a list of arguments used in each call to OrderRepositoryMock.ListOrders.
+// Calls returns a list of arguments used in each call to OrderRepositoryMock.ListOrdersByRecipient.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmListOrders *mOrderRepositoryMockListOrders) Calls() []*OrderRepositoryMockListOrdersParams {
-	mmListOrders.mutex.RLock()
+func (mmListOrdersByRecipient *mOrderRepositoryMockListOrdersByRecipient) Calls() []*OrderRepositoryMockListOrdersByRecipientParams {
+	mmListOrdersByRecipient.mutex.RLock()
 
-	argCopy := make([]*OrderRepositoryMockListOrdersParams, len(mmListOrders.callArgs))
-	copy(argCopy, mmListOrders.callArgs)
+	argCopy := make([]*OrderRepositoryMockListOrdersByRecipientParams, len(mmListOrdersByRecipient.callArgs))
+	copy(argCopy, mmListOrdersByRecipient.callArgs)
 
-	mmListOrders.mutex.RUnlock()
+	mmListOrdersByRecipient.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockListOrdersDone returns true if the count of the ListOrders invocations corresponds
+// MinimockListOrdersByRecipientDone returns true if the count of the ListOrdersByRecipient invocations corresponds
 // the number of defined expectations
-func (m *OrderRepositoryMock) MinimockListOrdersDone() bool {
-	if m.ListOrdersMock.optional {
+func (m *OrderRepositoryMock) MinimockListOrdersByRecipientDone() bool {
+	if m.ListOrdersByRecipientMock.optional {
 		// Optional methods provide '0 or more' call count restriction.
 		return true
 	}
 
-	for _, e := range m.ListOrdersMock.expectations {
+	for _, e := range m.ListOrdersByRecipientMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
-	return m.ListOrdersMock.invocationsDone()
+	return m.ListOrdersByRecipientMock.invocationsDone()
 }
 
-// MinimockListOrdersInspect logs each unmet expectation
-func (m *OrderRepositoryMock) MinimockListOrdersInspect() {
-	for _, e := range m.ListOrdersMock.expectations {
+// MinimockListOrdersByRecipientInspect logs each unmet expectation
+func (m *OrderRepositoryMock) MinimockListOrdersByRecipientInspect() {
+	for _, e := range m.ListOrdersByRecipientMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to OrderRepositoryMock.ListOrders at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+			m.t.Errorf("Expected call to OrderRepositoryMock.ListOrdersByRecipient at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
 		}
 	}
 
-	afterListOrdersCounter := mm_atomic.LoadUint64(&m.afterListOrdersCounter)
+	afterListOrdersByRecipientCounter := mm_atomic.LoadUint64(&m.afterListOrdersByRecipientCounter)
 	// if default expectation was set then invocations count should be greater than zero
-	if m.ListOrdersMock.defaultExpectation != nil && afterListOrdersCounter < 1 {
-		if m.ListOrdersMock.defaultExpectation.params == nil {
-			m.t.Errorf("Expected call to OrderRepositoryMock.ListOrders at\n%s", m.ListOrdersMock.defaultExpectation.returnOrigin)
+	if m.ListOrdersByRecipientMock.defaultExpectation != nil && afterListOrdersByRecipientCounter < 1 {
+		if m.ListOrdersByRecipientMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to OrderRepositoryMock.ListOrdersByRecipient at\n%s", m.ListOrdersByRecipientMock.defaultExpectation.returnOrigin)
 		} else {
-			m.t.Errorf("Expected call to OrderRepositoryMock.ListOrders at\n%s with params: %#v", m.ListOrdersMock.defaultExpectation.expectationOrigins.origin, *m.ListOrdersMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to OrderRepositoryMock.ListOrdersByRecipient at\n%s with params: %#v", m.ListOrdersByRecipientMock.defaultExpectation.expectationOrigins.origin, *m.ListOrdersByRecipientMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcListOrders != nil && afterListOrdersCounter < 1 {
-		m.t.Errorf("Expected call to OrderRepositoryMock.ListOrders at\n%s", m.funcListOrdersOrigin)
+	if m.funcListOrdersByRecipient != nil && afterListOrdersByRecipientCounter < 1 {
+		m.t.Errorf("Expected call to OrderRepositoryMock.ListOrdersByRecipient at\n%s", m.funcListOrdersByRecipientOrigin)
 	}
 
-	if !m.ListOrdersMock.invocationsDone() && afterListOrdersCounter > 0 {
-		m.t.Errorf("Expected %d calls to OrderRepositoryMock.ListOrders at\n%s but found %d calls",
-			mm_atomic.LoadUint64(&m.ListOrdersMock.expectedInvocations), m.ListOrdersMock.expectedInvocationsOrigin, afterListOrdersCounter)
+	if !m.ListOrdersByRecipientMock.invocationsDone() && afterListOrdersByRecipientCounter > 0 {
+		m.t.Errorf("Expected %d calls to OrderRepositoryMock.ListOrdersByRecipient at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.ListOrdersByRecipientMock.expectedInvocations), m.ListOrdersByRecipientMock.expectedInvocationsOrigin, afterListOrdersByRecipientCounter)
 	}
 }
 
@@ -1632,7 +2886,7 @@ func (mmUpdateOrder *mOrderRepositoryMockUpdateOrder) invocationsDone() bool {
 	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
 }
 
-// UpdateOrder implements mm_repository.OrderRepository
+// UpdateOrder implements mm_interfaces.OrderRepository
 func (mmUpdateOrder *OrderRepositoryMock) UpdateOrder(ctx context.Context, order *domain.Order) (err error) {
 	mm_atomic.AddUint64(&mmUpdateOrder.beforeUpdateOrderCounter, 1)
 	defer mm_atomic.AddUint64(&mmUpdateOrder.afterUpdateOrderCounter, 1)
@@ -1762,19 +3016,369 @@ func (m *OrderRepositoryMock) MinimockUpdateOrderInspect() {
 	}
 }
 
+type mOrderRepositoryMockUpdateOrders struct {
+	optional           bool
+	mock               *OrderRepositoryMock
+	defaultExpectation *OrderRepositoryMockUpdateOrdersExpectation
+	expectations       []*OrderRepositoryMockUpdateOrdersExpectation
+
+	callArgs []*OrderRepositoryMockUpdateOrdersParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// OrderRepositoryMockUpdateOrdersExpectation specifies expectation struct of the OrderRepository.UpdateOrders
+type OrderRepositoryMockUpdateOrdersExpectation struct {
+	mock               *OrderRepositoryMock
+	params             *OrderRepositoryMockUpdateOrdersParams
+	paramPtrs          *OrderRepositoryMockUpdateOrdersParamPtrs
+	expectationOrigins OrderRepositoryMockUpdateOrdersExpectationOrigins
+	results            *OrderRepositoryMockUpdateOrdersResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// OrderRepositoryMockUpdateOrdersParams contains parameters of the OrderRepository.UpdateOrders
+type OrderRepositoryMockUpdateOrdersParams struct {
+	ctx    context.Context
+	orders []*domain.Order
+}
+
+// OrderRepositoryMockUpdateOrdersParamPtrs contains pointers to parameters of the OrderRepository.UpdateOrders
+type OrderRepositoryMockUpdateOrdersParamPtrs struct {
+	ctx    *context.Context
+	orders *[]*domain.Order
+}
+
+// OrderRepositoryMockUpdateOrdersResults contains results of the OrderRepository.UpdateOrders
+type OrderRepositoryMockUpdateOrdersResults struct {
+	err error
+}
+
+// OrderRepositoryMockUpdateOrdersOrigins contains origins of expectations of the OrderRepository.UpdateOrders
+type OrderRepositoryMockUpdateOrdersExpectationOrigins struct {
+	origin       string
+	originCtx    string
+	originOrders string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmUpdateOrders *mOrderRepositoryMockUpdateOrders) Optional() *mOrderRepositoryMockUpdateOrders {
+	mmUpdateOrders.optional = true
+	return mmUpdateOrders
+}
+
+// Expect sets up expected params for OrderRepository.UpdateOrders
+func (mmUpdateOrders *mOrderRepositoryMockUpdateOrders) Expect(ctx context.Context, orders []*domain.Order) *mOrderRepositoryMockUpdateOrders {
+	if mmUpdateOrders.mock.funcUpdateOrders != nil {
+		mmUpdateOrders.mock.t.Fatalf("OrderRepositoryMock.UpdateOrders mock is already set by Set")
+	}
+
+	if mmUpdateOrders.defaultExpectation == nil {
+		mmUpdateOrders.defaultExpectation = &OrderRepositoryMockUpdateOrdersExpectation{}
+	}
+
+	if mmUpdateOrders.defaultExpectation.paramPtrs != nil {
+		mmUpdateOrders.mock.t.Fatalf("OrderRepositoryMock.UpdateOrders mock is already set by ExpectParams functions")
+	}
+
+	mmUpdateOrders.defaultExpectation.params = &OrderRepositoryMockUpdateOrdersParams{ctx, orders}
+	mmUpdateOrders.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmUpdateOrders.expectations {
+		if minimock.Equal(e.params, mmUpdateOrders.defaultExpectation.params) {
+			mmUpdateOrders.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmUpdateOrders.defaultExpectation.params)
+		}
+	}
+
+	return mmUpdateOrders
+}
+
+// ExpectCtxParam1 sets up expected param ctx for OrderRepository.UpdateOrders
+func (mmUpdateOrders *mOrderRepositoryMockUpdateOrders) ExpectCtxParam1(ctx context.Context) *mOrderRepositoryMockUpdateOrders {
+	if mmUpdateOrders.mock.funcUpdateOrders != nil {
+		mmUpdateOrders.mock.t.Fatalf("OrderRepositoryMock.UpdateOrders mock is already set by Set")
+	}
+
+	if mmUpdateOrders.defaultExpectation == nil {
+		mmUpdateOrders.defaultExpectation = &OrderRepositoryMockUpdateOrdersExpectation{}
+	}
+
+	if mmUpdateOrders.defaultExpectation.params != nil {
+		mmUpdateOrders.mock.t.Fatalf("OrderRepositoryMock.UpdateOrders mock is already set by Expect")
+	}
+
+	if mmUpdateOrders.defaultExpectation.paramPtrs == nil {
+		mmUpdateOrders.defaultExpectation.paramPtrs = &OrderRepositoryMockUpdateOrdersParamPtrs{}
+	}
+	mmUpdateOrders.defaultExpectation.paramPtrs.ctx = &ctx
+	mmUpdateOrders.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmUpdateOrders
+}
+
+// ExpectOrdersParam2 sets up expected param orders for OrderRepository.UpdateOrders
+func (mmUpdateOrders *mOrderRepositoryMockUpdateOrders) ExpectOrdersParam2(orders []*domain.Order) *mOrderRepositoryMockUpdateOrders {
+	if mmUpdateOrders.mock.funcUpdateOrders != nil {
+		mmUpdateOrders.mock.t.Fatalf("OrderRepositoryMock.UpdateOrders mock is already set by Set")
+	}
+
+	if mmUpdateOrders.defaultExpectation == nil {
+		mmUpdateOrders.defaultExpectation = &OrderRepositoryMockUpdateOrdersExpectation{}
+	}
+
+	if mmUpdateOrders.defaultExpectation.params != nil {
+		mmUpdateOrders.mock.t.Fatalf("OrderRepositoryMock.UpdateOrders mock is already set by Expect")
+	}
+
+	if mmUpdateOrders.defaultExpectation.paramPtrs == nil {
+		mmUpdateOrders.defaultExpectation.paramPtrs = &OrderRepositoryMockUpdateOrdersParamPtrs{}
+	}
+	mmUpdateOrders.defaultExpectation.paramPtrs.orders = &orders
+	mmUpdateOrders.defaultExpectation.expectationOrigins.originOrders = minimock.CallerInfo(1)
+
+	return mmUpdateOrders
+}
+
+// Inspect accepts an inspector function that has same arguments as the OrderRepository.UpdateOrders
+func (mmUpdateOrders *mOrderRepositoryMockUpdateOrders) Inspect(f func(ctx context.Context, orders []*domain.Order)) *mOrderRepositoryMockUpdateOrders {
+	if mmUpdateOrders.mock.inspectFuncUpdateOrders != nil {
+		mmUpdateOrders.mock.t.Fatalf("Inspect function is already set for OrderRepositoryMock.UpdateOrders")
+	}
+
+	mmUpdateOrders.mock.inspectFuncUpdateOrders = f
+
+	return mmUpdateOrders
+}
+
+// Return sets up results that will be returned by OrderRepository.UpdateOrders
+func (mmUpdateOrders *mOrderRepositoryMockUpdateOrders) Return(err error) *OrderRepositoryMock {
+	if mmUpdateOrders.mock.funcUpdateOrders != nil {
+		mmUpdateOrders.mock.t.Fatalf("OrderRepositoryMock.UpdateOrders mock is already set by Set")
+	}
+
+	if mmUpdateOrders.defaultExpectation == nil {
+		mmUpdateOrders.defaultExpectation = &OrderRepositoryMockUpdateOrdersExpectation{mock: mmUpdateOrders.mock}
+	}
+	mmUpdateOrders.defaultExpectation.results = &OrderRepositoryMockUpdateOrdersResults{err}
+	mmUpdateOrders.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmUpdateOrders.mock
+}
+
+// Set uses given function f to mock the OrderRepository.UpdateOrders method
+func (mmUpdateOrders *mOrderRepositoryMockUpdateOrders) Set(f func(ctx context.Context, orders []*domain.Order) (err error)) *OrderRepositoryMock {
+	if mmUpdateOrders.defaultExpectation != nil {
+		mmUpdateOrders.mock.t.Fatalf("Default expectation is already set for the OrderRepository.UpdateOrders method")
+	}
+
+	if len(mmUpdateOrders.expectations) > 0 {
+		mmUpdateOrders.mock.t.Fatalf("Some expectations are already set for the OrderRepository.UpdateOrders method")
+	}
+
+	mmUpdateOrders.mock.funcUpdateOrders = f
+	mmUpdateOrders.mock.funcUpdateOrdersOrigin = minimock.CallerInfo(1)
+	return mmUpdateOrders.mock
+}
+
+// When sets expectation for the OrderRepository.UpdateOrders which will trigger the result defined by the following
+// Then helper
+func (mmUpdateOrders *mOrderRepositoryMockUpdateOrders) When(ctx context.Context, orders []*domain.Order) *OrderRepositoryMockUpdateOrdersExpectation {
+	if mmUpdateOrders.mock.funcUpdateOrders != nil {
+		mmUpdateOrders.mock.t.Fatalf("OrderRepositoryMock.UpdateOrders mock is already set by Set")
+	}
+
+	expectation := &OrderRepositoryMockUpdateOrdersExpectation{
+		mock:               mmUpdateOrders.mock,
+		params:             &OrderRepositoryMockUpdateOrdersParams{ctx, orders},
+		expectationOrigins: OrderRepositoryMockUpdateOrdersExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmUpdateOrders.expectations = append(mmUpdateOrders.expectations, expectation)
+	return expectation
+}
+
+// Then sets up OrderRepository.UpdateOrders return parameters for the expectation previously defined by the When method
+func (e *OrderRepositoryMockUpdateOrdersExpectation) Then(err error) *OrderRepositoryMock {
+	e.results = &OrderRepositoryMockUpdateOrdersResults{err}
+	return e.mock
+}
+
+// Times sets number of times OrderRepository.UpdateOrders should be invoked
+func (mmUpdateOrders *mOrderRepositoryMockUpdateOrders) Times(n uint64) *mOrderRepositoryMockUpdateOrders {
+	if n == 0 {
+		mmUpdateOrders.mock.t.Fatalf("Times of OrderRepositoryMock.UpdateOrders mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmUpdateOrders.expectedInvocations, n)
+	mmUpdateOrders.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmUpdateOrders
+}
+
+func (mmUpdateOrders *mOrderRepositoryMockUpdateOrders) invocationsDone() bool {
+	if len(mmUpdateOrders.expectations) == 0 && mmUpdateOrders.defaultExpectation == nil && mmUpdateOrders.mock.funcUpdateOrders == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmUpdateOrders.mock.afterUpdateOrdersCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmUpdateOrders.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// UpdateOrders implements mm_interfaces.OrderRepository
+func (mmUpdateOrders *OrderRepositoryMock) UpdateOrders(ctx context.Context, orders []*domain.Order) (err error) {
+	mm_atomic.AddUint64(&mmUpdateOrders.beforeUpdateOrdersCounter, 1)
+	defer mm_atomic.AddUint64(&mmUpdateOrders.afterUpdateOrdersCounter, 1)
+
+	mmUpdateOrders.t.Helper()
+
+	if mmUpdateOrders.inspectFuncUpdateOrders != nil {
+		mmUpdateOrders.inspectFuncUpdateOrders(ctx, orders)
+	}
+
+	mm_params := OrderRepositoryMockUpdateOrdersParams{ctx, orders}
+
+	// Record call args
+	mmUpdateOrders.UpdateOrdersMock.mutex.Lock()
+	mmUpdateOrders.UpdateOrdersMock.callArgs = append(mmUpdateOrders.UpdateOrdersMock.callArgs, &mm_params)
+	mmUpdateOrders.UpdateOrdersMock.mutex.Unlock()
+
+	for _, e := range mmUpdateOrders.UpdateOrdersMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmUpdateOrders.UpdateOrdersMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmUpdateOrders.UpdateOrdersMock.defaultExpectation.Counter, 1)
+		mm_want := mmUpdateOrders.UpdateOrdersMock.defaultExpectation.params
+		mm_want_ptrs := mmUpdateOrders.UpdateOrdersMock.defaultExpectation.paramPtrs
+
+		mm_got := OrderRepositoryMockUpdateOrdersParams{ctx, orders}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmUpdateOrders.t.Errorf("OrderRepositoryMock.UpdateOrders got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmUpdateOrders.UpdateOrdersMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.orders != nil && !minimock.Equal(*mm_want_ptrs.orders, mm_got.orders) {
+				mmUpdateOrders.t.Errorf("OrderRepositoryMock.UpdateOrders got unexpected parameter orders, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmUpdateOrders.UpdateOrdersMock.defaultExpectation.expectationOrigins.originOrders, *mm_want_ptrs.orders, mm_got.orders, minimock.Diff(*mm_want_ptrs.orders, mm_got.orders))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmUpdateOrders.t.Errorf("OrderRepositoryMock.UpdateOrders got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmUpdateOrders.UpdateOrdersMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmUpdateOrders.UpdateOrdersMock.defaultExpectation.results
+		if mm_results == nil {
+			mmUpdateOrders.t.Fatal("No results are set for the OrderRepositoryMock.UpdateOrders")
+		}
+		return (*mm_results).err
+	}
+	if mmUpdateOrders.funcUpdateOrders != nil {
+		return mmUpdateOrders.funcUpdateOrders(ctx, orders)
+	}
+	mmUpdateOrders.t.Fatalf("Unexpected call to OrderRepositoryMock.UpdateOrders. %v %v", ctx, orders)
+	return
+}
+
+// UpdateOrdersAfterCounter returns a count of finished OrderRepositoryMock.UpdateOrders invocations
+func (mmUpdateOrders *OrderRepositoryMock) UpdateOrdersAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmUpdateOrders.afterUpdateOrdersCounter)
+}
+
+// UpdateOrdersBeforeCounter returns a count of OrderRepositoryMock.UpdateOrders invocations
+func (mmUpdateOrders *OrderRepositoryMock) UpdateOrdersBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmUpdateOrders.beforeUpdateOrdersCounter)
+}
+
+// Calls returns a list of arguments used in each call to OrderRepositoryMock.UpdateOrders.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmUpdateOrders *mOrderRepositoryMockUpdateOrders) Calls() []*OrderRepositoryMockUpdateOrdersParams {
+	mmUpdateOrders.mutex.RLock()
+
+	argCopy := make([]*OrderRepositoryMockUpdateOrdersParams, len(mmUpdateOrders.callArgs))
+	copy(argCopy, mmUpdateOrders.callArgs)
+
+	mmUpdateOrders.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockUpdateOrdersDone returns true if the count of the UpdateOrders invocations corresponds
+// the number of defined expectations
+func (m *OrderRepositoryMock) MinimockUpdateOrdersDone() bool {
+	if m.UpdateOrdersMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.UpdateOrdersMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.UpdateOrdersMock.invocationsDone()
+}
+
+// MinimockUpdateOrdersInspect logs each unmet expectation
+func (m *OrderRepositoryMock) MinimockUpdateOrdersInspect() {
+	for _, e := range m.UpdateOrdersMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to OrderRepositoryMock.UpdateOrders at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterUpdateOrdersCounter := mm_atomic.LoadUint64(&m.afterUpdateOrdersCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.UpdateOrdersMock.defaultExpectation != nil && afterUpdateOrdersCounter < 1 {
+		if m.UpdateOrdersMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to OrderRepositoryMock.UpdateOrders at\n%s", m.UpdateOrdersMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to OrderRepositoryMock.UpdateOrders at\n%s with params: %#v", m.UpdateOrdersMock.defaultExpectation.expectationOrigins.origin, *m.UpdateOrdersMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcUpdateOrders != nil && afterUpdateOrdersCounter < 1 {
+		m.t.Errorf("Expected call to OrderRepositoryMock.UpdateOrders at\n%s", m.funcUpdateOrdersOrigin)
+	}
+
+	if !m.UpdateOrdersMock.invocationsDone() && afterUpdateOrdersCounter > 0 {
+		m.t.Errorf("Expected %d calls to OrderRepositoryMock.UpdateOrders at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.UpdateOrdersMock.expectedInvocations), m.UpdateOrdersMock.expectedInvocationsOrigin, afterUpdateOrdersCounter)
+	}
+}
+
 // MinimockFinish checks that all mocked methods have been called the expected number of times
 func (m *OrderRepositoryMock) MinimockFinish() {
 	m.finishOnce.Do(func() {
 		if !m.minimockDone() {
 			m.MinimockAddOrderInspect()
 
+			m.MinimockAddOrdersInspect()
+
 			m.MinimockDeleteOrderInspect()
 
 			m.MinimockGetOrderInspect()
 
+			m.MinimockGetOrdersByIDsInspect()
+
 			m.MinimockListOrdersInspect()
 
+			m.MinimockListOrdersByRecipientInspect()
+
 			m.MinimockUpdateOrderInspect()
+
+			m.MinimockUpdateOrdersInspect()
 		}
 	})
 }
@@ -1799,8 +3403,12 @@ func (m *OrderRepositoryMock) minimockDone() bool {
 	done := true
 	return done &&
 		m.MinimockAddOrderDone() &&
+		m.MinimockAddOrdersDone() &&
 		m.MinimockDeleteOrderDone() &&
 		m.MinimockGetOrderDone() &&
+		m.MinimockGetOrdersByIDsDone() &&
 		m.MinimockListOrdersDone() &&
-		m.MinimockUpdateOrderDone()
+		m.MinimockListOrdersByRecipientDone() &&
+		m.MinimockUpdateOrderDone() &&
+		m.MinimockUpdateOrdersDone()
 }
@@ -4,15 +4,15 @@ package mocks
 
 import (
 	"context"
-	"homework-1/internal/domain"
 	"sync"
 	mm_atomic "sync/atomic"
 	mm_time "time"
 
 	"github.com/gojuno/minimock/v3"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
 )
 
-// ReturnRepositoryMock implements mm_repository.ReturnRepository
+// ReturnRepositoryMock implements mm_interfaces.ReturnRepository
 type ReturnRepositoryMock struct {
 	t          minimock.Tester
 	finishOnce sync.Once
@@ -24,15 +24,15 @@ type ReturnRepositoryMock struct {
 	beforeAddReturnCounter uint64
 	AddReturnMock          mReturnRepositoryMockAddReturn
 
-	funcListReturns          func(ctx context.Context) (rpa1 []*domain.Return, err error)
+	funcListReturns          func(ctx context.Context, after *domain.ReturnCursor, limit int) (rpa1 []*domain.Return, err error)
 	funcListReturnsOrigin    string
-	inspectFuncListReturns   func(ctx context.Context)
+	inspectFuncListReturns   func(ctx context.Context, after *domain.ReturnCursor, limit int)
 	afterListReturnsCounter  uint64
 	beforeListReturnsCounter uint64
 	ListReturnsMock          mReturnRepositoryMockListReturns
 }
 
-// NewReturnRepositoryMock returns a mock for mm_repository.ReturnRepository
+// NewReturnRepositoryMock returns a mock for mm_interfaces.ReturnRepository
 func NewReturnRepositoryMock(t minimock.Tester) *ReturnRepositoryMock {
 	m := &ReturnRepositoryMock{t: t}
 
@@ -263,7 +263,7 @@ func (mmAddReturn *mReturnRepositoryMockAddReturn) invocationsDone() bool {
 	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
 }
 
-// AddReturn implements mm_repository.ReturnRepository
+// AddReturn implements mm_interfaces.ReturnRepository
 func (mmAddReturn *ReturnRepositoryMock) AddReturn(ctx context.Context, ret *domain.Return) (err error) {
 	mm_atomic.AddUint64(&mmAddReturn.beforeAddReturnCounter, 1)
 	defer mm_atomic.AddUint64(&mmAddReturn.afterAddReturnCounter, 1)
@@ -419,12 +419,16 @@ type ReturnRepositoryMockListReturnsExpectation struct {
 
 // ReturnRepositoryMockListReturnsParams contains parameters of the ReturnRepository.ListReturns
 type ReturnRepositoryMockListReturnsParams struct {
-	ctx context.Context
+	ctx   context.Context
+	after *domain.ReturnCursor
+	limit int
 }
 
 // ReturnRepositoryMockListReturnsParamPtrs contains pointers to parameters of the ReturnRepository.ListReturns
 type ReturnRepositoryMockListReturnsParamPtrs struct {
-	ctx *context.Context
+	ctx   *context.Context
+	after **domain.ReturnCursor
+	limit *int
 }
 
 // ReturnRepositoryMockListReturnsResults contains results of the ReturnRepository.ListReturns
@@ -435,8 +439,10 @@ type ReturnRepositoryMockListReturnsResults struct {
 
 // ReturnRepositoryMockListReturnsOrigins contains origins of expectations of the ReturnRepository.ListReturns
 type ReturnRepositoryMockListReturnsExpectationOrigins struct {
-	origin    string
-	originCtx string
+	origin      string
+	originCtx   string
+	originAfter string
+	originLimit string
 }
 
 // Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
@@ -450,7 +456,7 @@ func (mmListReturns *mReturnRepositoryMockListReturns) Optional() *mReturnReposi
 }
 
 // Expect sets up expected params for ReturnRepository.ListReturns
-func (mmListReturns *mReturnRepositoryMockListReturns) Expect(ctx context.Context) *mReturnRepositoryMockListReturns {
+func (mmListReturns *mReturnRepositoryMockListReturns) Expect(ctx context.Context, after *domain.ReturnCursor, limit int) *mReturnRepositoryMockListReturns {
 	if mmListReturns.mock.funcListReturns != nil {
 		mmListReturns.mock.t.Fatalf("ReturnRepositoryMock.ListReturns mock is already set by Set")
 	}
@@ -463,7 +469,7 @@ func (mmListReturns *mReturnRepositoryMockListReturns) Expect(ctx context.Contex
 		mmListReturns.mock.t.Fatalf("ReturnRepositoryMock.ListReturns mock is already set by ExpectParams functions")
 	}
 
-	mmListReturns.defaultExpectation.params = &ReturnRepositoryMockListReturnsParams{ctx}
+	mmListReturns.defaultExpectation.params = &ReturnRepositoryMockListReturnsParams{ctx, after, limit}
 	mmListReturns.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
 	for _, e := range mmListReturns.expectations {
 		if minimock.Equal(e.params, mmListReturns.defaultExpectation.params) {
@@ -497,8 +503,54 @@ func (mmListReturns *mReturnRepositoryMockListReturns) ExpectCtxParam1(ctx conte
 	return mmListReturns
 }
 
+// ExpectAfterParam2 sets up expected param after for ReturnRepository.ListReturns
+func (mmListReturns *mReturnRepositoryMockListReturns) ExpectAfterParam2(after *domain.ReturnCursor) *mReturnRepositoryMockListReturns {
+	if mmListReturns.mock.funcListReturns != nil {
+		mmListReturns.mock.t.Fatalf("ReturnRepositoryMock.ListReturns mock is already set by Set")
+	}
+
+	if mmListReturns.defaultExpectation == nil {
+		mmListReturns.defaultExpectation = &ReturnRepositoryMockListReturnsExpectation{}
+	}
+
+	if mmListReturns.defaultExpectation.params != nil {
+		mmListReturns.mock.t.Fatalf("ReturnRepositoryMock.ListReturns mock is already set by Expect")
+	}
+
+	if mmListReturns.defaultExpectation.paramPtrs == nil {
+		mmListReturns.defaultExpectation.paramPtrs = &ReturnRepositoryMockListReturnsParamPtrs{}
+	}
+	mmListReturns.defaultExpectation.paramPtrs.after = &after
+	mmListReturns.defaultExpectation.expectationOrigins.originAfter = minimock.CallerInfo(1)
+
+	return mmListReturns
+}
+
+// ExpectLimitParam3 sets up expected param limit for ReturnRepository.ListReturns
+func (mmListReturns *mReturnRepositoryMockListReturns) ExpectLimitParam3(limit int) *mReturnRepositoryMockListReturns {
+	if mmListReturns.mock.funcListReturns != nil {
+		mmListReturns.mock.t.Fatalf("ReturnRepositoryMock.ListReturns mock is already set by Set")
+	}
+
+	if mmListReturns.defaultExpectation == nil {
+		mmListReturns.defaultExpectation = &ReturnRepositoryMockListReturnsExpectation{}
+	}
+
+	if mmListReturns.defaultExpectation.params != nil {
+		mmListReturns.mock.t.Fatalf("ReturnRepositoryMock.ListReturns mock is already set by Expect")
+	}
+
+	if mmListReturns.defaultExpectation.paramPtrs == nil {
+		mmListReturns.defaultExpectation.paramPtrs = &ReturnRepositoryMockListReturnsParamPtrs{}
+	}
+	mmListReturns.defaultExpectation.paramPtrs.limit = &limit
+	mmListReturns.defaultExpectation.expectationOrigins.originLimit = minimock.CallerInfo(1)
+
+	return mmListReturns
+}
+
 // Inspect accepts an inspector function that has same arguments as the ReturnRepository.ListReturns
-func (mmListReturns *mReturnRepositoryMockListReturns) Inspect(f func(ctx context.Context)) *mReturnRepositoryMockListReturns {
+func (mmListReturns *mReturnRepositoryMockListReturns) Inspect(f func(ctx context.Context, after *domain.ReturnCursor, limit int)) *mReturnRepositoryMockListReturns {
 	if mmListReturns.mock.inspectFuncListReturns != nil {
 		mmListReturns.mock.t.Fatalf("Inspect function is already set for ReturnRepositoryMock.ListReturns")
 	}
@@ -523,7 +575,7 @@ func (mmListReturns *mReturnRepositoryMockListReturns) Return(rpa1 []*domain.Ret
 }
 
 // Set uses given function f to mock the ReturnRepository.ListReturns method
-func (mmListReturns *mReturnRepositoryMockListReturns) Set(f func(ctx context.Context) (rpa1 []*domain.Return, err error)) *ReturnRepositoryMock {
+func (mmListReturns *mReturnRepositoryMockListReturns) Set(f func(ctx context.Context, after *domain.ReturnCursor, limit int) (rpa1 []*domain.Return, err error)) *ReturnRepositoryMock {
 	if mmListReturns.defaultExpectation != nil {
 		mmListReturns.mock.t.Fatalf("Default expectation is already set for the ReturnRepository.ListReturns method")
 	}
@@ -539,14 +591,14 @@ func (mmListReturns *mReturnRepositoryMockListReturns) Set(f func(ctx context.Co
 
 // When sets expectation for the ReturnRepository.ListReturns which will trigger the result defined by the following
 // Then helper
-func (mmListReturns *mReturnRepositoryMockListReturns) When(ctx context.Context) *ReturnRepositoryMockListReturnsExpectation {
+func (mmListReturns *mReturnRepositoryMockListReturns) When(ctx context.Context, after *domain.ReturnCursor, limit int) *ReturnRepositoryMockListReturnsExpectation {
 	if mmListReturns.mock.funcListReturns != nil {
 		mmListReturns.mock.t.Fatalf("ReturnRepositoryMock.ListReturns mock is already set by Set")
 	}
 
 	expectation := &ReturnRepositoryMockListReturnsExpectation{
 		mock:               mmListReturns.mock,
-		params:             &ReturnRepositoryMockListReturnsParams{ctx},
+		params:             &ReturnRepositoryMockListReturnsParams{ctx, after, limit},
 		expectationOrigins: ReturnRepositoryMockListReturnsExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
 	mmListReturns.expectations = append(mmListReturns.expectations, expectation)
@@ -580,18 +632,18 @@ func (mmListReturns *mReturnRepositoryMockListReturns) invocationsDone() bool {
 	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
 }
 
-// ListReturns implements mm_repository.ReturnRepository
-func (mmListReturns *ReturnRepositoryMock) ListReturns(ctx context.Context) (rpa1 []*domain.Return, err error) {
+// ListReturns implements mm_interfaces.ReturnRepository
+func (mmListReturns *ReturnRepositoryMock) ListReturns(ctx context.Context, after *domain.ReturnCursor, limit int) (rpa1 []*domain.Return, err error) {
 	mm_atomic.AddUint64(&mmListReturns.beforeListReturnsCounter, 1)
 	defer mm_atomic.AddUint64(&mmListReturns.afterListReturnsCounter, 1)
 
 	mmListReturns.t.Helper()
 
 	if mmListReturns.inspectFuncListReturns != nil {
-		mmListReturns.inspectFuncListReturns(ctx)
+		mmListReturns.inspectFuncListReturns(ctx, after, limit)
 	}
 
-	mm_params := ReturnRepositoryMockListReturnsParams{ctx}
+	mm_params := ReturnRepositoryMockListReturnsParams{ctx, after, limit}
 
 	// Record call args
 	mmListReturns.ListReturnsMock.mutex.Lock()
@@ -610,7 +662,7 @@ func (mmListReturns *ReturnRepositoryMock) ListReturns(ctx context.Context) (rpa
 		mm_want := mmListReturns.ListReturnsMock.defaultExpectation.params
 		mm_want_ptrs := mmListReturns.ListReturnsMock.defaultExpectation.paramPtrs
 
-		mm_got := ReturnRepositoryMockListReturnsParams{ctx}
+		mm_got := ReturnRepositoryMockListReturnsParams{ctx, after, limit}
 
 		if mm_want_ptrs != nil {
 
@@ -619,6 +671,16 @@ func (mmListReturns *ReturnRepositoryMock) ListReturns(ctx context.Context) (rpa
 					mmListReturns.ListReturnsMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
 			}
 
+			if mm_want_ptrs.after != nil && !minimock.Equal(*mm_want_ptrs.after, mm_got.after) {
+				mmListReturns.t.Errorf("ReturnRepositoryMock.ListReturns got unexpected parameter after, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListReturns.ListReturnsMock.defaultExpectation.expectationOrigins.originAfter, *mm_want_ptrs.after, mm_got.after, minimock.Diff(*mm_want_ptrs.after, mm_got.after))
+			}
+
+			if mm_want_ptrs.limit != nil && !minimock.Equal(*mm_want_ptrs.limit, mm_got.limit) {
+				mmListReturns.t.Errorf("ReturnRepositoryMock.ListReturns got unexpected parameter limit, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListReturns.ListReturnsMock.defaultExpectation.expectationOrigins.originLimit, *mm_want_ptrs.limit, mm_got.limit, minimock.Diff(*mm_want_ptrs.limit, mm_got.limit))
+			}
+
 		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
 			mmListReturns.t.Errorf("ReturnRepositoryMock.ListReturns got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 				mmListReturns.ListReturnsMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
@@ -631,9 +693,9 @@ func (mmListReturns *ReturnRepositoryMock) ListReturns(ctx context.Context) (rpa
 		return (*mm_results).rpa1, (*mm_results).err
 	}
 	if mmListReturns.funcListReturns != nil {
-		return mmListReturns.funcListReturns(ctx)
+		return mmListReturns.funcListReturns(ctx, after, limit)
 	}
-	mmListReturns.t.Fatalf("Unexpected call to ReturnRepositoryMock.ListReturns. %v", ctx)
+	mmListReturns.t.Fatalf("Unexpected call to ReturnRepositoryMock.ListReturns. %v %v %v", ctx, after, limit)
 	return
 }
 
@@ -28,59 +28,132 @@ func NewOrderUseCase(orderRepo interfaces.OrderRepository, returnRepo interfaces
 }
 
 func (uc *OrderUseCase) AddOrder(ctx context.Context, req *dto.AddOrderDTO) error {
+	order, err := newOrder(req)
+	if err != nil {
+		return err
+	}
+
+	err = uc.orderRepo.AddOrder(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	uc.metrics.IncOrdersServed()
+
+	return nil
+}
+
+// AddOrders принимает пачку заказов одной операцией: если хотя бы один заказ некорректен
+// или уже есть, не принимается ни один
+func (uc *OrderUseCase) AddOrders(ctx context.Context, reqs []*dto.AddOrderDTO) error {
+	orders := make([]*domain.Order, 0, len(reqs))
+	for _, req := range reqs {
+		order, err := newOrder(req)
+		if err != nil {
+			return fmt.Errorf("order %s: %w", req.OrderID, err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err := uc.orderRepo.AddOrders(ctx, orders); err != nil {
+		return err
+	}
+
+	for range orders {
+		uc.metrics.IncOrdersServed()
+	}
+	return nil
+}
+
+func newOrder(req *dto.AddOrderDTO) (*domain.Order, error) {
 	if err := req.Validate(); err != nil {
-		return fmt.Errorf("validate add order request: %w", err)
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
 	}
 
 	expiryDate, err := time.Parse("2006-01-02", req.ExpiryDate)
 	if err != nil {
-		return domain.ErrInvalidInput
+		return nil, domain.ErrInvalidInput
 	}
 
-	order := &domain.Order{
+	return &domain.Order{
 		OrderID:       req.OrderID,
 		RecipientID:   req.RecipientID,
 		ExpiryDate:    expiryDate,
-		Status:        "new",
+		Status:        domain.OrderStatusNew,
 		Weight:        req.Weight,
 		Cost:          calculateCost(req.Weight, req.PackagingType),
 		PackagingType: req.PackagingType,
-	}
-
-	err = uc.orderRepo.AddOrder(ctx, order)
-	if err != nil {
-		return err
-	}
-
-	uc.metrics.IncOrdersServed()
-
-	return nil
+		AcceptedAt:    time.Now(),
+		Version:       1,
+	}, nil
 }
 
-func (uc *OrderUseCase) RemoveOrder(ctx context.Context, orderID string) error {
-	return uc.orderRepo.DeleteOrder(ctx, orderID)
+// RemoveOrder удаляет заказ с причиной reason. Если expectedVersion не равна 0, заказ удаляется, только пока
+// его версия совпадает с ней, иначе возвращается domain.ErrOrderVersionConflict
+func (uc *OrderUseCase) RemoveOrder(ctx context.Context, orderID string, expectedVersion int64, reason string) error {
+	if expectedVersion == 0 {
+		return uc.orderRepo.DeleteOrder(ctx, orderID, reason)
+	}
+	return uc.txManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		order, err := uc.orderRepo.GetOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if order.Version != expectedVersion {
+			return domain.ErrOrderVersionConflict
+		}
+		return uc.orderRepo.DeleteOrder(ctx, orderID, reason)
+	}, nil)
 }
 
+// DeliverOrders выдаёт заказы получателю одной операцией: заказы читаются и обновляются пачкой,
+// а если хотя бы один не найден или принадлежит другому получателю, не выдаётся ни один
 func (uc *OrderUseCase) DeliverOrders(ctx context.Context, recipientID string, orderIDs []string) error {
-	for _, orderID := range orderIDs {
-		order, err := uc.orderRepo.GetOrder(ctx, orderID)
+	orderIDs = uniqueIDs(orderIDs)
+	return uc.txManager.RunInTransaction(ctx, func(ctx context.Context) error {
+		orders, err := uc.orderRepo.GetOrdersByIDs(ctx, orderIDs)
 		if err != nil {
 			return err
 		}
-		if order.RecipientID != recipientID {
-			return domain.ErrPermissionDenied
+		byID := make(map[string]*domain.Order, len(orders))
+		for _, order := range orders {
+			byID[order.OrderID] = order
 		}
-		order.Status = "delivered"
-		order.DeliveryDate = sql.NullTime{Time: time.Now(), Valid: true}
-		if err := uc.orderRepo.UpdateOrder(ctx, order); err != nil {
-			return err
+
+		deliveryDate := sql.NullTime{Time: time.Now(), Valid: true}
+		for _, orderID := range orderIDs {
+			order, ok := byID[orderID]
+			if !ok {
+				return domain.ErrOrderNotFound
+			}
+			if order.RecipientID != recipientID {
+				return domain.ErrPermissionDenied
+			}
+			order.Status = domain.OrderStatusDelivered
+			order.DeliveryDate = deliveryDate
+		}
+		return uc.orderRepo.UpdateOrders(ctx, orders)
+	}, nil)
+}
+
+// uniqueIDs убирает повторы, сохраняя порядок
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
 		}
 	}
-	return nil
+	return unique
 }
 
-func (uc *OrderUseCase) AcceptReturn(ctx context.Context, recipientID, orderID string) error {
-	return uc.txManager.RunInTransaction(ctx, func(ctx context.Context) error {
+// AcceptReturn принимает возврат заказа и возвращает новую версию заказа. Если expectedVersion не равна 0,
+// возврат принимается, только пока версия заказа совпадает с ней, иначе возвращается domain.ErrOrderVersionConflict
+func (uc *OrderUseCase) AcceptReturn(ctx context.Context, recipientID, orderID string, expectedVersion int64) (int64, error) {
+	var version int64
+	err := uc.txManager.RunInTransaction(ctx, func(ctx context.Context) error {
 		order, err := uc.orderRepo.GetOrder(ctx, orderID)
 		if err != nil {
 			return err
@@ -88,11 +161,15 @@ func (uc *OrderUseCase) AcceptReturn(ctx context.Context, recipientID, orderID s
 		if order.RecipientID != recipientID {
 			return domain.ErrPermissionDenied
 		}
-		order.Status = "returned"
+		if expectedVersion != 0 && order.Version != expectedVersion {
+			return domain.ErrOrderVersionConflict
+		}
+		order.Status = domain.OrderStatusReturned
 		order.ReturnDate = sql.NullTime{Time: time.Now(), Valid: true}
 		if err := uc.orderRepo.UpdateOrder(ctx, order); err != nil {
 			return err
 		}
+		version = order.Version
 		ret := &domain.Return{
 			OrderID:     orderID,
 			RecipientID: recipientID,
@@ -100,42 +177,120 @@ func (uc *OrderUseCase) AcceptReturn(ctx context.Context, recipientID, orderID s
 		}
 		return uc.returnRepo.AddReturn(ctx, ret)
 	}, nil)
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
 }
 
-func (uc *OrderUseCase) GetOrders(ctx context.Context, recipientID string, lastN int) ([]*dto.OrderDTO, error) {
-	orders, err := uc.orderRepo.ListOrdersByRecipient(ctx, recipientID, lastN)
+func (uc *OrderUseCase) GetOrders(ctx context.Context, recipientID string, pageSize int, pageToken string) ([]*dto.OrderDTO, string, error) {
+	query, err := filterFingerprint(domain.OrderFilter{RecipientID: recipientID})
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
+	return uc.pageOrders(pageSize, pageToken, query, func(after *domain.OrderCursor, limit int) ([]*domain.Order, error) {
+		return uc.orderRepo.ListOrdersByRecipient(ctx, recipientID, after, limit)
+	})
+}
 
-	var orderDTOs []*dto.OrderDTO
-	for _, order := range orders {
-		dtoOrder := &dto.OrderDTO{
-			OrderID:       order.OrderID,
-			RecipientID:   order.RecipientID,
-			ExpiryDate:    order.ExpiryDate.Format("2006-01-02"),
-			Status:        order.Status,
-			Weight:        order.Weight,
-			Cost:          order.Cost,
-			PackagingType: order.PackagingType,
+// ListOrders возвращает страницу заказов под фильтром. Токен страницы действует только с тем фильтром,
+// с которым он выдан
+func (uc *OrderUseCase) ListOrders(ctx context.Context, filter domain.OrderFilter, pageSize int, pageToken string) ([]*dto.OrderDTO, string, error) {
+	if !filter.ExpiryFrom.IsZero() && !filter.ExpiryTo.IsZero() && filter.ExpiryFrom.After(filter.ExpiryTo) {
+		return nil, "", fmt.Errorf("%w: expiry_from is after expiry_to", domain.ErrInvalidInput)
+	}
+	query, err := filterFingerprint(filter)
+	if err != nil {
+		return nil, "", err
+	}
+	return uc.pageOrders(pageSize, pageToken, query, func(after *domain.OrderCursor, limit int) ([]*domain.Order, error) {
+		return uc.orderRepo.ListOrders(ctx, filter, after, limit)
+	})
+}
+
+// pageOrders запрашивает на один заказ больше размера страницы, чтобы понять, есть ли следующая.
+// query — отпечаток выборки: токен другой выборки отклоняется, иначе курсор продолжил бы не ту выдачу
+func (uc *OrderUseCase) pageOrders(pageSize int, pageToken, query string, fetch func(after *domain.OrderCursor, limit int) ([]*domain.Order, error)) ([]*dto.OrderDTO, string, error) {
+	pageSize = normalizePageSize(pageSize)
+
+	var after *domain.OrderCursor
+	if pageToken != "" {
+		var token orderPageToken
+		if err := decodePageToken(pageToken, &token); err != nil {
+			return nil, "", err
 		}
-		if order.DeliveryDate.Valid {
-			dtoOrder.DeliveryDate = order.DeliveryDate.Time.Format("2006-01-02")
+		if token.Query != query {
+			return nil, "", fmt.Errorf("%w: page token was issued for a different filter", domain.ErrInvalidInput)
 		}
-		if order.ReturnDate.Valid {
-			dtoOrder.ReturnDate = order.ReturnDate.Time.Format("2006-01-02")
+		after = &token.OrderCursor
+	}
+
+	orders, err := fetch(after, pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextPageToken string
+	if len(orders) > pageSize {
+		orders = orders[:pageSize]
+		last := orders[len(orders)-1]
+		nextPageToken, err = encodePageToken(orderPageToken{
+			OrderCursor: domain.OrderCursor{AcceptedAt: last.AcceptedAt, OrderID: last.OrderID},
+			Query:       query,
+		})
+		if err != nil {
+			return nil, "", err
 		}
-		orderDTOs = append(orderDTOs, dtoOrder)
 	}
-	return orderDTOs, nil
+
+	var orderDTOs []*dto.OrderDTO
+	for _, order := range orders {
+		orderDTOs = append(orderDTOs, orderToDTO(order))
+	}
+	return orderDTOs, nextPageToken, nil
 }
 
-func (uc *OrderUseCase) GetReturns(ctx context.Context, page int) ([]*dto.ReturnDTO, error) {
-	pageSize := 10
-	offset := (page - 1) * pageSize
-	returns, err := uc.returnRepo.ListReturns(ctx, offset, pageSize)
+func orderToDTO(order *domain.Order) *dto.OrderDTO {
+	return &dto.OrderDTO{
+		OrderID:       order.OrderID,
+		RecipientID:   order.RecipientID,
+		ExpiryDate:    order.ExpiryDate,
+		Status:        order.Status,
+		DeliveryDate:  order.DeliveryDate,
+		ReturnDate:    order.ReturnDate,
+		Weight:        order.Weight,
+		Cost:          order.Cost,
+		PackagingType: order.PackagingType,
+		AcceptedAt:    order.AcceptedAt,
+		Version:       order.Version,
+		UpdatedAt:     order.UpdatedAt,
+	}
+}
+
+func (uc *OrderUseCase) GetReturns(ctx context.Context, pageSize int, pageToken string) ([]*dto.ReturnDTO, string, error) {
+	pageSize = normalizePageSize(pageSize)
+
+	var after *domain.ReturnCursor
+	if pageToken != "" {
+		after = &domain.ReturnCursor{}
+		if err := decodePageToken(pageToken, after); err != nil {
+			return nil, "", err
+		}
+	}
+
+	returns, err := uc.returnRepo.ListReturns(ctx, after, pageSize+1)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	var nextPageToken string
+	if len(returns) > pageSize {
+		returns = returns[:pageSize]
+		last := returns[len(returns)-1]
+		nextPageToken, err = encodePageToken(domain.ReturnCursor{ReturnDate: last.ReturnDate, ID: last.ID})
+		if err != nil {
+			return nil, "", err
+		}
 	}
 
 	var returnDTOs []*dto.ReturnDTO
@@ -146,17 +301,17 @@ func (uc *OrderUseCase) GetReturns(ctx context.Context, page int) ([]*dto.Return
 			ReturnDate:  ret.ReturnDate.Format("2006-01-02"),
 		})
 	}
-	return returnDTOs, nil
+	return returnDTOs, nextPageToken, nil
 }
 
 func calculateCost(weight float32, packagingType string) float32 {
 	baseCost := weight * 10
 	switch packagingType {
-	case "bag":
+	case domain.PackagingBag:
 		return baseCost + 5
-	case "box":
+	case domain.PackagingBox:
 		return baseCost + 10
-	case "film":
+	case domain.PackagingFilm:
 		return baseCost + 2
 	default:
 		return baseCost
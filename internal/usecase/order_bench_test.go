@@ -0,0 +1,134 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/dto"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/interfaces"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/memory"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/usecase"
+)
+
+// benchBatchSize — размер пачки, на которой сравниваются поштучные и пакетные операции
+const benchBatchSize = 100
+
+// countingOrderRepository считает обращения к хранилищу. В postgres каждое из них — один запрос,
+// поэтому счётчик показывает, сколько запросов делает операция
+type countingOrderRepository struct {
+	interfaces.OrderRepository
+	queries int
+}
+
+func (r *countingOrderRepository) AddOrder(ctx context.Context, order *domain.Order) error {
+	r.queries++
+	return r.OrderRepository.AddOrder(ctx, order)
+}
+
+func (r *countingOrderRepository) AddOrders(ctx context.Context, orders []*domain.Order) error {
+	r.queries++
+	return r.OrderRepository.AddOrders(ctx, orders)
+}
+
+func (r *countingOrderRepository) GetOrder(ctx context.Context, orderID string) (*domain.Order, error) {
+	r.queries++
+	return r.OrderRepository.GetOrder(ctx, orderID)
+}
+
+func (r *countingOrderRepository) GetOrdersByIDs(ctx context.Context, orderIDs []string) ([]*domain.Order, error) {
+	r.queries++
+	return r.OrderRepository.GetOrdersByIDs(ctx, orderIDs)
+}
+
+func (r *countingOrderRepository) UpdateOrder(ctx context.Context, order *domain.Order) error {
+	r.queries++
+	return r.OrderRepository.UpdateOrder(ctx, order)
+}
+
+func (r *countingOrderRepository) UpdateOrders(ctx context.Context, orders []*domain.Order) error {
+	r.queries++
+	return r.OrderRepository.UpdateOrders(ctx, orders)
+}
+
+func newBenchUseCase() (*usecase.OrderUseCase, *countingOrderRepository) {
+	store := memory.NewStore()
+	repo := &countingOrderRepository{OrderRepository: memory.NewOrderRepository(store)}
+	return usecase.NewOrderUseCase(repo, memory.NewReturnRepository(store), memory.NewTxManager(store), &stubMetrics{}), repo
+}
+
+func benchAddRequests(prefix string) []*dto.AddOrderDTO {
+	reqs := make([]*dto.AddOrderDTO, benchBatchSize)
+	for i := range reqs {
+		reqs[i] = addOrderRequest(fmt.Sprintf("%s-%d", prefix, i), "recipient1")
+	}
+	return reqs
+}
+
+func BenchmarkOrderUseCase_AddOrders(b *testing.B) {
+	ctx := context.Background()
+
+	b.Run("per_order", func(b *testing.B) {
+		uc, repo := newBenchUseCase()
+		for i := 0; i < b.N; i++ {
+			for _, req := range benchAddRequests(fmt.Sprint(i)) {
+				if err := uc.AddOrder(ctx, req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(repo.queries)/float64(b.N), "queries/op")
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		uc, repo := newBenchUseCase()
+		for i := 0; i < b.N; i++ {
+			if err := uc.AddOrders(ctx, benchAddRequests(fmt.Sprint(i))); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(repo.queries)/float64(b.N), "queries/op")
+	})
+}
+
+func BenchmarkOrderUseCase_DeliverOrders(b *testing.B) {
+	ctx := context.Background()
+	uc, repo := newBenchUseCase()
+	reqs := benchAddRequests("order")
+	if err := uc.AddOrders(ctx, reqs); err != nil {
+		b.Fatal(err)
+	}
+	orderIDs := make([]string, len(reqs))
+	for i, req := range reqs {
+		orderIDs[i] = req.OrderID
+	}
+
+	// per_order повторяет прежнюю выдачу: чтение и обновление каждого заказа отдельно
+	b.Run("per_order", func(b *testing.B) {
+		repo.queries = 0
+		for i := 0; i < b.N; i++ {
+			for _, orderID := range orderIDs {
+				order, err := repo.GetOrder(ctx, orderID)
+				if err != nil {
+					b.Fatal(err)
+				}
+				order.Status = domain.OrderStatusDelivered
+				if err := repo.UpdateOrder(ctx, order); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(repo.queries)/float64(b.N), "queries/op")
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		repo.queries = 0
+		for i := 0; i < b.N; i++ {
+			if err := uc.DeliverOrders(ctx, "recipient1", orderIDs); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(repo.queries)/float64(b.N), "queries/op")
+	})
+}
@@ -0,0 +1,397 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/cache"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/dto"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/memory"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/repository/postgres"
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/usecase"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubMetrics struct {
+	ordersServed int
+}
+
+func (m *stubMetrics) IncOrdersServed()                         { m.ordersServed++ }
+func (m *stubMetrics) IncInvalidationsPublished(err error)      {}
+func (m *stubMetrics) IncInvalidationsApplied(keys int)         {}
+func (m *stubMetrics) ObserveInvalidationLag(lag time.Duration) {}
+func (m *stubMetrics) IncTxRetries(sqlState string)             {}
+func (m *stubMetrics) IncTxRollbacks(reason string)             {}
+
+// failingReturnRepository отказывает в записи возврата, чтобы проверить откат транзакции
+type failingReturnRepository struct {
+	*memory.ReturnRepository
+}
+
+var errReturnFailed = errors.New("return failed")
+
+func (r failingReturnRepository) AddReturn(ctx context.Context, ret *domain.Return) error {
+	return errReturnFailed
+}
+
+func addOrderRequest(orderID, recipientID string) *dto.AddOrderDTO {
+	return &dto.AddOrderDTO{
+		OrderID:       orderID,
+		RecipientID:   recipientID,
+		ExpiryDate:    time.Now().Add(24 * time.Hour).Format("2006-01-02"),
+		Weight:        5,
+		PackagingType: domain.PackagingBag,
+	}
+}
+
+func TestOrderUseCase_AddOrder(t *testing.T) {
+	store := memory.NewStore()
+	metrics := &stubMetrics{}
+	uc := usecase.NewOrderUseCase(memory.NewOrderRepository(store), memory.NewReturnRepository(store), memory.NewTxManager(store), metrics)
+	ctx := context.Background()
+
+	require.NoError(t, uc.AddOrder(ctx, addOrderRequest("order1", "recipient1")))
+	assert.ErrorIs(t, uc.AddOrder(ctx, addOrderRequest("order1", "recipient1")), domain.ErrOrderAlreadyExists)
+	assert.ErrorIs(t, uc.AddOrder(ctx, &dto.AddOrderDTO{OrderID: "order2"}), domain.ErrInvalidInput)
+	assert.Equal(t, 1, metrics.ordersServed)
+
+	orders, nextPageToken, err := uc.GetOrders(ctx, "recipient1", 10, "")
+	require.NoError(t, err)
+	assert.Empty(t, nextPageToken)
+	require.Len(t, orders, 1)
+	assert.Equal(t, domain.OrderStatusNew, orders[0].Status)
+	assert.Equal(t, float32(55), orders[0].Cost)
+}
+
+func TestOrderUseCase_DeliverOrders(t *testing.T) {
+	store := memory.NewStore()
+	orderRepo := memory.NewOrderRepository(store)
+	uc := usecase.NewOrderUseCase(orderRepo, memory.NewReturnRepository(store), memory.NewTxManager(store), &stubMetrics{})
+	ctx := context.Background()
+
+	require.NoError(t, uc.AddOrder(ctx, addOrderRequest("order1", "recipient1")))
+	require.NoError(t, uc.AddOrder(ctx, addOrderRequest("order2", "recipient2")))
+
+	assert.ErrorIs(t, uc.DeliverOrders(ctx, "recipient1", []string{"order2"}), domain.ErrPermissionDenied)
+	assert.ErrorIs(t, uc.DeliverOrders(ctx, "recipient1", []string{"missing"}), domain.ErrOrderNotFound)
+	require.NoError(t, uc.DeliverOrders(ctx, "recipient1", []string{"order1"}))
+
+	order, err := orderRepo.GetOrder(ctx, "order1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusDelivered, order.Status)
+	assert.True(t, order.DeliveryDate.Valid)
+}
+
+func TestOrderUseCase_DeliverOrdersIsAtomic(t *testing.T) {
+	store := memory.NewStore()
+	orderRepo := memory.NewOrderRepository(store)
+	uc := usecase.NewOrderUseCase(orderRepo, memory.NewReturnRepository(store), memory.NewTxManager(store), &stubMetrics{})
+	ctx := context.Background()
+
+	require.NoError(t, uc.AddOrders(ctx, []*dto.AddOrderDTO{
+		addOrderRequest("order1", "recipient1"),
+		addOrderRequest("order2", "recipient1"),
+		addOrderRequest("order3", "recipient2"),
+	}))
+
+	assert.ErrorIs(t, uc.DeliverOrders(ctx, "recipient1", []string{"order1", "order3"}), domain.ErrPermissionDenied)
+	assert.ErrorIs(t, uc.DeliverOrders(ctx, "recipient1", []string{"order1", "missing"}), domain.ErrOrderNotFound)
+	order, err := orderRepo.GetOrder(ctx, "order1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusNew, order.Status, "no order must be delivered when one of them fails")
+
+	require.NoError(t, uc.DeliverOrders(ctx, "recipient1", []string{"order1", "order2", "order1"}))
+	orders, err := orderRepo.GetOrdersByIDs(ctx, []string{"order1", "order2"})
+	require.NoError(t, err)
+	for _, order := range orders {
+		assert.Equal(t, domain.OrderStatusDelivered, order.Status)
+		assert.Equal(t, int64(2), order.Version)
+	}
+}
+
+// ordersDriver — драйвер database/sql, который на любую выборку отдаёт строки rows, а любую команду
+// отклоняет с errUpdateFailed. Так репозиторий postgres работает без базы, а транзакция DeliverOrders падает
+type ordersDriver struct {
+	mu   sync.Mutex
+	rows []*domain.Order
+}
+
+var errUpdateFailed = errors.New("update failed")
+
+func (d *ordersDriver) Open(name string) (driver.Conn, error) { return ordersConn{d}, nil }
+
+func (d *ordersDriver) setRows(rows ...*domain.Order) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rows = rows
+}
+
+type ordersConn struct{ d *ordersDriver }
+
+func (c ordersConn) Prepare(query string) (driver.Stmt, error)      { return ordersStmt(c), nil }
+func (c ordersConn) Close() error                                   { return nil }
+func (c ordersConn) Begin() (driver.Tx, error)                      { return ordersTx{}, nil }
+func (c ordersConn) CheckNamedValue(value *driver.NamedValue) error { return nil }
+
+type ordersStmt struct{ d *ordersDriver }
+
+func (s ordersStmt) Close() error  { return nil }
+func (s ordersStmt) NumInput() int { return -1 }
+func (s ordersStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errUpdateFailed
+}
+func (s ordersStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	return &orderRows{orders: slices.Clone(s.d.rows)}, nil
+}
+
+type ordersTx struct{}
+
+func (ordersTx) Commit() error   { return nil }
+func (ordersTx) Rollback() error { return nil }
+
+// orderRows отдаёт заказы в колонках запросов репозитория postgres
+type orderRows struct {
+	orders []*domain.Order
+}
+
+func (r *orderRows) Columns() []string {
+	return []string{"order_id", "recipient_id", "expiry_date", "status", "delivery_date", "return_date", "weight",
+		"cost", "packaging_type", "accepted_at", "version", "updated_at", "deleted_at", "removal_reason"}
+}
+
+func (r *orderRows) Close() error { return nil }
+
+func (r *orderRows) Next(dest []driver.Value) error {
+	if len(r.orders) == 0 {
+		return io.EOF
+	}
+	o := r.orders[0]
+	r.orders = r.orders[1:]
+	copy(dest, []driver.Value{o.OrderID, o.RecipientID, o.ExpiryDate, o.Status, nil, nil, float64(o.Weight),
+		float64(o.Cost), o.PackagingType, o.AcceptedAt, o.Version, o.UpdatedAt, nil, o.RemovalReason})
+	return nil
+}
+
+// nopInvalidator не публикует инвалидации: в тесте одна реплика
+type nopInvalidator struct{}
+
+func (nopInvalidator) Invalidate(ctx context.Context, keys ...string) {}
+
+// testOrders отдаёт строки заказов репозиторию postgres в тестах usecase
+var testOrders = &ordersDriver{}
+
+func init() {
+	sql.Register("usecase-orders", testOrders)
+}
+
+func TestOrderUseCase_DeliverOrdersFailureKeepsCachedOrders(t *testing.T) {
+	testOrders.setRows(&domain.Order{
+		OrderID:     "order1",
+		RecipientID: "recipient1",
+		ExpiryDate:  time.Now().Add(24 * time.Hour).UTC(),
+		Status:      domain.OrderStatusNew,
+		Weight:      5,
+		Cost:        55,
+		AcceptedAt:  time.Now().UTC(),
+		Version:     1,
+	})
+	db, err := sql.Open("usecase-orders", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	// Имя драйвера задаёт плейсхолдеры $n, как у postgres
+	primary := sqlx.NewDb(db, "postgres")
+
+	orderCache := cache.NewLRUCache[string, *domain.Order](10, time.Hour, time.Minute, cache.WithCloner((*domain.Order).Clone))
+	t.Cleanup(func() { _ = orderCache.Close() })
+	cluster := postgres.NewCluster(primary, nil, postgres.ClusterConfig{})
+	orderRepo := postgres.NewOrderRepository(cluster, orderCache, nopInvalidator{})
+	uc := usecase.NewOrderUseCase(orderRepo, postgres.NewReturnRepository(cluster), postgres.NewTxManager(primary, postgres.TxConfig{}, &stubMetrics{}), &stubMetrics{})
+	ctx := context.Background()
+
+	cached, err := orderRepo.GetOrder(ctx, "order1")
+	require.NoError(t, err, "the first read puts the order into the cache")
+	cached.Status = domain.OrderStatusReturned
+
+	assert.ErrorIs(t, uc.DeliverOrders(ctx, "recipient1", []string{"order1"}), errUpdateFailed)
+
+	// Без строк в базе заказ может прийти только из кэша
+	testOrders.setRows()
+	order, err := orderRepo.GetOrder(ctx, "order1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusNew, order.Status, "neither the caller nor a failed delivery may change the cached order")
+	assert.False(t, order.DeliveryDate.Valid)
+}
+
+func TestOrderUseCase_AddOrders(t *testing.T) {
+	store := memory.NewStore()
+	metrics := &stubMetrics{}
+	orderRepo := memory.NewOrderRepository(store)
+	uc := usecase.NewOrderUseCase(orderRepo, memory.NewReturnRepository(store), memory.NewTxManager(store), metrics)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, uc.AddOrders(ctx, []*dto.AddOrderDTO{
+		addOrderRequest("order1", "recipient1"),
+		{OrderID: "order2"},
+	}), domain.ErrInvalidInput)
+	require.NoError(t, uc.AddOrder(ctx, addOrderRequest("order2", "recipient1")))
+	assert.ErrorIs(t, uc.AddOrders(ctx, []*dto.AddOrderDTO{
+		addOrderRequest("order1", "recipient1"),
+		addOrderRequest("order2", "recipient1"),
+	}), domain.ErrOrderAlreadyExists)
+	_, err := orderRepo.GetOrder(ctx, "order1")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+
+	require.NoError(t, uc.AddOrders(ctx, []*dto.AddOrderDTO{
+		addOrderRequest("order1", "recipient1"),
+		addOrderRequest("order3", "recipient1"),
+	}))
+	assert.Equal(t, 3, metrics.ordersServed)
+}
+
+func TestOrderUseCase_AcceptReturn(t *testing.T) {
+	store := memory.NewStore()
+	orderRepo := memory.NewOrderRepository(store)
+	uc := usecase.NewOrderUseCase(orderRepo, memory.NewReturnRepository(store), memory.NewTxManager(store), &stubMetrics{})
+	ctx := context.Background()
+
+	require.NoError(t, uc.AddOrder(ctx, addOrderRequest("order1", "recipient1")))
+	version, err := uc.AcceptReturn(ctx, "recipient1", "order1", 0)
+	require.NoError(t, err)
+
+	order, err := orderRepo.GetOrder(ctx, "order1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusReturned, order.Status)
+	assert.Equal(t, int64(2), version)
+	assert.Equal(t, version, order.Version)
+
+	returns, _, err := uc.GetReturns(ctx, 10, "")
+	require.NoError(t, err)
+	require.Len(t, returns, 1)
+	assert.Equal(t, "order1", returns[0].OrderID)
+}
+
+func TestOrderUseCase_AcceptReturnRollsBack(t *testing.T) {
+	store := memory.NewStore()
+	orderRepo := memory.NewOrderRepository(store)
+	returnRepo := failingReturnRepository{memory.NewReturnRepository(store)}
+	uc := usecase.NewOrderUseCase(orderRepo, returnRepo, memory.NewTxManager(store), &stubMetrics{})
+	ctx := context.Background()
+
+	require.NoError(t, uc.AddOrder(ctx, addOrderRequest("order1", "recipient1")))
+	_, err := uc.AcceptReturn(ctx, "recipient1", "order1", 0)
+	assert.ErrorIs(t, err, errReturnFailed)
+
+	order, err := orderRepo.GetOrder(ctx, "order1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusNew, order.Status, "the status update must be rolled back with the failed return")
+	assert.False(t, order.ReturnDate.Valid)
+}
+
+func TestOrderUseCase_VersionConflicts(t *testing.T) {
+	store := memory.NewStore()
+	orderRepo := memory.NewOrderRepository(store)
+	uc := usecase.NewOrderUseCase(orderRepo, memory.NewReturnRepository(store), memory.NewTxManager(store), &stubMetrics{})
+	ctx := context.Background()
+
+	require.NoError(t, uc.AddOrder(ctx, addOrderRequest("order1", "recipient1")))
+	require.NoError(t, uc.AddOrder(ctx, addOrderRequest("order2", "recipient1")))
+
+	// Оператор прочитал заказ, а другой успел его изменить
+	stale, err := orderRepo.GetOrder(ctx, "order1")
+	require.NoError(t, err)
+	require.NoError(t, uc.DeliverOrders(ctx, "recipient1", []string{"order1"}))
+
+	stale.Status = domain.OrderStatusReturned
+	assert.ErrorIs(t, orderRepo.UpdateOrder(ctx, stale), domain.ErrOrderVersionConflict)
+
+	_, err = uc.AcceptReturn(ctx, "recipient1", "order1", stale.Version)
+	assert.ErrorIs(t, err, domain.ErrOrderVersionConflict)
+	version, err := uc.AcceptReturn(ctx, "recipient1", "order1", stale.Version+1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+
+	assert.ErrorIs(t, uc.RemoveOrder(ctx, "order2", 5, ""), domain.ErrOrderVersionConflict)
+	require.NoError(t, uc.RemoveOrder(ctx, "order2", 1, "recipient refused"))
+	_, err = orderRepo.GetOrder(ctx, "order2")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+}
+
+func TestOrderUseCase_GetOrdersPaging(t *testing.T) {
+	store := memory.NewStore()
+	uc := usecase.NewOrderUseCase(memory.NewOrderRepository(store), memory.NewReturnRepository(store), memory.NewTxManager(store), &stubMetrics{})
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, uc.AddOrder(ctx, addOrderRequest(fmt.Sprintf("order%d", i), "recipient1")))
+	}
+
+	var seen []string
+	pageToken := ""
+	for {
+		orders, next, err := uc.GetOrders(ctx, "recipient1", 2, pageToken)
+		require.NoError(t, err)
+		for _, order := range orders {
+			seen = append(seen, order.OrderID)
+		}
+		if next == "" {
+			break
+		}
+		pageToken = next
+	}
+	assert.ElementsMatch(t, []string{"order0", "order1", "order2", "order3", "order4"}, seen)
+	assert.Len(t, seen, 5)
+}
+
+func TestOrderUseCase_ListOrders(t *testing.T) {
+	store := memory.NewStore()
+	uc := usecase.NewOrderUseCase(memory.NewOrderRepository(store), memory.NewReturnRepository(store), memory.NewTxManager(store), &stubMetrics{})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, uc.AddOrder(ctx, addOrderRequest(fmt.Sprintf("order%d", i), "recipient1")))
+	}
+	filter := domain.OrderFilter{RecipientID: "recipient1"}
+
+	t.Run("Inverted expiry range is rejected", func(t *testing.T) {
+		now := time.Now()
+		_, _, err := uc.ListOrders(ctx, domain.OrderFilter{ExpiryFrom: now, ExpiryTo: now.Add(-time.Hour)}, 10, "")
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+
+		_, _, err = uc.ListOrders(ctx, domain.OrderFilter{ExpiryFrom: now, ExpiryTo: now}, 10, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Page token is bound to its filter", func(t *testing.T) {
+		_, next, err := uc.ListOrders(ctx, filter, 1, "")
+		require.NoError(t, err)
+		require.NotEmpty(t, next)
+
+		orders, _, err := uc.ListOrders(ctx, filter, 1, next)
+		require.NoError(t, err)
+		assert.Len(t, orders, 1)
+
+		_, _, err = uc.ListOrders(ctx, domain.OrderFilter{RecipientID: "recipient2"}, 1, next)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		_, _, err = uc.ListOrders(ctx, domain.OrderFilter{RecipientID: "recipient1", InStorage: true}, 1, next)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("Malformed page token is rejected", func(t *testing.T) {
+		_, _, err := uc.ListOrders(ctx, filter, 1, "not a token")
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"gitlab.ozon.dev/ashadkhamov/homework/internal/domain"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+func normalizePageSize(pageSize int) int {
+	if pageSize <= 0 {
+		return defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		return maxPageSize
+	}
+	return pageSize
+}
+
+// encodePageToken упаковывает курсор в непрозрачную для клиента строку
+func encodePageToken(cursor any) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode page token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// orderPageToken — курсор страницы заказов вместе с отпечатком выборки, для которой он выдан
+type orderPageToken struct {
+	domain.OrderCursor
+	Query string `json:"query,omitempty"`
+}
+
+// filterFingerprint возвращает короткий отпечаток фильтра для привязки к нему токена страницы
+func filterFingerprint(filter domain.OrderFilter) (string, error) {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode filter: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:12]), nil
+}
+
+func decodePageToken(token string, cursor any) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return fmt.Errorf("%w: malformed page token", domain.ErrInvalidInput)
+	}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return fmt.Errorf("%w: malformed page token", domain.ErrInvalidInput)
+	}
+	return nil
+}
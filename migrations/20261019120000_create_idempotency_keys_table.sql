@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key TEXT NOT NULL,
+    method TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    status_code INTEGER,
+    status_message TEXT NOT NULL DEFAULT '',
+    response BYTEA,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (idempotency_key, method)
+    );
+
+-- +goose Down
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS accepted_at TIMESTAMP NOT NULL DEFAULT now();
+CREATE INDEX IF NOT EXISTS orders_recipient_accepted_at_idx ON orders (recipient_id, accepted_at DESC, order_id DESC);
+CREATE INDEX IF NOT EXISTS returns_return_date_idx ON returns (return_date DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS returns_return_date_idx;
+DROP INDEX IF EXISTS orders_recipient_accepted_at_idx;
+ALTER TABLE orders DROP COLUMN IF EXISTS accepted_at;
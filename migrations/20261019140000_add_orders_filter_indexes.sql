@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS orders_status_accepted_at_idx ON orders (status, accepted_at DESC, order_id DESC);
+CREATE INDEX IF NOT EXISTS orders_expiry_date_idx ON orders (expiry_date);
+CREATE INDEX IF NOT EXISTS orders_order_id_prefix_idx ON orders (order_id text_pattern_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS orders_order_id_prefix_idx;
+DROP INDEX IF EXISTS orders_expiry_date_idx;
+DROP INDEX IF EXISTS orders_status_accepted_at_idx;
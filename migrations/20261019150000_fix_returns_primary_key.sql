@@ -0,0 +1,16 @@
+-- +goose Up
+-- return_id никогда не заполнялся при вставке, а код работает с числовым id
+ALTER TABLE returns DROP CONSTRAINT IF EXISTS returns_pkey;
+ALTER TABLE returns DROP COLUMN IF EXISTS return_id;
+ALTER TABLE returns ADD COLUMN IF NOT EXISTS id BIGSERIAL PRIMARY KEY;
+DROP INDEX IF EXISTS returns_return_date_idx;
+CREATE INDEX IF NOT EXISTS returns_return_date_id_idx ON returns (return_date DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS returns_return_date_id_idx;
+CREATE INDEX IF NOT EXISTS returns_return_date_idx ON returns (return_date DESC);
+ALTER TABLE returns ADD COLUMN IF NOT EXISTS return_id TEXT;
+UPDATE returns SET return_id = id::text;
+ALTER TABLE returns DROP CONSTRAINT IF EXISTS returns_pkey;
+ALTER TABLE returns DROP COLUMN IF EXISTS id;
+ALTER TABLE returns ADD PRIMARY KEY (return_id);
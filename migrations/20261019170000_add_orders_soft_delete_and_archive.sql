@@ -0,0 +1,46 @@
+-- +goose Up
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP;
+UPDATE orders SET updated_at = GREATEST(accepted_at, delivery_date, return_date) WHERE updated_at IS NULL;
+ALTER TABLE orders ALTER COLUMN updated_at SET DEFAULT now();
+ALTER TABLE orders ALTER COLUMN updated_at SET NOT NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS removal_reason TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS orders_closed_updated_at_idx ON orders (updated_at) WHERE deleted_at IS NOT NULL OR status = 'delivered';
+
+CREATE TABLE IF NOT EXISTS orders_archive (
+    id BIGSERIAL PRIMARY KEY,
+    order_id TEXT NOT NULL,
+    recipient_id TEXT NOT NULL,
+    expiry_date DATE NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    delivery_date TIMESTAMP,
+    return_date TIMESTAMP,
+    weight REAL NOT NULL,
+    cost REAL NOT NULL,
+    packaging_type VARCHAR(50) NOT NULL,
+    accepted_at TIMESTAMP NOT NULL,
+    version BIGINT NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+    removal_reason TEXT NOT NULL,
+    archived_at TIMESTAMP NOT NULL
+    );
+CREATE INDEX IF NOT EXISTS orders_archive_order_id_idx ON orders_archive (order_id, archived_at DESC);
+CREATE INDEX IF NOT EXISTS orders_archive_recipient_accepted_at_idx ON orders_archive (recipient_id, accepted_at DESC, order_id DESC);
+
+CREATE TABLE IF NOT EXISTS returns_archive (
+    id BIGINT PRIMARY KEY,
+    order_id TEXT NOT NULL,
+    recipient_id VARCHAR(255) NOT NULL,
+    return_date DATE NOT NULL,
+    archived_at TIMESTAMP NOT NULL
+    );
+CREATE INDEX IF NOT EXISTS returns_archive_order_id_idx ON returns_archive (order_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS returns_archive;
+DROP TABLE IF EXISTS orders_archive;
+DROP INDEX IF EXISTS orders_closed_updated_at_idx;
+ALTER TABLE orders DROP COLUMN IF EXISTS removal_reason;
+ALTER TABLE orders DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS updated_at;
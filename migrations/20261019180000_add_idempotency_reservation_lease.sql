@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS reservation_token TEXT NOT NULL DEFAULT '';
+-- Резервирования, сделанные до появления срока, считаются брошенными сразу
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS reserved_until TIMESTAMP NOT NULL DEFAULT now();
+
+-- +goose Down
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS reserved_until;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS reservation_token;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS response_headers BYTEA;
+
+-- +goose Down
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS response_headers;
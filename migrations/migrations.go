@@ -0,0 +1,9 @@
+// Package migrations встраивает SQL-миграции в бинарник сервера
+package migrations
+
+import "embed"
+
+// FS содержит миграции в формате goose
+//
+//go:embed *.sql
+var FS embed.FS
@@ -21,9 +21,11 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	OrderService_AddOrder_FullMethodName      = "/order_service.v1.OrderService/AddOrder"
+	OrderService_AddOrders_FullMethodName     = "/order_service.v1.OrderService/AddOrders"
 	OrderService_RemoveOrder_FullMethodName   = "/order_service.v1.OrderService/RemoveOrder"
 	OrderService_DeliverOrders_FullMethodName = "/order_service.v1.OrderService/DeliverOrders"
 	OrderService_GetOrders_FullMethodName     = "/order_service.v1.OrderService/GetOrders"
+	OrderService_ListOrders_FullMethodName    = "/order_service.v1.OrderService/ListOrders"
 	OrderService_AcceptReturn_FullMethodName  = "/order_service.v1.OrderService/AcceptReturn"
 	OrderService_GetReturns_FullMethodName    = "/order_service.v1.OrderService/GetReturns"
 )
@@ -33,9 +35,12 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type OrderServiceClient interface {
 	AddOrder(ctx context.Context, in *AddOrderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// AddOrders принимает пачку заказов: если хотя бы один некорректен или уже есть, не принимается ни один
+	AddOrders(ctx context.Context, in *AddOrdersRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	RemoveOrder(ctx context.Context, in *RemoveOrderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	DeliverOrders(ctx context.Context, in *DeliverOrdersRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	GetOrders(ctx context.Context, in *GetOrdersRequest, opts ...grpc.CallOption) (*GetOrdersResponse, error)
+	ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error)
 	AcceptReturn(ctx context.Context, in *AcceptReturnRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	GetReturns(ctx context.Context, in *GetReturnsRequest, opts ...grpc.CallOption) (*GetReturnsResponse, error)
 }
@@ -58,6 +63,16 @@ func (c *orderServiceClient) AddOrder(ctx context.Context, in *AddOrderRequest,
 	return out, nil
 }
 
+func (c *orderServiceClient) AddOrders(ctx context.Context, in *AddOrdersRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, OrderService_AddOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orderServiceClient) RemoveOrder(ctx context.Context, in *RemoveOrderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -88,6 +103,16 @@ func (c *orderServiceClient) GetOrders(ctx context.Context, in *GetOrdersRequest
 	return out, nil
 }
 
+func (c *orderServiceClient) ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrdersResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orderServiceClient) AcceptReturn(ctx context.Context, in *AcceptReturnRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -113,9 +138,12 @@ func (c *orderServiceClient) GetReturns(ctx context.Context, in *GetReturnsReque
 // for forward compatibility.
 type OrderServiceServer interface {
 	AddOrder(context.Context, *AddOrderRequest) (*emptypb.Empty, error)
+	// AddOrders принимает пачку заказов: если хотя бы один некорректен или уже есть, не принимается ни один
+	AddOrders(context.Context, *AddOrdersRequest) (*emptypb.Empty, error)
 	RemoveOrder(context.Context, *RemoveOrderRequest) (*emptypb.Empty, error)
 	DeliverOrders(context.Context, *DeliverOrdersRequest) (*emptypb.Empty, error)
 	GetOrders(context.Context, *GetOrdersRequest) (*GetOrdersResponse, error)
+	ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error)
 	AcceptReturn(context.Context, *AcceptReturnRequest) (*emptypb.Empty, error)
 	GetReturns(context.Context, *GetReturnsRequest) (*GetReturnsResponse, error)
 	mustEmbedUnimplementedOrderServiceServer()
@@ -131,6 +159,9 @@ type UnimplementedOrderServiceServer struct{}
 func (UnimplementedOrderServiceServer) AddOrder(context.Context, *AddOrderRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddOrder not implemented")
 }
+func (UnimplementedOrderServiceServer) AddOrders(context.Context, *AddOrdersRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOrders not implemented")
+}
 func (UnimplementedOrderServiceServer) RemoveOrder(context.Context, *RemoveOrderRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveOrder not implemented")
 }
@@ -140,6 +171,9 @@ func (UnimplementedOrderServiceServer) DeliverOrders(context.Context, *DeliverOr
 func (UnimplementedOrderServiceServer) GetOrders(context.Context, *GetOrdersRequest) (*GetOrdersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetOrders not implemented")
 }
+func (UnimplementedOrderServiceServer) ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrders not implemented")
+}
 func (UnimplementedOrderServiceServer) AcceptReturn(context.Context, *AcceptReturnRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AcceptReturn not implemented")
 }
@@ -185,6 +219,24 @@ func _OrderService_AddOrder_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_AddOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).AddOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_AddOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).AddOrders(ctx, req.(*AddOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrderService_RemoveOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RemoveOrderRequest)
 	if err := dec(in); err != nil {
@@ -239,6 +291,24 @@ func _OrderService_GetOrders_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListOrders(ctx, req.(*ListOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrderService_AcceptReturn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AcceptReturnRequest)
 	if err := dec(in); err != nil {
@@ -286,6 +356,10 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddOrder",
 			Handler:    _OrderService_AddOrder_Handler,
 		},
+		{
+			MethodName: "AddOrders",
+			Handler:    _OrderService_AddOrders_Handler,
+		},
 		{
 			MethodName: "RemoveOrder",
 			Handler:    _OrderService_RemoveOrder_Handler,
@@ -298,6 +372,10 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetOrders",
 			Handler:    _OrderService_GetOrders_Handler,
 		},
+		{
+			MethodName: "ListOrders",
+			Handler:    _OrderService_ListOrders_Handler,
+		},
 		{
 			MethodName: "AcceptReturn",
 			Handler:    _OrderService_AcceptReturn_Handler,
@@ -310,3 +388,329 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/order_service/v1/order_service.proto",
 }
+
+const (
+	CacheAdminService_GetCacheStats_FullMethodName   = "/order_service.v1.CacheAdminService/GetCacheStats"
+	CacheAdminService_InspectCacheKey_FullMethodName = "/order_service.v1.CacheAdminService/InspectCacheKey"
+	CacheAdminService_FlushCache_FullMethodName      = "/order_service.v1.CacheAdminService/FlushCache"
+)
+
+// CacheAdminServiceClient is the client API for CacheAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Диагностика кэша заказов. Доступен только по gRPC, через HTTP-шлюз не публикуется
+type CacheAdminServiceClient interface {
+	GetCacheStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CacheStats, error)
+	InspectCacheKey(ctx context.Context, in *InspectCacheKeyRequest, opts ...grpc.CallOption) (*InspectCacheKeyResponse, error)
+	FlushCache(ctx context.Context, in *FlushCacheRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type cacheAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCacheAdminServiceClient(cc grpc.ClientConnInterface) CacheAdminServiceClient {
+	return &cacheAdminServiceClient{cc}
+}
+
+func (c *cacheAdminServiceClient) GetCacheStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CacheStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CacheStats)
+	err := c.cc.Invoke(ctx, CacheAdminService_GetCacheStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheAdminServiceClient) InspectCacheKey(ctx context.Context, in *InspectCacheKeyRequest, opts ...grpc.CallOption) (*InspectCacheKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InspectCacheKeyResponse)
+	err := c.cc.Invoke(ctx, CacheAdminService_InspectCacheKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheAdminServiceClient) FlushCache(ctx context.Context, in *FlushCacheRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, CacheAdminService_FlushCache_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CacheAdminServiceServer is the server API for CacheAdminService service.
+// All implementations must embed UnimplementedCacheAdminServiceServer
+// for forward compatibility.
+//
+// Диагностика кэша заказов. Доступен только по gRPC, через HTTP-шлюз не публикуется
+type CacheAdminServiceServer interface {
+	GetCacheStats(context.Context, *emptypb.Empty) (*CacheStats, error)
+	InspectCacheKey(context.Context, *InspectCacheKeyRequest) (*InspectCacheKeyResponse, error)
+	FlushCache(context.Context, *FlushCacheRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedCacheAdminServiceServer()
+}
+
+// UnimplementedCacheAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCacheAdminServiceServer struct{}
+
+func (UnimplementedCacheAdminServiceServer) GetCacheStats(context.Context, *emptypb.Empty) (*CacheStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCacheStats not implemented")
+}
+func (UnimplementedCacheAdminServiceServer) InspectCacheKey(context.Context, *InspectCacheKeyRequest) (*InspectCacheKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InspectCacheKey not implemented")
+}
+func (UnimplementedCacheAdminServiceServer) FlushCache(context.Context, *FlushCacheRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushCache not implemented")
+}
+func (UnimplementedCacheAdminServiceServer) mustEmbedUnimplementedCacheAdminServiceServer() {}
+func (UnimplementedCacheAdminServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeCacheAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CacheAdminServiceServer will
+// result in compilation errors.
+type UnsafeCacheAdminServiceServer interface {
+	mustEmbedUnimplementedCacheAdminServiceServer()
+}
+
+func RegisterCacheAdminServiceServer(s grpc.ServiceRegistrar, srv CacheAdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCacheAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CacheAdminService_ServiceDesc, srv)
+}
+
+func _CacheAdminService_GetCacheStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheAdminServiceServer).GetCacheStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheAdminService_GetCacheStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheAdminServiceServer).GetCacheStats(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheAdminService_InspectCacheKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectCacheKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheAdminServiceServer).InspectCacheKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheAdminService_InspectCacheKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheAdminServiceServer).InspectCacheKey(ctx, req.(*InspectCacheKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheAdminService_FlushCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheAdminServiceServer).FlushCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheAdminService_FlushCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheAdminServiceServer).FlushCache(ctx, req.(*FlushCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CacheAdminService_ServiceDesc is the grpc.ServiceDesc for CacheAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CacheAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "order_service.v1.CacheAdminService",
+	HandlerType: (*CacheAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCacheStats",
+			Handler:    _CacheAdminService_GetCacheStats_Handler,
+		},
+		{
+			MethodName: "InspectCacheKey",
+			Handler:    _CacheAdminService_InspectCacheKey_Handler,
+		},
+		{
+			MethodName: "FlushCache",
+			Handler:    _CacheAdminService_FlushCache_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/order_service/v1/order_service.proto",
+}
+
+const (
+	ArchiveAdminService_GetArchivedOrder_FullMethodName   = "/order_service.v1.ArchiveAdminService/GetArchivedOrder"
+	ArchiveAdminService_ListArchivedOrders_FullMethodName = "/order_service.v1.ArchiveAdminService/ListArchivedOrders"
+)
+
+// ArchiveAdminServiceClient is the client API for ArchiveAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ArchiveAdminService даёт администраторам доступ к архиву удалённых и выданных заказов
+type ArchiveAdminServiceClient interface {
+	GetArchivedOrder(ctx context.Context, in *GetArchivedOrderRequest, opts ...grpc.CallOption) (*ArchivedOrder, error)
+	ListArchivedOrders(ctx context.Context, in *ListArchivedOrdersRequest, opts ...grpc.CallOption) (*ListArchivedOrdersResponse, error)
+}
+
+type archiveAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewArchiveAdminServiceClient(cc grpc.ClientConnInterface) ArchiveAdminServiceClient {
+	return &archiveAdminServiceClient{cc}
+}
+
+func (c *archiveAdminServiceClient) GetArchivedOrder(ctx context.Context, in *GetArchivedOrderRequest, opts ...grpc.CallOption) (*ArchivedOrder, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArchivedOrder)
+	err := c.cc.Invoke(ctx, ArchiveAdminService_GetArchivedOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *archiveAdminServiceClient) ListArchivedOrders(ctx context.Context, in *ListArchivedOrdersRequest, opts ...grpc.CallOption) (*ListArchivedOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListArchivedOrdersResponse)
+	err := c.cc.Invoke(ctx, ArchiveAdminService_ListArchivedOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ArchiveAdminServiceServer is the server API for ArchiveAdminService service.
+// All implementations must embed UnimplementedArchiveAdminServiceServer
+// for forward compatibility.
+//
+// ArchiveAdminService даёт администраторам доступ к архиву удалённых и выданных заказов
+type ArchiveAdminServiceServer interface {
+	GetArchivedOrder(context.Context, *GetArchivedOrderRequest) (*ArchivedOrder, error)
+	ListArchivedOrders(context.Context, *ListArchivedOrdersRequest) (*ListArchivedOrdersResponse, error)
+	mustEmbedUnimplementedArchiveAdminServiceServer()
+}
+
+// UnimplementedArchiveAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedArchiveAdminServiceServer struct{}
+
+func (UnimplementedArchiveAdminServiceServer) GetArchivedOrder(context.Context, *GetArchivedOrderRequest) (*ArchivedOrder, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArchivedOrder not implemented")
+}
+func (UnimplementedArchiveAdminServiceServer) ListArchivedOrders(context.Context, *ListArchivedOrdersRequest) (*ListArchivedOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListArchivedOrders not implemented")
+}
+func (UnimplementedArchiveAdminServiceServer) mustEmbedUnimplementedArchiveAdminServiceServer() {}
+func (UnimplementedArchiveAdminServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeArchiveAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ArchiveAdminServiceServer will
+// result in compilation errors.
+type UnsafeArchiveAdminServiceServer interface {
+	mustEmbedUnimplementedArchiveAdminServiceServer()
+}
+
+func RegisterArchiveAdminServiceServer(s grpc.ServiceRegistrar, srv ArchiveAdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedArchiveAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ArchiveAdminService_ServiceDesc, srv)
+}
+
+func _ArchiveAdminService_GetArchivedOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArchivedOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArchiveAdminServiceServer).GetArchivedOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArchiveAdminService_GetArchivedOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArchiveAdminServiceServer).GetArchivedOrder(ctx, req.(*GetArchivedOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArchiveAdminService_ListArchivedOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListArchivedOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArchiveAdminServiceServer).ListArchivedOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArchiveAdminService_ListArchivedOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArchiveAdminServiceServer).ListArchivedOrders(ctx, req.(*ListArchivedOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ArchiveAdminService_ServiceDesc is the grpc.ServiceDesc for ArchiveAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ArchiveAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "order_service.v1.ArchiveAdminService",
+	HandlerType: (*ArchiveAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetArchivedOrder",
+			Handler:    _ArchiveAdminService_GetArchivedOrder_Handler,
+		},
+		{
+			MethodName: "ListArchivedOrders",
+			Handler:    _ArchiveAdminService_ListArchivedOrders_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/order_service/v1/order_service.proto",
+}